@@ -0,0 +1,192 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Aggregation represents an aggregation of a search request, built with the *Agg functions
+type Aggregation struct {
+	kind   string
+	params map[string]interface{}
+	subs   Aggregations
+}
+
+// Aggregations represents the named aggregations of a search request, marshaled as the "aggs" object
+type Aggregations map[string]*Aggregation
+
+// NewAggregation creates an aggregation of any kind, for the ones without a dedicated builder
+func NewAggregation(kind string) *Aggregation {
+	return &Aggregation{kind: kind, params: map[string]interface{}{}}
+}
+
+// TermsAgg creates a terms aggregation returning the size most frequent values of field
+func TermsAgg(field string, size int) *Aggregation {
+	return NewAggregation("terms").Param("field", field).Param("size", size)
+}
+
+// DateHistogramAgg creates a date_histogram aggregation with a calendar interval (1d, 1M...)
+func DateHistogramAgg(field, calendarInterval string) *Aggregation {
+	return NewAggregation("date_histogram").Param("field", field).Param("calendar_interval", calendarInterval)
+}
+
+// HistogramAgg creates a histogram aggregation with a fixed interval
+func HistogramAgg(field string, interval float64) *Aggregation {
+	return NewAggregation("histogram").Param("field", field).Param("interval", interval)
+}
+
+// CardinalityAgg creates a cardinality aggregation counting the distinct values of field
+func CardinalityAgg(field string) *Aggregation {
+	return NewAggregation("cardinality").Param("field", field)
+}
+
+// AvgAgg creates an avg aggregation
+func AvgAgg(field string) *Aggregation {
+	return NewAggregation("avg").Param("field", field)
+}
+
+// SumAgg creates a sum aggregation
+func SumAgg(field string) *Aggregation {
+	return NewAggregation("sum").Param("field", field)
+}
+
+// MinAgg creates a min aggregation
+func MinAgg(field string) *Aggregation {
+	return NewAggregation("min").Param("field", field)
+}
+
+// MaxAgg creates a max aggregation
+func MaxAgg(field string) *Aggregation {
+	return NewAggregation("max").Param("field", field)
+}
+
+// Param sets a parameter of the aggregation, such as order, min_doc_count or missing
+func (a *Aggregation) Param(key string, value interface{}) *Aggregation {
+	a.params[key] = value
+	return a
+}
+
+// SubAgg adds a sub-aggregation computed for every bucket of the aggregation
+func (a *Aggregation) SubAgg(name string, sub *Aggregation) *Aggregation {
+	if a.subs == nil {
+		a.subs = Aggregations{}
+	}
+	a.subs[name] = sub
+	return a
+}
+
+// MarshalJSON encodes the aggregation as expected in the aggs object of a search request
+func (a *Aggregation) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{a.kind: a.params}
+	if len(a.subs) > 0 {
+		body["aggs"] = a.subs
+	}
+	return json.Marshal(body)
+}
+
+// AggregationResults represents the named aggregations of a search result or of a bucket
+type AggregationResults map[string]json.RawMessage
+
+// BucketAggResult represents the result of a bucket aggregation (terms, histogram, date_histogram)
+type BucketAggResult struct {
+	DocCountErrorUpperBound int64    `json:"doc_count_error_upper_bound"`
+	SumOtherDocCount        int64    `json:"sum_other_doc_count"`
+	Buckets                 []Bucket `json:"buckets"`
+}
+
+// Bucket represents a bucket of a bucket aggregation, with its sub-aggregations
+type Bucket struct {
+	Key          interface{}        `json:"key"`
+	KeyAsString  string             `json:"key_as_string"`
+	DocCount     int64              `json:"doc_count"`
+	Aggregations AggregationResults `json:"-"`
+}
+
+// MetricAggResult represents the result of a single value metric aggregation (avg, sum, min, max, cardinality).
+// Value is nil when no document has a value for the field.
+type MetricAggResult struct {
+	Value         *float64 `json:"value"`
+	ValueAsString string   `json:"value_as_string"`
+}
+
+// UnmarshalJSON decodes a bucket, every object besides the bucket fields is a sub-aggregation
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	type bucket Bucket
+	if err := json.Unmarshal(data, (*bucket)(b)); err != nil {
+		return err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	b.Aggregations = AggregationResults{}
+	for name, value := range fields {
+		if len(value) > 0 && value[0] == '{' {
+			b.Aggregations[name] = value
+		}
+	}
+	return nil
+}
+
+// Buckets decodes a bucket aggregation (terms, histogram, date_histogram) by name
+func (a AggregationResults) Buckets(name string) (*BucketAggResult, error) {
+	result := &BucketAggResult{}
+	return result, a.decode(name, result)
+}
+
+// Metric decodes a single value metric aggregation (avg, sum, min, max, cardinality) by name
+func (a AggregationResults) Metric(name string) (*MetricAggResult, error) {
+	result := &MetricAggResult{}
+	return result, a.decode(name, result)
+}
+
+func (a AggregationResults) decode(name string, v interface{}) error {
+	raw, found := a[name]
+	if !found {
+		return fmt.Errorf("aggregation %s not found", name)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// ParseAggregations decodes the aggregations of a search result
+func ParseAggregations(result *SearchResult) (AggregationResults, error) {
+	aggregations := AggregationResults{}
+	if len(result.Aggregations) == 0 {
+		return aggregations, nil
+	}
+	err := json.Unmarshal(result.Aggregations, &aggregations)
+	return aggregations, err
+}
+
+// ParseTermsAgg decodes a terms aggregation of a search result
+func ParseTermsAgg(result *SearchResult, name string) (*BucketAggResult, error) {
+	return parseBucketAgg(result, name)
+}
+
+// ParseHistogramAgg decodes a histogram aggregation of a search result
+func ParseHistogramAgg(result *SearchResult, name string) (*BucketAggResult, error) {
+	return parseBucketAgg(result, name)
+}
+
+// ParseDateHistogramAgg decodes a date_histogram aggregation of a search result
+func ParseDateHistogramAgg(result *SearchResult, name string) (*BucketAggResult, error) {
+	return parseBucketAgg(result, name)
+}
+
+// ParseMetricAgg decodes an avg, sum, min, max or cardinality aggregation of a search result
+func ParseMetricAgg(result *SearchResult, name string) (*MetricAggResult, error) {
+	aggregations, err := ParseAggregations(result)
+	if err != nil {
+		return nil, err
+	}
+	return aggregations.Metric(name)
+}
+
+func parseBucketAgg(result *SearchResult, name string) (*BucketAggResult, error) {
+	aggregations, err := ParseAggregations(result)
+	if err != nil {
+		return nil, err
+	}
+	return aggregations.Buckets(name)
+}
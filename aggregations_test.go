@@ -0,0 +1,53 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestAggregationsBuilder(t *testing.T) {
+	helper := Test{}
+	aggs := elasticsearch.Aggregations{
+		"colors": elasticsearch.TermsAgg("Colors", 10).SubAgg("avg_price", elasticsearch.AvgAgg("Price")),
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"size": 0, "aggs": aggs})
+	helper.OK(t, err)
+	helper.Equals(t, `{"aggs":{"colors":{"aggs":{"avg_price":{"avg":{"field":"Price"}}},"terms":{"field":"Colors","size":10}}},"size":0}`, string(body))
+}
+
+func TestParseAggregations(t *testing.T) {
+	helper := Test{}
+	result := &elasticsearch.SearchResult{}
+	err := json.Unmarshal([]byte(`{"aggregations":{
+		"colors":{"doc_count_error_upper_bound":0,"sum_other_doc_count":0,"buckets":[
+			{"key":"red","doc_count":2,"avg_price":{"value":15.5}},
+			{"key":"blue","doc_count":1,"avg_price":{"value":null}}
+		]},
+		"products":{"value":3}
+	}}`), result)
+	helper.OK(t, err)
+
+	colors, err := elasticsearch.ParseTermsAgg(result, "colors")
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(colors.Buckets))
+	helper.Equals(t, "red", colors.Buckets[0].Key)
+	helper.Equals(t, int64(2), colors.Buckets[0].DocCount)
+
+	price, err := colors.Buckets[0].Aggregations.Metric("avg_price")
+	helper.OK(t, err)
+	helper.Equals(t, 15.5, *price.Value)
+
+	price, err = colors.Buckets[1].Aggregations.Metric("avg_price")
+	helper.OK(t, err)
+	helper.Assert(t, price.Value == nil, "A missing value must be nil")
+
+	products, err := elasticsearch.ParseMetricAgg(result, "products")
+	helper.OK(t, err)
+	helper.Equals(t, float64(3), *products.Value)
+
+	_, err = elasticsearch.ParseTermsAgg(result, "sizes")
+	helper.Assert(t, err != nil, "A missing aggregation must fail")
+}
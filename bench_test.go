@@ -0,0 +1,73 @@
+package elasticsearch_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func benchmarkServer(response []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(response)
+	}))
+}
+
+func BenchmarkSearch(b *testing.B) {
+	var response bytes.Buffer
+	response.WriteString(`{"took":3,"hits":{"total":{"value":500,"relation":"eq"},"hits":[`)
+	for i := 0; i < 500; i++ {
+		if i > 0 {
+			response.WriteByte(',')
+		}
+		fmt.Fprintf(&response, `{"_index":"test","_id":"%d","_score":1.0,"_source":{"Name":"product %d","Colors":["blue","red"]}}`, i, i)
+	}
+	response.WriteString(`]}}`)
+	server := benchmarkServer(response.Bytes())
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	query := `{"query":{"match_all":{}},"size":500}`
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Search(IndexName, "", query, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBulk(b *testing.B) {
+	var response, payload bytes.Buffer
+	response.WriteString(`{"took":30,"errors":false,"items":[`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			response.WriteByte(',')
+		}
+		fmt.Fprintf(&response, `{"index":{"_index":"test","_id":"%d","_version":1,"result":"created","status":201,"_seq_no":%d,"_primary_term":1}}`, i, i)
+		fmt.Fprintf(&payload, "{\"index\":{\"_id\":\"%d\"}}\n{\"Name\":\"product %d\"}\n", i, i)
+	}
+	response.WriteString(`]}`)
+	server := benchmarkServer(response.Bytes())
+	defer server.Close()
+
+	for _, compression := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compression=%v", compression), func(b *testing.B) {
+			opts := []elasticsearch.ClientOption{}
+			if compression {
+				opts = append(opts, elasticsearch.WithRequestCompression(1024))
+			}
+			client := elasticsearch.NewClientFromUrl(server.URL, opts...)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.Bulk(IndexName, payload.Bytes()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
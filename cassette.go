@@ -0,0 +1,197 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// CassetteMode sets whether a cassette sends the requests to Elasticsearch or replays the recorded responses
+type CassetteMode int
+
+const (
+	// CassetteAuto replays the cassette if its file exists, and records it otherwise
+	CassetteAuto CassetteMode = iota
+	// CassetteRecord sends every request and records the interactions, overwriting the file
+	CassetteRecord
+	// CassetteReplay replays the recorded interactions, a request which hasn't been recorded failing
+	CassetteReplay
+)
+
+// Interaction represents a request and its response recorded on a cassette
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"` // path and query string
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int                 `json:"status"`
+		Header map[string][]string `json:"header,omitempty"`
+		Body   string              `json:"body"`
+	} `json:"response"`
+}
+
+// Cassette is a transport recording the requests sent to Elasticsearch and their responses in a JSON
+// file, and replaying them in the following runs, so that integration tests don't need a cluster once
+// recorded. The requests are matched by method, path, query string and body, the interactions of
+// identical requests being replayed in the order they were recorded.
+//
+//	cassette, err := elasticsearch.NewCassette("testdata/search.json", elasticsearch.CassetteAuto, nil)
+//	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithTransport(cassette))
+type Cassette struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+
+	mutex        sync.Mutex
+	interactions []Interaction
+	replayed     map[int]bool
+}
+
+// NewCassette loads the cassette of the file unless it's recorded. The requests are recorded through
+// transport, http.DefaultTransport when nil.
+func NewCassette(path string, mode CassetteMode, transport http.RoundTripper) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	cassette := &Cassette{path: path, transport: transport, replayed: map[int]bool{}}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case mode == CassetteRecord || mode == CassetteAuto && os.IsNotExist(err):
+		cassette.recording = true
+		return cassette, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cassette.interactions); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %v", path, err)
+	}
+	return cassette, nil
+}
+
+// Recording reports whether the requests are sent to Elasticsearch and recorded
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// RoundTrip records the interaction with Elasticsearch, or replays it
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := recordedBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.recording {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) record(req *http.Request, body string) (*http.Response, error) {
+	response, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	raw, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := decodeBody(raw, response.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{}
+	interaction.Request.Method = req.Method
+	interaction.Request.URL = req.URL.RequestURI()
+	interaction.Request.Body = body
+	interaction.Response.Status = response.StatusCode
+	interaction.Response.Header = map[string][]string{}
+	for key, values := range response.Header {
+		// The body is recorded decompressed
+		if key != "Content-Encoding" && key != "Content-Length" {
+			interaction.Response.Header[key] = values
+		}
+	}
+	interaction.Response.Body = string(responseBody)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.interactions = append(c.interactions, interaction)
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(c.path, data, 0644); err != nil {
+		return nil, err
+	}
+	return interaction.response(req), nil
+}
+
+func (c *Cassette) replay(req *http.Request, body string) (*http.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, interaction := range c.interactions {
+		if c.replayed[i] || interaction.Request.Method != req.Method ||
+			interaction.Request.URL != req.URL.RequestURI() || interaction.Request.Body != body {
+			continue
+		}
+		c.replayed[i] = true
+		return interaction.response(req), nil
+	}
+	return nil, fmt.Errorf("request %s %s not recorded on cassette %s", req.Method, req.URL.RequestURI(), c.path)
+}
+
+// response returns the recorded response
+func (i Interaction) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for key, values := range i.Response.Header {
+		header[key] = values
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(i.Response.Status) + " " + http.StatusText(i.Response.Status),
+		StatusCode:    i.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the body of the request, replaced by an in-memory copy
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// decodeBody decompresses a body with the given content encoding
+func decodeBody(body []byte, encoding string) ([]byte, error) {
+	if encoding != "gzip" || len(body) == 0 {
+		return body, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
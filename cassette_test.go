@@ -0,0 +1,43 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestCassette(t *testing.T) {
+	helper := Test{}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Write([]byte(`{"_index":"test","_id":"1","found":true,"_source":{"Name":"Jeans"}}`))
+	}))
+
+	cassette, err := elasticsearch.NewCassette(path, elasticsearch.CassetteAuto, nil)
+	helper.OK(t, err)
+	helper.Assert(t, cassette.Recording(), "A missing cassette is expected to be recorded")
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithTransport(cassette), elasticsearch.WithResponseCompression())
+	document, err := client.Document(IndexName, "", "1")
+	helper.OK(t, err)
+	helper.Equals(t, `{"Name":"Jeans"}`, string(document.Source))
+	server.Close()
+
+	// Replayed without the server
+	cassette, err = elasticsearch.NewCassette(path, elasticsearch.CassetteAuto, nil)
+	helper.OK(t, err)
+	helper.Assert(t, !cassette.Recording(), "An existing cassette is expected to be replayed")
+	client = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithTransport(cassette), elasticsearch.WithResponseCompression())
+	document, err = client.Document(IndexName, "", "1")
+	helper.OK(t, err)
+	helper.Equals(t, `{"Name":"Jeans"}`, string(document.Source))
+	helper.Equals(t, 1, requests)
+
+	_, err = client.Document(IndexName, "", "2")
+	helper.Assert(t, err != nil, "A request which hasn't been recorded is expected to fail")
+}
@@ -0,0 +1,226 @@
+package elasticsearch
+
+// Cat exposes the compact and aligned text (CAT) APIs, decoded from their JSON format.
+// The numeric columns, returned as strings, are decoded as numbers, the sizes being requested in bytes.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat.html
+type Cat interface {
+	Indices(indices string) ([]CatIndex, error)
+	Shards(indices string) ([]CatShard, error)
+	Aliases(aliases string) ([]CatAlias, error)
+	Nodes() ([]CatNode, error)
+	Health() ([]CatHealth, error)
+	ThreadPool(pools string) ([]CatThreadPool, error)
+	Recovery(indices string) ([]CatRecovery, error)
+	Segments(indices string) ([]CatSegment, error)
+}
+
+// CatIndex represents a line of _cat/indices.
+// The counts of a closed index are zero, and the sizes are in bytes as for every CAT API.
+type CatIndex struct {
+	Health       string `json:"health"`
+	Status       string `json:"status"`
+	Index        string `json:"index"`
+	UUID         string `json:"uuid"`
+	Primaries    int    `json:"pri,string"`
+	Replicas     int    `json:"rep,string"`
+	DocsCount    int64  `json:"docs.count,string"`
+	DocsDeleted  int64  `json:"docs.deleted,string"`
+	StoreSize    int64  `json:"store.size,string"`
+	PriStoreSize int64  `json:"pri.store.size,string"`
+}
+
+// CatShard represents a line of _cat/shards, the counts of an unassigned shard being zero
+type CatShard struct {
+	Index  string `json:"index"`
+	Shard  int    `json:"shard,string"`
+	PriRep string `json:"prirep"`
+	State  string `json:"state"`
+	Docs   int64  `json:"docs,string"`
+	Store  int64  `json:"store,string"`
+	IP     string `json:"ip"`
+	Node   string `json:"node"`
+}
+
+// CatAlias represents a line of _cat/aliases
+type CatAlias struct {
+	Alias         string `json:"alias"`
+	Index         string `json:"index"`
+	Filter        string `json:"filter"`
+	RoutingIndex  string `json:"routing.index"`
+	RoutingSearch string `json:"routing.search"`
+	IsWriteIndex  string `json:"is_write_index"`
+}
+
+// CatNode represents a line of _cat/nodes
+type CatNode struct {
+	IP          string  `json:"ip"`
+	HeapPercent int     `json:"heap.percent,string"`
+	RAMPercent  int     `json:"ram.percent,string"`
+	CPU         int     `json:"cpu,string"`
+	Load1m      float64 `json:"load_1m,string"`
+	Load5m      float64 `json:"load_5m,string"`
+	Load15m     float64 `json:"load_15m,string"`
+	NodeRole    string  `json:"node.role"`
+	Master      string  `json:"master"`
+	Name        string  `json:"name"`
+}
+
+// CatHealth represents a line of _cat/health
+type CatHealth struct {
+	Epoch               int64  `json:"epoch,string"`
+	Timestamp           string `json:"timestamp"`
+	Cluster             string `json:"cluster"`
+	Status              string `json:"status"`
+	NodeTotal           int    `json:"node.total,string"`
+	NodeData            int    `json:"node.data,string"`
+	Shards              int    `json:"shards,string"`
+	Primaries           int    `json:"pri,string"`
+	Relocating          int    `json:"relo,string"`
+	Initializing        int    `json:"init,string"`
+	Unassigned          int    `json:"unassign,string"`
+	PendingTasks        int    `json:"pending_tasks,string"`
+	MaxTaskWaitTime     string `json:"max_task_wait_time"`
+	ActiveShardsPercent string `json:"active_shards_percent"`
+}
+
+// CatThreadPool represents a line of _cat/thread_pool
+type CatThreadPool struct {
+	NodeName string `json:"node_name"`
+	Name     string `json:"name"`
+	Active   int    `json:"active,string"`
+	Queue    int    `json:"queue,string"`
+	Rejected int64  `json:"rejected,string"`
+}
+
+// CatRecovery represents a line of _cat/recovery, the recovery of a shard copy
+type CatRecovery struct {
+	Index                string `json:"index"`
+	Shard                int    `json:"shard,string"`
+	Time                 string `json:"time"`
+	Type                 string `json:"type"`  // empty_store, existing_store, peer, snapshot or local_shards
+	Stage                string `json:"stage"` // init, index, verify_index, translog, finalize or done
+	SourceHost           string `json:"source_host"`
+	SourceNode           string `json:"source_node"`
+	TargetHost           string `json:"target_host"`
+	TargetNode           string `json:"target_node"`
+	Repository           string `json:"repository"`
+	Snapshot             string `json:"snapshot"`
+	Files                int    `json:"files,string"`
+	FilesRecovered       int    `json:"files_recovered,string"`
+	FilesPercent         string `json:"files_percent"`
+	FilesTotal           int    `json:"files_total,string"`
+	Bytes                int64  `json:"bytes,string"`
+	BytesRecovered       int64  `json:"bytes_recovered,string"`
+	BytesPercent         string `json:"bytes_percent"`
+	BytesTotal           int64  `json:"bytes_total,string"`
+	TranslogOps          int64  `json:"translog_ops,string"`
+	TranslogOpsRecovered int64  `json:"translog_ops_recovered,string"`
+	TranslogOpsPercent   string `json:"translog_ops_percent"`
+}
+
+// CatSegment represents a line of _cat/segments, a Lucene segment of a shard copy
+type CatSegment struct {
+	Index       string `json:"index"`
+	Shard       int    `json:"shard,string"`
+	PriRep      string `json:"prirep"`
+	IP          string `json:"ip"`
+	Segment     string `json:"segment"`
+	Generation  int64  `json:"generation,string"`
+	DocsCount   int64  `json:"docs.count,string"`
+	DocsDeleted int64  `json:"docs.deleted,string"`
+	Size        int64  `json:"size,string"`
+	SizeMemory  int64  `json:"size.memory,string"`
+	Committed   string `json:"committed"`
+	Searchable  string `json:"searchable"`
+	Version     string `json:"version"`
+	Compound    string `json:"compound"`
+}
+
+type cat struct {
+	client *client
+}
+
+// Cat returns the client of the CAT APIs
+func (c *client) Cat() Cat {
+	return &cat{client: c}
+}
+
+// Indices returns the indices matching the pattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-indices.html
+func (c *cat) Indices(indices string) ([]CatIndex, error) {
+	result := []CatIndex{}
+	err := c.get("indices", c.client.namespace(indices), &result)
+	return result, err
+}
+
+// Shards returns the shards of the indices matching the pattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-shards.html
+func (c *cat) Shards(indices string) ([]CatShard, error) {
+	result := []CatShard{}
+	err := c.get("shards", c.client.namespace(indices), &result)
+	return result, err
+}
+
+// Aliases returns the aliases matching the pattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-alias.html
+func (c *cat) Aliases(aliases string) ([]CatAlias, error) {
+	result := []CatAlias{}
+	err := c.get("aliases", c.client.namespace(aliases), &result)
+	return result, err
+}
+
+// Nodes returns the nodes of the cluster
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-nodes.html
+func (c *cat) Nodes() ([]CatNode, error) {
+	result := []CatNode{}
+	err := c.get("nodes", "", &result)
+	return result, err
+}
+
+// Health returns the health status of the cluster
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-health.html
+func (c *cat) Health() ([]CatHealth, error) {
+	result := []CatHealth{}
+	err := c.get("health", "", &result)
+	return result, err
+}
+
+// ThreadPool returns the thread pools matching the pattern on every node, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-thread-pool.html
+func (c *cat) ThreadPool(pools string) ([]CatThreadPool, error) {
+	result := []CatThreadPool{}
+	err := c.get("thread_pool", pools, &result)
+	return result, err
+}
+
+// Recovery returns the ongoing and completed recoveries of the shards of the indices matching the
+// pattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-recovery.html
+func (c *cat) Recovery(indices string) ([]CatRecovery, error) {
+	result := []CatRecovery{}
+	err := c.get("recovery", c.client.namespace(indices), &result)
+	return result, err
+}
+
+// Segments returns the segments of the shards of the indices matching the pattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-segments.html
+func (c *cat) Segments(indices string) ([]CatSegment, error) {
+	result := []CatSegment{}
+	err := c.get("segments", c.client.namespace(indices), &result)
+	return result, err
+}
+
+func (c *cat) get(api, target string, result interface{}) error {
+	url := c.client.Host.String() + "/_cat/" + api
+	if target != "" {
+		url += "/" + escapeIndices(target)
+	}
+	url += "?format=json&bytes=b"
+
+	response, err := c.client.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.client.codec.Unmarshal(response, result)
+}
@@ -0,0 +1,122 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestCatIndicesAndShards(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cat/indices/staging-orders-*", http.StatusOK, `[
+		{"health":"green","status":"open","index":"staging-orders-v2","uuid":"u2","pri":"3","rep":"1","docs.count":"1000","docs.deleted":"12","store.size":"2097152","pri.store.size":"1048576"},
+		{"health":null,"status":"close","index":"staging-orders-v1","uuid":"u1","pri":"3","rep":"1","docs.count":null,"docs.deleted":null,"store.size":null,"pri.store.size":null}]`)
+	recorder.Respond("GET", "/_cat/shards/staging-orders-v2", http.StatusOK, `[
+		{"index":"staging-orders-v2","shard":"0","prirep":"p","state":"STARTED","docs":"1000","store":"1048576","ip":"10.0.0.1","node":"node-1"},
+		{"index":"staging-orders-v2","shard":"0","prirep":"r","state":"UNASSIGNED","docs":null,"store":null,"ip":null,"node":null}]`)
+	cat := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-")).Cat()
+
+	indices, err := cat.Indices("orders-*")
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(indices))
+	helper.Equals(t, elasticsearch.CatIndex{
+		Health: "green", Status: "open", Index: "staging-orders-v2", UUID: "u2", Primaries: 3, Replicas: 1,
+		DocsCount: 1000, DocsDeleted: 12, StoreSize: 2097152, PriStoreSize: 1048576,
+	}, indices[0])
+	helper.Equals(t, int64(0), indices[1].DocsCount)
+
+	shards, err := cat.Shards("orders-v2")
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(shards))
+	helper.Equals(t, int64(1000), shards[0].Docs)
+	helper.Equals(t, int64(1048576), shards[0].Store)
+	helper.Equals(t, "UNASSIGNED", shards[1].State)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_cat/indices/staging-orders-*?format=json&bytes=b", requests[0].String())
+	helper.Equals(t, "GET /_cat/shards/staging-orders-v2?format=json&bytes=b", requests[1].String())
+}
+
+func TestCatAliases(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cat/aliases/staging-orders", http.StatusOK, `[{"alias":"staging-orders","index":"staging-orders-v2","filter":"-","routing.index":"-","routing.search":"-","is_write_index":"true"}]`)
+	cat := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-")).Cat()
+
+	aliases, err := cat.Aliases("orders")
+	helper.OK(t, err)
+	helper.Equals(t, []elasticsearch.CatAlias{{
+		Alias: "staging-orders", Index: "staging-orders-v2", Filter: "-", RoutingIndex: "-", RoutingSearch: "-", IsWriteIndex: "true",
+	}}, aliases)
+	helper.Equals(t, "GET /_cat/aliases/staging-orders?format=json&bytes=b", recorder.Requests()[0].String())
+}
+
+func TestCatNodesAndHealth(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cat/nodes", http.StatusOK, `[{"ip":"10.0.0.1","heap.percent":"45","ram.percent":"92","cpu":"7","load_1m":"0.52","load_5m":"0.61","load_15m":null,"node.role":"dimr","master":"*","name":"node-1"}]`)
+	recorder.Respond("GET", "/_cat/health", http.StatusOK, `[{"epoch":"1700000000","timestamp":"22:13:20","cluster":"search","status":"yellow","node.total":"3","node.data":"2","shards":"10","pri":"6","relo":"0","init":"1","unassign":"3","pending_tasks":"2","max_task_wait_time":"-","active_shards_percent":"71.4%"}]`)
+	cat := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder)).Cat()
+
+	nodes, err := cat.Nodes()
+	helper.OK(t, err)
+	helper.Equals(t, elasticsearch.CatNode{
+		IP: "10.0.0.1", HeapPercent: 45, RAMPercent: 92, CPU: 7, Load1m: 0.52, Load5m: 0.61, NodeRole: "dimr", Master: "*", Name: "node-1",
+	}, nodes[0])
+
+	health, err := cat.Health()
+	helper.OK(t, err)
+	helper.Equals(t, elasticsearch.CatHealth{
+		Epoch: 1700000000, Timestamp: "22:13:20", Cluster: "search", Status: "yellow", NodeTotal: 3, NodeData: 2, Shards: 10,
+		Primaries: 6, Initializing: 1, Unassigned: 3, PendingTasks: 2, MaxTaskWaitTime: "-", ActiveShardsPercent: "71.4%",
+	}, health[0])
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_cat/nodes?format=json&bytes=b", requests[0].String())
+	helper.Equals(t, "GET /_cat/health?format=json&bytes=b", requests[1].String())
+}
+
+func TestCatThreadPool(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cat/thread_pool/write,search", http.StatusOK, `[
+		{"node_name":"node-1","name":"search","active":"2","queue":"0","rejected":"0"},
+		{"node_name":"node-1","name":"write","active":"8","queue":"150","rejected":"12345"}]`)
+	cat := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-")).Cat()
+
+	pools, err := cat.ThreadPool("write,search")
+	helper.OK(t, err)
+	helper.Equals(t, []elasticsearch.CatThreadPool{
+		{NodeName: "node-1", Name: "search", Active: 2},
+		{NodeName: "node-1", Name: "write", Active: 8, Queue: 150, Rejected: 12345},
+	}, pools)
+	// The thread pools aren't indices, they're not namespaced
+	helper.Equals(t, "GET /_cat/thread_pool/write,search?format=json&bytes=b", recorder.Requests()[0].String())
+}
+
+func TestCatRecoveryAndSegments(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cat/recovery/orders-v2", http.StatusOK, `[{"index":"orders-v2","shard":"0","time":"1.2s","type":"peer","stage":"index","source_node":"node-1","target_node":"node-2","bytes_recovered":"536870912","bytes_percent":"42.0%","bytes_total":"1288490188"}]`)
+	recorder.Respond("GET", "/_cat/segments/orders-v2", http.StatusOK, `[{"index":"orders-v2","shard":"0","prirep":"p","segment":"_0","generation":"0","docs.count":"1000","docs.deleted":"12","size":"1153433","committed":"true","searchable":"true","compound":"false"}]`)
+	cat := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder)).Cat()
+
+	recoveries, err := cat.Recovery("orders-v2")
+	helper.OK(t, err)
+	helper.Equals(t, 1, len(recoveries))
+	helper.Equals(t, "peer", recoveries[0].Type)
+	helper.Equals(t, int64(536870912), recoveries[0].BytesRecovered)
+	helper.Equals(t, "42.0%", recoveries[0].BytesPercent)
+
+	segments, err := cat.Segments("orders-v2")
+	helper.OK(t, err)
+	helper.Equals(t, int64(1000), segments[0].DocsCount)
+	helper.Equals(t, int64(12), segments[0].DocsDeleted)
+	helper.Equals(t, int64(1153433), segments[0].Size)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_cat/recovery/orders-v2?format=json&bytes=b", requests[0].String())
+	helper.Equals(t, "GET /_cat/segments/orders-v2?format=json&bytes=b", requests[1].String())
+}
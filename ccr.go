@@ -0,0 +1,230 @@
+package elasticsearch
+
+// CCR exposes the cross-cluster replication APIs, replicating the indices of a remote leader cluster
+// into follower indices of the local cluster
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-apis.html
+type CCR interface {
+	Follow(followerIndex, body string) (*FollowResult, error)
+	PauseFollow(followerIndex string) (*Response, error)
+	ResumeFollow(followerIndex, body string) (*Response, error)
+	Unfollow(followerIndex string) (*Response, error)
+	FollowStats(followerIndices string) (*FollowStats, error)
+	FollowInfo(followerIndices string) (*FollowInfo, error)
+	Stats() (*CCRStats, error)
+	PutAutoFollowPattern(name, body string) (*Response, error)
+	GetAutoFollowPatterns(name string) (*AutoFollowPatterns, error)
+	DeleteAutoFollowPattern(name string) (*Response, error)
+	PauseAutoFollowPattern(name string) (*Response, error)
+	ResumeAutoFollowPattern(name string) (*Response, error)
+}
+
+// FollowResult represents the response of the creation of a follower index
+type FollowResult struct {
+	RawResponse
+
+	FollowIndexCreated     bool `json:"follow_index_created"`
+	FollowIndexShardsAcked bool `json:"follow_index_shards_acked"`
+	IndexFollowingStarted  bool `json:"index_following_started"`
+}
+
+// FollowStats represents the shard-level replication statistics of follower indices
+type FollowStats struct {
+	RawResponse
+
+	Indices []struct {
+		Index  string             `json:"index"`
+		Shards []FollowShardStats `json:"shards"`
+	} `json:"indices"`
+}
+
+// FollowShardStats represents the replication statistics of a shard of a follower index.
+// The lag of the follower is LeaderGlobalCheckpoint - FollowerGlobalCheckpoint operations.
+type FollowShardStats struct {
+	RemoteCluster                 string `json:"remote_cluster"`
+	LeaderIndex                   string `json:"leader_index"`
+	FollowerIndex                 string `json:"follower_index"`
+	ShardID                       int    `json:"shard_id"`
+	LeaderGlobalCheckpoint        int64  `json:"leader_global_checkpoint"`
+	LeaderMaxSeqNo                int64  `json:"leader_max_seq_no"`
+	FollowerGlobalCheckpoint      int64  `json:"follower_global_checkpoint"`
+	FollowerMaxSeqNo              int64  `json:"follower_max_seq_no"`
+	LastRequestedSeqNo            int64  `json:"last_requested_seq_no"`
+	OutstandingReadRequests       int    `json:"outstanding_read_requests"`
+	OutstandingWriteRequests      int    `json:"outstanding_write_requests"`
+	WriteBufferOperationCount     int64  `json:"write_buffer_operation_count"`
+	WriteBufferSizeInBytes        int64  `json:"write_buffer_size_in_bytes"`
+	TotalReadTimeMillis           int64  `json:"total_read_time_millis"`
+	TotalReadRemoteExecTimeMillis int64  `json:"total_read_remote_exec_time_millis"`
+	SuccessfulReadRequests        int64  `json:"successful_read_requests"`
+	FailedReadRequests            int64  `json:"failed_read_requests"`
+	OperationsRead                int64  `json:"operations_read"`
+	BytesRead                     int64  `json:"bytes_read"`
+	TotalWriteTimeMillis          int64  `json:"total_write_time_millis"`
+	SuccessfulWriteRequests       int64  `json:"successful_write_requests"`
+	FailedWriteRequests           int64  `json:"failed_write_requests"`
+	OperationsWritten             int64  `json:"operations_written"`
+	TimeSinceLastReadMillis       int64  `json:"time_since_last_read_millis"`
+	ReadExceptions                []struct {
+		FromSeqNo int64      `json:"from_seq_no"`
+		Retries   int        `json:"retries"`
+		Exception ErrorCause `json:"exception"`
+	} `json:"read_exceptions"`
+	FatalException *ErrorCause `json:"fatal_exception,omitempty"`
+}
+
+// FollowInfo represents the parameters and the status, active or paused, of follower indices
+type FollowInfo struct {
+	RawResponse
+
+	FollowerIndices []struct {
+		FollowerIndex string                 `json:"follower_index"`
+		RemoteCluster string                 `json:"remote_cluster"`
+		LeaderIndex   string                 `json:"leader_index"`
+		Status        string                 `json:"status"`
+		Parameters    map[string]interface{} `json:"parameters"`
+	} `json:"follower_indices"`
+}
+
+// CCRStats represents the auto-follow statistics and the replication statistics of every follower index
+type CCRStats struct {
+	RawResponse
+
+	AutoFollowStats struct {
+		NumberOfFailedFollowIndices              int64 `json:"number_of_failed_follow_indices"`
+		NumberOfFailedRemoteClusterStateRequests int64 `json:"number_of_failed_remote_cluster_state_requests"`
+		NumberOfSuccessfulFollowIndices          int64 `json:"number_of_successful_follow_indices"`
+		RecentAutoFollowErrors                   []struct {
+			LeaderIndex         string     `json:"leader_index"`
+			Timestamp           int64      `json:"timestamp"`
+			AutoFollowException ErrorCause `json:"auto_follow_exception"`
+		} `json:"recent_auto_follow_errors"`
+		AutoFollowedClusters []struct {
+			ClusterName              string `json:"cluster_name"`
+			TimeSinceLastCheckMillis int64  `json:"time_since_last_check_millis"`
+			LastSeenMetadataVersion  int64  `json:"last_seen_metadata_version"`
+		} `json:"auto_followed_clusters"`
+	} `json:"auto_follow_stats"`
+	FollowStats FollowStats `json:"follow_stats"`
+}
+
+// AutoFollowPatterns represents auto-follow patterns, creating a follower index for every new remote
+// index matching them
+type AutoFollowPatterns struct {
+	RawResponse
+
+	Patterns []struct {
+		Name    string `json:"name"`
+		Pattern struct {
+			Active                       bool     `json:"active"`
+			RemoteCluster                string   `json:"remote_cluster"`
+			LeaderIndexPatterns          []string `json:"leader_index_patterns"`
+			LeaderIndexExclusionPatterns []string `json:"leader_index_exclusion_patterns"`
+			FollowIndexPattern           string   `json:"follow_index_pattern"`
+		} `json:"pattern"`
+	} `json:"patterns"`
+}
+
+type ccr struct {
+	client *client
+}
+
+// CCR returns the client of the cross-cluster replication APIs
+func (c *client) CCR() CCR {
+	return &ccr{client: c}
+}
+
+// Follow creates a follower index replicating the leader index and remote cluster described by body
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-put-follow.html
+func (c *ccr) Follow(followerIndex, body string) (*FollowResult, error) {
+	result := &FollowResult{}
+	if err := c.client.sendAPIRequest("PUT", "/"+c.client.indexPath(followerIndex)+"/_ccr/follow", body, result); err != nil {
+		return &FollowResult{}, err
+	}
+	return result, nil
+}
+
+// PauseFollow pauses the replication of a follower index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-post-pause-follow.html
+func (c *ccr) PauseFollow(followerIndex string) (*Response, error) {
+	return c.client.acknowledge("POST", "/"+c.client.indexPath(followerIndex)+"/_ccr/pause_follow", "")
+}
+
+// ResumeFollow resumes the replication of a paused follower index, with the parameters of body if any
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-post-resume-follow.html
+func (c *ccr) ResumeFollow(followerIndex, body string) (*Response, error) {
+	return c.client.acknowledge("POST", "/"+c.client.indexPath(followerIndex)+"/_ccr/resume_follow", body)
+}
+
+// Unfollow converts a paused and closed follower index into a regular index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-post-unfollow.html
+func (c *ccr) Unfollow(followerIndex string) (*Response, error) {
+	return c.client.acknowledge("POST", "/"+c.client.indexPath(followerIndex)+"/_ccr/unfollow", "")
+}
+
+// FollowStats returns the replication statistics of the shards of the follower indices
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-get-follow-stats.html
+func (c *ccr) FollowStats(followerIndices string) (*FollowStats, error) {
+	result := &FollowStats{}
+	if err := c.client.sendAPIRequest("GET", "/"+c.client.indexPath(followerIndices)+"/_ccr/stats", "", result); err != nil {
+		return &FollowStats{}, err
+	}
+	return result, nil
+}
+
+// FollowInfo returns the parameters and the status of the follower indices
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-get-follow-info.html
+func (c *ccr) FollowInfo(followerIndices string) (*FollowInfo, error) {
+	result := &FollowInfo{}
+	if err := c.client.sendAPIRequest("GET", "/"+c.client.indexPath(followerIndices)+"/_ccr/info", "", result); err != nil {
+		return &FollowInfo{}, err
+	}
+	return result, nil
+}
+
+// Stats returns the auto-follow statistics and the replication statistics of every follower index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-get-stats.html
+func (c *ccr) Stats() (*CCRStats, error) {
+	result := &CCRStats{}
+	if err := c.client.sendAPIRequest("GET", "/_ccr/stats", "", result); err != nil {
+		return &CCRStats{}, err
+	}
+	return result, nil
+}
+
+// PutAutoFollowPattern creates or updates an auto-follow pattern
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-put-auto-follow-pattern.html
+func (c *ccr) PutAutoFollowPattern(name, body string) (*Response, error) {
+	return c.client.acknowledge("PUT", "/_ccr/auto_follow/"+escapePath(name), body)
+}
+
+// GetAutoFollowPatterns returns the auto-follow pattern, all of them if name is empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-get-auto-follow-pattern.html
+func (c *ccr) GetAutoFollowPatterns(name string) (*AutoFollowPatterns, error) {
+	path := "/_ccr/auto_follow"
+	if name != "" {
+		path += "/" + escapePath(name)
+	}
+	result := &AutoFollowPatterns{}
+	if err := c.client.sendAPIRequest("GET", path, "", result); err != nil {
+		return &AutoFollowPatterns{}, err
+	}
+	return result, nil
+}
+
+// DeleteAutoFollowPattern deletes an auto-follow pattern, the existing follower indices being kept
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-delete-auto-follow-pattern.html
+func (c *ccr) DeleteAutoFollowPattern(name string) (*Response, error) {
+	return c.client.acknowledge("DELETE", "/_ccr/auto_follow/"+escapePath(name), "")
+}
+
+// PauseAutoFollowPattern stops following the new remote indices matching the pattern
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-pause-auto-follow-pattern.html
+func (c *ccr) PauseAutoFollowPattern(name string) (*Response, error) {
+	return c.client.acknowledge("POST", "/_ccr/auto_follow/"+escapePath(name)+"/pause", "")
+}
+
+// ResumeAutoFollowPattern resumes following the new remote indices matching the pattern
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-resume-auto-follow-pattern.html
+func (c *ccr) ResumeAutoFollowPattern(name string) (*Response, error) {
+	return c.client.acknowledge("POST", "/_ccr/auto_follow/"+escapePath(name)+"/resume", "")
+}
@@ -0,0 +1,59 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestCCR(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.URL.Path {
+		case "/staging-orders/_ccr/follow":
+			w.Write([]byte(`{"follow_index_created":true,"follow_index_shards_acked":true,"index_following_started":true}`))
+		case "/staging-orders/_ccr/stats":
+			w.Write([]byte(`{"indices":[{"index":"staging-orders","shards":[{"remote_cluster":"dr","leader_index":"orders","follower_index":"staging-orders","shard_id":0,"leader_global_checkpoint":1024,"follower_global_checkpoint":1000,"read_exceptions":[]}]}]}`))
+		case "/_ccr/auto_follow/logs":
+			w.Write([]byte(`{"patterns":[{"name":"logs","pattern":{"active":true,"remote_cluster":"dr","leader_index_patterns":["logs-*"],"follow_index_pattern":"{{leader_index}}-copy"}}]}`))
+		default:
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer server.Close()
+
+	ccr := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-")).CCR()
+	follow, err := ccr.Follow("orders", `{"remote_cluster":"dr","leader_index":"orders"}`)
+	helper.OK(t, err)
+	helper.Assert(t, follow.IndexFollowingStarted, "The following is expected to be started")
+
+	stats, err := ccr.FollowStats("orders")
+	helper.OK(t, err)
+	shard := stats.Indices[0].Shards[0]
+	helper.Equals(t, int64(24), shard.LeaderGlobalCheckpoint-shard.FollowerGlobalCheckpoint)
+
+	paused, err := ccr.PauseFollow("orders")
+	helper.OK(t, err)
+	helper.Assert(t, paused.Acknowledged, "The pause is expected to be acknowledged")
+
+	patterns, err := ccr.GetAutoFollowPatterns("logs")
+	helper.OK(t, err)
+	helper.Equals(t, []string{"logs-*"}, patterns.Patterns[0].Pattern.LeaderIndexPatterns)
+
+	_, err = ccr.PauseAutoFollowPattern("logs")
+	helper.OK(t, err)
+
+	helper.Equals(t, []string{
+		`PUT /staging-orders/_ccr/follow {"remote_cluster":"dr","leader_index":"orders"}`,
+		"GET /staging-orders/_ccr/stats ",
+		"POST /staging-orders/_ccr/pause_follow ",
+		"GET /_ccr/auto_follow/logs ",
+		"POST /_ccr/auto_follow/logs/pause ",
+	}, requests)
+}
@@ -2,72 +2,197 @@ package elasticsearch
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Searcher set the contract to manage indices, synchronize data and request
 type Client interface {
 	CreateIndex(indexName, mapping string) (*Response, error)
-	DeleteIndex(indexName string) (*Response, error)
-	UpdateIndexSetting(indexName, mapping string) (*Response, error)
-	IndexSettings(indexName string) (Settings, error)
-	IndexExists(indexName string) (bool, error)
+	DeleteIndex(indexName string, opts ...RequestOption) (*Response, error)
+	UpdateIndexSetting(indexName, mapping string, opts ...RequestOption) (*Response, error)
+	IndexSettings(indexName string, opts ...RequestOption) (Settings, error)
+	IndicesSettings(indices string, opts ...RequestOption) (map[string]Settings, error)
+	GetIndex(indexName string, opts ...RequestOption) (map[string]IndexDefinition, error)
+	IndexExists(indexName string, opts ...RequestOption) (bool, error)
+	TypeExists(indexName, documentType string, opts ...RequestOption) (bool, error)
+	GetMapping(indexName string, opts ...RequestOption) ([]byte, error)
+	GetMappings(indexName string, opts ...RequestOption) (map[string]Mappings, error)
+	PutMapping(indexName, mapping string, opts ...RequestOption) (*Response, error)
+	IndexTemplate(name string) ([]byte, error)
+	PutIndexTemplate(name, template string) (*Response, error)
+	Rollover(alias, body string, opts ...RequestOption) (*RolloverResult, error)
 	Status(indices string) (*Settings, error)
-	InsertDocument(indexName, documentType, identifier string, data []byte) (*InsertDocument, error)
-	Document(indexName, documentType, identifier string) (*Document, error)
-	DeleteDocument(indexName, documentType, identifier string) (*Document, error)
-	Bulk(indexName string, data []byte) (*Bulk, error)
-	Search(indexName, documentType, data string, explain bool) (*SearchResult, error)
-	MSearch(queries []MSearchQuery) (*MSearchResult, error)
-	Suggest(indexName, data string) ([]byte, error)
+	IndexStats(indices string, opts ...RequestOption) (*IndexStats, error)
+	InsertDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error)
+	CreateDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error)
+	UpdateDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error)
+	UpdateWithRequest(indexName, identifier string, request *UpdateRequest, opts ...RequestOption) (*InsertDocument, error)
+	Document(indexName, documentType, identifier string, opts ...RequestOption) (*Document, error)
+	DeleteDocument(indexName, documentType, identifier string, opts ...RequestOption) (*Document, error)
+	DocumentExists(indexName, documentType, identifier string, opts ...RequestOption) (bool, error)
+	DocumentSource(indexName, documentType, identifier string, opts ...RequestOption) ([]byte, error)
+	Bulk(indexName string, data []byte, opts ...RequestOption) (*Bulk, error)
+	BulkIndexTarget(indexName, documentType string, data []byte, opts ...RequestOption) (*Bulk, error)
+	BulkReader(indexName string, r io.Reader, contentLength int64, opts ...RequestOption) (*Bulk, error)
+	Search(indexName, documentType, data string, explain bool, opts ...RequestOption) (*SearchResult, error)
+	SearchWithRequest(indexName string, request *SearchRequest, opts ...RequestOption) (*SearchResult, error)
+	SearchStream(indexName, query string, opts ...RequestOption) (<-chan Hit, <-chan error)
+	MSearch(queries []MSearchQuery, opts ...RequestOption) (*MSearchResult, error)
+	MSearchTemplate(queries []MSearchQuery, opts ...RequestOption) (*MSearchResult, error)
+	Explain(indexName, documentType, identifier, query string) (*ExplainResult, error)
+	ValidateQuery(indexName, documentType, query string, explain bool) (*ValidateResult, error)
+	RankEval(indices, data string) (*RankEvalResult, error)
+	Suggest(indexName, data string) (*SuggestResult, error)
 	GetIndicesFromAlias(alias string) ([]string, error)
 	UpdateAlias(remove []string, add []string, alias string) (*Response, error)
+	UpdateAliasActions(actions []AliasAction) (*Response, error)
+	GetAlias(alias string) (map[string]AliasInfo, error)
+	AliasExists(alias string) (bool, error)
 	UpdateByQuery(indexName, query string) (*UpdateByQueryResult, error)
+	DeleteByQuery(indexName, query string) (*DeleteByQueryResult, error)
+	PutScript(id, script string) (*Response, error)
+	GetScript(id string) (*StoredScript, error)
+	DeleteScript(id string) (*Response, error)
+	ExecutePainless(body string) (*PainlessResult, error)
+	CreateSearchTemplate(name, template string) (*Response, error)
+	GetSearchTemplate(name string) (*StoredScript, error)
+	DeleteSearchTemplate(name string) (*Response, error)
+	RenderSearchTemplate(data string) (*RenderedTemplate, error)
+	Cat() Cat
+	CCR() CCR
+	Security() Security
+	Transform() Transform
+	Rollup() Rollup
+	ILM() ILM
+	Ingest() Ingest
+	NodesInfo(metrics []string) (*NodesInfo, error)
+	NodesStats(metrics []string) (*NodesStats, error)
+	PendingTasks() (*PendingTasks, error)
+	ClusterState(metrics, indices string) (*ClusterState, error)
+	ClusterHealth(indices string, opts ...RequestOption) (*ClusterHealth, error)
+	WaitForStatus(indexName, status string, timeout time.Duration) (*ClusterHealth, error)
+	PostVotingConfigExclusions(nodeNames []string, timeout time.Duration) error
+	DeleteVotingConfigExclusions(waitForRemoval bool) error
+	ShrinkIndex(source, target, body string) (*Response, error)
+	SplitIndex(source, target, body string) (*Response, error)
+	CloneIndex(source, target, body string) (*Response, error)
+	IndexSegments(indices string) (*IndexSegments, error)
+	IndexRecovery(indices string, activeOnly bool) (map[string]IndexRecovery, error)
+	ClearCache(indices string, caches ...string) (*BroadcastResponse, error)
+	FreezeIndex(indexName string) (*Response, error)
+	UnfreezeIndex(indexName string) (*Response, error)
+	AddIndexBlock(indexName string, block IndexBlock) (*IndexBlockResult, error)
+	ListDanglingIndices() (*DanglingIndices, error)
+	ImportDanglingIndex(indexUUID string, acceptDataLoss bool) (*Response, error)
+	DeleteDanglingIndex(indexUUID string, acceptDataLoss bool) (*Response, error)
+	DeprecationInfo(indexName string) (*DeprecationInfo, error)
+	Do(method, path string, params url.Values, body io.Reader) (*http.Response, error)
+	Info() (*Status, error)
+	IsOpenSearch() (bool, error)
+	ServerVersion() (Version, error)
 }
 
 // A SearchClient describes the client configuration to manage an ElasticSearch index.
 type client struct {
 	Host url.URL
+
+	compressRequests   bool
+	compressionMinSize int
+	compressResponses  bool
+	transport          http.RoundTripper
+	transportOptions   []func(*http.Transport)
+	httpClient         *http.Client
+	application        string
+	userAgentHeader    string
+	clientMetaHeader   string
+	metrics            MetricsHook
+	signer             RequestSigner
+	typeless           bool
+	compatibleWith     int
+	openSearch         bool
+	codec              Codec
+	rawResponses       bool
+	timeout            time.Duration
+	searchTimeout      time.Duration
+	maxRetries         int
+	maxRetryWait       time.Duration
+	rateLimiter        *RateLimiter
+	defaultParams      url.Values
+	indexPrefix        string
+	indexSuffix        string
+
+	productCheck     bool
+	acceptOpenSearch bool
+	productMutex     sync.Mutex
+	productChecked   bool
+	productErr       error
+
+	infoMutex          sync.Mutex
+	info               *Status
+	versionFailure     time.Time // when apiVersion last failed to get the version, infoMutex being locked
+	detectedOpenSearch int32     // set atomically by Info, read by every request
 }
 
 // NewSearchClient creates and initializes a new ElasticSearch client, implements core api for Indexing and searching.
-func NewClient(scheme, host, port string) Client {
+func NewClient(scheme, host, port string, opts ...ClientOption) Client {
 	u := url.URL{
 		Scheme: scheme,
 		Host:   host + ":" + port,
 	}
-	return &client{Host: u}
+	return newClient(u, opts)
 }
 
 // NewSearchClient creates and initializes a new ElasticSearch client, implements core api for Indexing and searching.
-func NewClientFromUrl(rawurl string) Client {
+func NewClientFromUrl(rawurl string, opts ...ClientOption) Client {
 	u, err := url.Parse(rawurl)
 	if err != nil {
 		log.Fatal(err)
 		return nil
 	}
-	return &client{Host: *u}
+	return newClient(*u, opts)
+}
+
+func newClient(u url.URL, opts []ClientOption) *client {
+	c := &client{Host: u, codec: JSONCodec{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// Wrapped once every option is applied, whatever the order of WithCodec
+	if c.rawResponses {
+		c.codec = rawCodec{Codec: c.codec}
+	}
+	c.configureTransport()
+	c.userAgentHeader = c.userAgent()
+	c.clientMetaHeader = clientMeta()
+	return c
 }
 
 // CreateIndex instantiates an index
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-create-index.html
 func (c *client) CreateIndex(indexName, mapping string) (*Response, error) {
-	url := c.Host.String() + "/" + indexName
+	url := c.Host.String() + "/" + c.indexPath(indexName)
 	reader := bytes.NewBufferString(mapping)
-	response, err := sendHTTPRequest("PUT", url, reader)
+	response, err := c.sendHTTPRequest("PUT", url, reader)
 	if err != nil {
 		return &Response{}, err
 	}
 
 	esResp := &Response{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Response{}, err
 	}
@@ -77,15 +202,15 @@ func (c *client) CreateIndex(indexName, mapping string) (*Response, error) {
 
 // DeleteIndex deletes an existing index.
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-delete-index.html
-func (c *client) DeleteIndex(indexName string) (*Response, error) {
-	url := c.Host.String() + "/" + indexName
-	response, err := sendHTTPRequest("DELETE", url, nil)
+func (c *client) DeleteIndex(indexName string, opts ...RequestOption) (*Response, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indexName))
+	response, err := c.sendHTTPRequest("DELETE", url, nil)
 	if err != nil {
 		return &Response{}, err
 	}
 
 	esResp := &Response{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Response{}, err
 	}
@@ -95,16 +220,16 @@ func (c *client) DeleteIndex(indexName string) (*Response, error) {
 
 // UpdateIndexSetting changes specific index level settings in real time
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-update-settings.html
-func (c *client) UpdateIndexSetting(indexName, mapping string) (*Response, error) {
-	url := c.Host.String() + "/" + indexName + "/_settings"
+func (c *client) UpdateIndexSetting(indexName, mapping string, opts ...RequestOption) (*Response, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indexName) + "/_settings")
 	reader := bytes.NewBufferString(mapping)
-	response, err := sendHTTPRequest("PUT", url, reader)
+	response, err := c.sendHTTPRequest("PUT", url, reader)
 	if err != nil {
 		return &Response{}, err
 	}
 
 	esResp := &Response{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Response{}, err
 	}
@@ -114,47 +239,264 @@ func (c *client) UpdateIndexSetting(indexName, mapping string) (*Response, error
 
 // IndexSettings allows to retrieve settings of index
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-settings.html
-func (c *client) IndexSettings(indexName string) (Settings, error) {
-	url := c.Host.String() + "/" + indexName + "/_settings"
-	response, err := sendHTTPRequest("GET", url, nil)
+func (c *client) IndexSettings(indexName string, opts ...RequestOption) (Settings, error) {
+	info, err := c.IndicesSettings(indexName, opts...)
 	if err != nil {
 		return Settings{}, err
 	}
 
-	type settingsArray map[string]Settings
-	dec := json.NewDecoder(bytes.NewBuffer(response))
-	var info settingsArray
-	err = dec.Decode(&info)
+	return info[c.namespace(indexName)], nil
+}
+
+// IndicesSettings retrieves the settings of the indices matching a comma separated list of names
+// or wildcard expressions, by index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-settings.html
+func (c *client) IndicesSettings(indices string, opts ...RequestOption) (map[string]Settings, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indices) + "/_settings")
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return map[string]Settings{}, err
+	}
+
+	info := make(map[string]Settings)
+	err = c.codec.Unmarshal(response, &info)
 	if err != nil {
-		return Settings{}, err
+		return map[string]Settings{}, err
 	}
 
-	return info[indexName], nil
+	return info, nil
 }
 
 // IndexExists allows to check if the index exists or not.
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-exists.html
-func (c *client) IndexExists(indexName string) (bool, error) {
-	url := c.Host.String() + "/" + indexName
-	httpClient := &http.Client{}
-	newReq, err := httpClient.Head(url)
+func (c *client) IndexExists(indexName string, opts ...RequestOption) (bool, error) {
+	options := newRequestOptions(opts)
+	return c.exists(options, options.url(c.Host.String()+"/"+c.indexPath(indexName)))
+}
+
+// TypeExists allows to check if a document type exists in the index or not.
+// Document types have been removed in Elasticsearch 8, an error is returned for a typeless client or server.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.8/indices-types-exists.html
+func (c *client) TypeExists(indexName, documentType string, opts ...RequestOption) (bool, error) {
+	if c.typeless || c.typelessServer() {
+		return false, errors.New("document types are not supported by typeless clients and servers")
+	}
+	options := newRequestOptions(opts)
+	return c.exists(options, options.url(c.Host.String()+"/"+c.indexPath(indexName)+"/_mapping/"+escapePath(documentType)))
+}
+
+// GetIndex returns the settings, mappings and aliases of the indices matching the name
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-index.html
+func (c *client) GetIndex(indexName string, opts ...RequestOption) (map[string]IndexDefinition, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indexName))
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return map[string]IndexDefinition{}, err
+	}
+
+	esResp := make(map[string]IndexDefinition)
+	err = c.codec.Unmarshal(response, &esResp)
+	if err != nil {
+		return map[string]IndexDefinition{}, err
+	}
+
+	return esResp, nil
+}
+
+// GetMapping retrieves the mapping definition of an index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-mapping.html
+func (c *client) GetMapping(indexName string, opts ...RequestOption) ([]byte, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indexName) + "/_mapping")
+	return c.sendHTTPRequest("GET", url, nil)
+}
+
+// GetMappings retrieves the mappings of the indices matching indexName, keyed by index name.
+// The mappings of the servers before Elasticsearch 7 are keyed by document type in Types, the
+// properties of an index with a single type being also set at the top level.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-mapping.html
+func (c *client) GetMappings(indexName string, opts ...RequestOption) (map[string]Mappings, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + "/" + c.indexPath(indexName) + "/_mapping")
+	indices := map[string]struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}{}
+	if err := c.sendJSONRequest(options, "GET", url, nil, &indices); err != nil {
+		return map[string]Mappings{}, err
+	}
+
+	version, ok := c.apiVersion()
+	typed := ok && version.Major < 7
+	mappings := make(map[string]Mappings, len(indices))
+	for name, index := range indices {
+		mapping := Mappings{}
+		if len(index.Mappings) == 0 {
+			mappings[name] = mapping
+			continue
+		}
+		if !typed {
+			if err := c.codec.Unmarshal(index.Mappings, &mapping); err != nil {
+				return map[string]Mappings{}, err
+			}
+			mappings[name] = mapping
+			continue
+		}
+		if err := c.codec.Unmarshal(index.Mappings, &mapping.Types); err != nil {
+			return map[string]Mappings{}, err
+		}
+		if len(mapping.Types) == 1 {
+			for _, typeMapping := range mapping.Types {
+				mapping.Dynamic = typeMapping.Dynamic
+				mapping.Meta = typeMapping.Meta
+				mapping.Properties = typeMapping.Properties
+			}
+		}
+		mappings[name] = mapping
+	}
+
+	return mappings, nil
+}
+
+// PutMapping adds new fields to an existing index or changes search only settings of existing fields
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-put-mapping.html
+func (c *client) PutMapping(indexName, mapping string, opts ...RequestOption) (*Response, error) {
+	url := newRequestOptions(opts).url(c.Host.String() + "/" + c.indexPath(indexName) + "/_mapping")
+	reader := bytes.NewBufferString(mapping)
+	response, err := c.sendHTTPRequest("PUT", url, reader)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// IndexTemplate retrieves an index template by name
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-template-v1.html
+func (c *client) IndexTemplate(name string) ([]byte, error) {
+	url := c.Host.String() + "/_template/" + escapePath(name)
+	return c.sendHTTPRequest("GET", url, nil)
+}
+
+// PutIndexTemplate creates or updates an index template applied automatically to new indices
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-templates-v1.html
+func (c *client) PutIndexTemplate(name, template string) (*Response, error) {
+	url := c.Host.String() + "/_template/" + escapePath(name)
+	reader := bytes.NewBufferString(template)
+	response, err := c.sendHTTPRequest("PUT", url, reader)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// Rollover creates a new write index for the alias when one of the conditions of the body is met,
+// such as {"conditions": {"max_age": "7d", "max_size": "50gb"}}, or unconditionally when empty.
+// WithParam("dry_run", "true") checks the conditions without rolling over.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-rollover-index.html
+func (c *client) Rollover(alias, body string, opts ...RequestOption) (*RolloverResult, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + "/" + c.indexPath(alias) + "/_rollover")
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	esResp := &RolloverResult{}
+	if err := c.sendJSONRequest(options, "POST", url, reader, esResp); err != nil {
+		return &RolloverResult{}, err
+	}
+
+	return esResp, nil
+}
+
+// Info returns the name, cluster and version of the search engine.
+// The first successful response is cached, the following calls don't reach the search engine.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rest-api-root.html
+func (c *client) Info() (*Status, error) {
+	// The product check caches the information of the server it gets, it must be done before locking
+	if err := c.checkProduct(); err != nil {
+		return &Status{}, err
+	}
+
+	c.infoMutex.Lock()
+	defer c.infoMutex.Unlock()
+	if c.info != nil {
+		return c.info, nil
+	}
+
+	url := c.Host.String() + "/"
+	status, response, err := c.sendStatusRequest(nil, "GET", url, nil)
+	if err != nil {
+		return &Status{}, err
+	}
+
+	esResp := &Status{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Status{}, err
+	}
+
+	// The error documents, such as the ones of an unavailable cluster, are not cached
+	if _, err := ParseVersion(esResp.Version.Number); status == http.StatusOK && err == nil {
+		c.setInfo(esResp)
+	}
+	return esResp, nil
+}
+
+// setInfo caches the information of the server, infoMutex being locked
+func (c *client) setInfo(info *Status) {
+	c.info = info
+	if info.Version.Distribution == "opensearch" {
+		atomic.StoreInt32(&c.detectedOpenSearch, 1)
+	}
+}
+
+// IsOpenSearch reports whether the client targets OpenSearch, as configured with WithOpenSearch
+// or detected from the distribution returned by Info
+func (c *client) IsOpenSearch() (bool, error) {
+	if c.targetsOpenSearch() {
+		return true, nil
+	}
+
+	info, err := c.Info()
 	if err != nil {
 		return false, err
 	}
+	return info.Version.Distribution == "opensearch", nil
+}
 
-	return newReq.StatusCode == http.StatusOK, nil
+// targetsOpenSearch reports whether OpenSearch has been configured or already detected
+func (c *client) targetsOpenSearch() bool {
+	return c.openSearch || atomic.LoadInt32(&c.detectedOpenSearch) == 1
 }
 
-// Status allows to get a comprehensive status information
+// Status allows to get a comprehensive status information.
+// The _status endpoint has been removed in Elasticsearch 2, _stats is used instead on later versions.
+//
+// Deprecated: use IndexStats for the statistics of indices, and ClusterHealth for their health.
 func (c *client) Status(indices string) (*Settings, error) {
-	url := c.Host.String() + "/" + indices + "/_status"
-	response, err := sendHTTPRequest("GET", url, nil)
+	url := c.Host.String() + "/" + c.indexPath(indices) + "/_status"
+	if version, ok := c.apiVersion(); ok && version.Major >= 2 {
+		url = c.Host.String() + "/" + c.indexPath(indices) + "/_stats"
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
 	if err != nil {
 		return &Settings{}, err
 	}
 
 	esResp := &Settings{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Settings{}, err
 	}
@@ -162,18 +504,46 @@ func (c *client) Status(indices string) (*Settings, error) {
 	return esResp, nil
 }
 
+// IndexStats returns the statistics of the comma-separated indices, of every index if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-stats.html
+func (c *client) IndexStats(indices string, opts ...RequestOption) (*IndexStats, error) {
+	url := c.Host.String() + "/_stats"
+	if indices != "" {
+		url = c.Host.String() + "/" + c.indexPath(indices) + "/_stats"
+	}
+	options := newRequestOptions(opts)
+	esResp := &IndexStats{}
+	if err := c.sendJSONRequest(options, "GET", options.url(url), nil, esResp); err != nil {
+		return &IndexStats{}, err
+	}
+
+	return esResp, nil
+}
+
 // InsertDocument adds or updates a typed JSON document in a specific index, making it searchable
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-index_.html
-func (c *client) InsertDocument(indexName, documentType, identifier string, data []byte) (*InsertDocument, error) {
-	url := c.Host.String() + "/" + indexName + "/_doc/" + identifier
-	reader := bytes.NewBuffer(data)
-	response, err := sendHTTPRequest("POST", url, reader)
+func (c *client) InsertDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + "/" + c.indexPath(indexName) + "/_doc/" + escapePath(identifier))
+	reader := bytes.NewReader(data)
+	esResp := &InsertDocument{}
+	err := c.sendJSONRequest(options, "POST", url, reader, esResp)
 	if err != nil {
 		return &InsertDocument{}, err
 	}
 
+	return esResp, nil
+}
+
+// CreateDocument adds a typed JSON document in a specific index only if it doesn't exist yet.
+// A *ConflictError is returned when a document with the same identifier exists.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-index_.html
+func (c *client) CreateDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + c.documentPath(indexName, documentType, "_create", identifier))
+	reader := bytes.NewReader(data)
 	esResp := &InsertDocument{}
-	err = json.Unmarshal(response, esResp)
+	err := c.sendJSONRequest(options, "PUT", url, reader, esResp)
 	if err != nil {
 		return &InsertDocument{}, err
 	}
@@ -181,17 +551,28 @@ func (c *client) InsertDocument(indexName, documentType, identifier string, data
 	return esResp, nil
 }
 
-// Document gets a typed JSON document from the index based on its id
-// http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-get.html
-func (c *client) Document(indexName, documentType, identifier string) (*Document, error) {
-	url := c.Host.String() + "/" + indexName + "/" + documentType + "/" + identifier
-	response, err := sendHTTPRequest("GET", url, nil)
+// UpdateDocument updates a document using a partial document or a script
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-update.html
+func (c *client) UpdateDocument(indexName, documentType, identifier string, data []byte, opts ...RequestOption) (*InsertDocument, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + c.documentPath(indexName, documentType, "_update", identifier))
+	reader := bytes.NewReader(data)
+	esResp := &InsertDocument{}
+	err := c.sendJSONRequest(options, "POST", url, reader, esResp)
 	if err != nil {
-		return &Document{}, err
+		return &InsertDocument{}, err
 	}
 
+	return esResp, nil
+}
+
+// Document gets a typed JSON document from the index based on its id
+// http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-get.html
+func (c *client) Document(indexName, documentType, identifier string, opts ...RequestOption) (*Document, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + c.documentPath(indexName, documentType, "", identifier))
 	esResp := &Document{}
-	err = json.Unmarshal(response, esResp)
+	err := c.sendJSONRequest(options, "GET", url, nil, esResp)
 	if err != nil {
 		return &Document{}, err
 	}
@@ -201,15 +582,16 @@ func (c *client) Document(indexName, documentType, identifier string) (*Document
 
 // DeleteDocument deletes a typed JSON document from a specific index based on its id
 // http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-delete.html
-func (c *client) DeleteDocument(indexName, documentType, identifier string) (*Document, error) {
-	url := c.Host.String() + "/" + indexName + "/" + documentType + "/" + identifier
-	response, err := sendHTTPRequest("DELETE", url, nil)
+func (c *client) DeleteDocument(indexName, documentType, identifier string, opts ...RequestOption) (*Document, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + c.documentPath(indexName, documentType, "", identifier))
+	response, err := c.sendRequest(options, "DELETE", url, nil)
 	if err != nil {
 		return &Document{}, err
 	}
 
 	esResp := &Document{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Document{}, err
 	}
@@ -217,19 +599,79 @@ func (c *client) DeleteDocument(indexName, documentType, identifier string) (*Do
 	return esResp, nil
 }
 
+// DocumentExists allows to check if a document exists or not.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-get.html
+func (c *client) DocumentExists(indexName, documentType, identifier string, opts ...RequestOption) (bool, error) {
+	options := newRequestOptions(opts)
+	return c.exists(options, options.url(c.Host.String()+c.documentPath(indexName, documentType, "", identifier)))
+}
+
+// DocumentSource gets the raw source of a document, without its metadata
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-get.html
+func (c *client) DocumentSource(indexName, documentType, identifier string, opts ...RequestOption) ([]byte, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + c.documentPath(indexName, documentType, "_source", identifier))
+	return c.sendRequest(options, "GET", url, nil)
+}
+
 // Bulk makes it possible to perform many index/delete operations in a single API call.
 // This can greatly increase the indexing speed.
 // http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-bulk.html
-func (c *client) Bulk(indexName string, data []byte) (*Bulk, error) {
-	url := c.Host.String() + "/" + indexName + "/_bulk"
-	reader := bytes.NewBuffer(data)
-	response, err := sendHTTPRequest("POST", url, reader)
+func (c *client) Bulk(indexName string, data []byte, opts ...RequestOption) (*Bulk, error) {
+	return c.bulk("/"+c.indexPath(indexName)+"/_bulk", data, opts)
+}
+
+// BulkIndexTarget is like Bulk, with every action targeting the index and the document type by default,
+// so that the action lines can omit _index and _type. The document type is dropped for a typeless client
+// or server, the requests being sent to /{index}/_bulk.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.8/docs-bulk.html
+func (c *client) BulkIndexTarget(indexName, documentType string, data []byte, opts ...RequestOption) (*Bulk, error) {
+	if indexName == "" {
+		return &Bulk{}, errors.New("the target index of the bulk request is missing")
+	}
+	path := "/" + c.indexPath(indexName)
+	if documentType != "" && !c.typeless && !c.typelessServer() {
+		path += "/" + escapePath(documentType)
+	}
+	return c.bulk(path+"/_bulk", data, opts)
+}
+
+// bulk sends the bulk request to the path, the index names of the action lines being namespaced
+func (c *client) bulk(path string, data []byte, opts []RequestOption) (*Bulk, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + path)
+	data, err := c.namespaceBulk(data)
+	if err != nil {
+		return &Bulk{}, err
+	}
+	reader := bytes.NewReader(data)
+	esResp := &Bulk{}
+	err = c.sendJSONRequest(options, "POST", url, reader, esResp)
+	if err != nil {
+		return &Bulk{}, err
+	}
+
+	return esResp, nil
+}
+
+// BulkReader is like Bulk, but streams the NDJSON payload from r instead of buffering it in memory.
+// A negative contentLength means the length of the payload is unknown.
+// http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-bulk.html
+func (c *client) BulkReader(indexName string, r io.Reader, contentLength int64, opts ...RequestOption) (*Bulk, error) {
+	options := newRequestOptions(opts)
+	url := options.url(c.Host.String() + "/" + c.indexPath(indexName) + "/_bulk")
+	if c.namespaced() {
+		// The length of the namespaced payload is unknown
+		r, contentLength = c.namespaceBulkReader(r), -1
+	}
+	req, compressed, cancel, err := c.newStreamRequest(options, "POST", url, r, contentLength)
 	if err != nil {
 		return &Bulk{}, err
 	}
+	defer cancel()
 
 	esResp := &Bulk{}
-	err = json.Unmarshal(response, esResp)
+	err = c.decode(req, compressed, esResp)
 	if err != nil {
 		return &Bulk{}, err
 	}
@@ -238,21 +680,24 @@ func (c *client) Bulk(indexName string, data []byte) (*Bulk, error) {
 }
 
 // Search allows to execute a search query and get back search hits that match the query
+// Options such as WithSize, WithRouting or WithPreference are sent as query string parameters.
 // http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/docs-delete.html
-func (c *client) Search(indexName, documentType, data string, explain bool) (*SearchResult, error) {
-
-	url := c.Host.String() + "/" + indexName + "/_search"
+func (c *client) Search(indexName, documentType, data string, explain bool, opts ...RequestOption) (*SearchResult, error) {
+	options := newRequestOptions(opts)
 	if explain {
-		url += "?explain"
+		options.params.Set("explain", "true")
 	}
-	reader := bytes.NewBufferString(data)
-	response, err := sendHTTPRequest("POST", url, reader)
-	if err != nil {
-		return &SearchResult{}, err
+	if c.searchTimeout > 0 && options.params.Get("timeout") == "" {
+		WithTimeout(c.searchTimeout)(options)
 	}
-
+	path := "/_search"
+	if indexName != "" {
+		path = "/" + c.indexPath(indexName) + path
+	}
+	url := options.url(c.Host.String() + path)
+	reader := strings.NewReader(data)
 	esResp := &SearchResult{}
-	err = json.Unmarshal(response, esResp)
+	err := c.sendJSONRequest(options, "POST", url, reader, esResp)
 	if err != nil {
 		return &SearchResult{}, err
 	}
@@ -260,26 +705,107 @@ func (c *client) Search(indexName, documentType, data string, explain bool) (*Se
 	return esResp, nil
 }
 
-// MSearch allows to execute a multi-search and get back result
+// MSearch allows to execute a multi-search and get back result.
+// A failing query doesn't fail the others, its response has the Status and the Error returned for it.
 // http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/search-multi-search.html
-func (c *client) MSearch(queries []MSearchQuery) (*MSearchResult, error) {
-	replacer := strings.NewReplacer("\n", " ")
-	queriesList := make([]string, len(queries))
-	for i, query := range queries {
-		queriesList[i] = query.Header + "\n" + replacer.Replace(query.Body)
-	}
+func (c *client) MSearch(queries []MSearchQuery, opts ...RequestOption) (*MSearchResult, error) {
+	return c.msearch("/_msearch", queries, newRequestOptions(opts))
+}
 
-	mSearchQuery := strings.Join(queriesList, "\n") + "\n" // Don't forget trailing \n
-	url := c.Host.String() + "/_msearch"
-	reader := bytes.NewBufferString(mSearchQuery)
-	response, err := sendHTTPRequest("POST", url, reader)
+// MSearchTemplate allows to execute several search templates within the same request
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/multi-search-template.html
+func (c *client) MSearchTemplate(queries []MSearchQuery, opts ...RequestOption) (*MSearchResult, error) {
+	return c.msearch("/_msearch/template", queries, newRequestOptions(opts))
+}
 
+// msearch sends the queries in a single request, or in batches of the size set by WithMSearchBatchSize.
+// The responses of a failing batch have the error of the batch, the other batches being kept.
+func (c *client) msearch(endpoint string, queries []MSearchQuery, options *requestOptions) (*MSearchResult, error) {
+	queries, err := c.namespaceMSearch(queries)
 	if err != nil {
 		return &MSearchResult{}, err
 	}
+	// The preference isn't a parameter of the endpoint but of each query
+	if preference := options.params.Get("preference"); preference != "" {
+		options.params.Del("preference")
+		if queries, err = c.msearchPreference(queries, preference); err != nil {
+			return &MSearchResult{}, err
+		}
+	}
+	if options.batchSize <= 0 || len(queries) <= options.batchSize {
+		return c.sendMSearch(endpoint, queries, options)
+	}
 
+	concurrency := options.concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	responses := make([]SearchResult, len(queries))
+	var wg sync.WaitGroup
+	for start := 0; start < len(queries); start += options.batchSize {
+		end := start + options.batchSize
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(batch []SearchResult, queries []MSearchQuery) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			result, err := c.sendMSearch(endpoint, queries, options)
+			if err == nil && len(result.Responses) != len(queries) {
+				err = fmt.Errorf("%d responses returned for %d queries", len(result.Responses), len(queries))
+			}
+			if err != nil {
+				for i := range batch {
+					batch[i] = SearchResult{Error: &ErrorCause{Type: "msearch_batch_failed", Reason: err.Error()}}
+				}
+				return
+			}
+			copy(batch, result.Responses)
+		}(responses[start:end], queries[start:end])
+	}
+	wg.Wait()
+
+	return &MSearchResult{Responses: responses}, nil
+}
+
+// msearchPreference sets the preference in the headers of the queries not having their own
+func (c *client) msearchPreference(queries []MSearchQuery, preference string) ([]MSearchQuery, error) {
+	withPreference := make([]MSearchQuery, len(queries))
+	for i, query := range queries {
+		header := map[string]json.RawMessage{}
+		if strings.TrimSpace(query.Header) != "" {
+			if err := c.codec.Unmarshal([]byte(query.Header), &header); err != nil {
+				return nil, fmt.Errorf("invalid header of query %d: %v", i, err)
+			}
+		}
+		if _, found := header["preference"]; !found {
+			header["preference"], _ = json.Marshal(preference)
+		}
+		encoded, err := json.Marshal(header)
+		if err != nil {
+			return nil, err
+		}
+		withPreference[i] = MSearchQuery{Header: string(encoded), Body: query.Body}
+	}
+	return withPreference, nil
+}
+
+func (c *client) sendMSearch(endpoint string, queries []MSearchQuery, options *requestOptions) (*MSearchResult, error) {
+	url := options.url(c.Host.String() + endpoint)
+	body, err := getMSearchQuery(queries)
+	if err != nil {
+		return &MSearchResult{}, err
+	}
+	reader := bytes.NewBuffer(body)
 	esResp := &MSearchResult{}
-	err = json.Unmarshal(response, esResp)
+	err = c.sendJSONRequest(options, "POST", url, reader, esResp)
 	if err != nil {
 		return &MSearchResult{}, err
 	}
@@ -287,52 +813,193 @@ func (c *client) MSearch(queries []MSearchQuery) (*MSearchResult, error) {
 	return esResp, nil
 }
 
+// Explain computes a score explanation for a query and a specific document
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-explain.html
+func (c *client) Explain(indexName, documentType, identifier, query string) (*ExplainResult, error) {
+	url := c.Host.String() + c.documentPath(indexName, documentType, "_explain", identifier)
+	reader := bytes.NewBufferString(query)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &ExplainResult{}, err
+	}
+
+	esResp := &ExplainResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &ExplainResult{}, err
+	}
+
+	return esResp, nil
+}
+
+// ValidateQuery validates a potentially expensive query without executing it.
+// The parse error is only returned by Elasticsearch when explain is set.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-validate.html
+func (c *client) ValidateQuery(indexName, documentType, query string, explain bool) (*ValidateResult, error) {
+	path := "/" + c.indexPath(indexName)
+	if documentType != "" && !c.typeless && !c.typelessServer() {
+		path += "/" + escapePath(documentType)
+	}
+	options := newRequestOptions(nil)
+	if explain {
+		options.params.Set("explain", "true")
+	}
+	url := options.url(c.Host.String() + path + "/_validate/query")
+	reader := bytes.NewBufferString(query)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &ValidateResult{}, err
+	}
+
+	esResp := &ValidateResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &ValidateResult{}, err
+	}
+
+	return esResp, nil
+}
+
+// RankEval evaluates the quality of ranked search results over a set of typical search queries
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-rank-eval.html
+func (c *client) RankEval(indices, data string) (*RankEvalResult, error) {
+	url := c.Host.String() + "/" + c.indexPath(indices) + "/_rank_eval"
+	reader := bytes.NewBufferString(data)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &RankEvalResult{}, err
+	}
+
+	esResp := &RankEvalResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &RankEvalResult{}, err
+	}
+
+	return esResp, nil
+}
+
 // Suggest allows basic auto-complete functionality.
+// data holds the named suggesters, such as the JSON encoding of Suggesters, which are sent under the suggest
+// section of a search request because the _suggest endpoint has been removed in Elasticsearch 6.
 // http://www.elasticsearch.org/guide/en/elasticsearch/reference/current/search-suggesters-completion.html
-func (c *client) Suggest(indexName, data string) ([]byte, error) {
-	url := c.Host.String() + "/" + indexName + "/_suggest"
-	reader := bytes.NewBufferString(data)
-	response, err := sendHTTPRequest("POST", url, reader)
-	return response, err
+func (c *client) Suggest(indexName, data string) (*SuggestResult, error) {
+	url := c.Host.String() + "/" + c.indexPath(indexName) + "/_search"
+	reader := bytes.NewBufferString(`{"size":0,"suggest":` + data + `}`)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &SuggestResult{}, err
+	}
+
+	esResp := &SuggestResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &SuggestResult{}, err
+	}
+
+	return esResp, nil
 }
 
 // GetIndicesFromAlias returns the list of indices the alias points to
 func (c *client) GetIndicesFromAlias(alias string) ([]string, error) {
-	url := c.Host.String() + "/*/_alias/" + alias
-	response, err := sendHTTPRequest("GET", url, nil)
+	url := c.Host.String() + "/*/_alias/" + c.indexPath(alias)
+	response, err := c.sendHTTPRequest("GET", url, nil)
 	if err != nil {
 		return []string{}, err
 	}
 
 	esResp := make(map[string]*json.RawMessage)
-	err = json.Unmarshal(response, &esResp)
+	err = c.codec.Unmarshal(response, &esResp)
 	if err != nil {
 		return []string{}, err
 	}
 
-	indices := make([]string, len(esResp))
-	i := 0
-	for k := range esResp {
-		indices[i] = k
-		i++
+	// A missing alias answers with an error document, only objects describe indices
+	indices := make([]string, 0, len(esResp))
+	for k, v := range esResp {
+		if v == nil || !bytes.HasPrefix(bytes.TrimSpace(*v), []byte("{")) {
+			continue
+		}
+		indices = append(indices, k)
 	}
 	return indices, nil
 }
 
+// GetAlias returns the metadata of the alias for each index it points to
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-get-alias.html
+func (c *client) GetAlias(alias string) (map[string]AliasInfo, error) {
+	url := c.Host.String() + "/_alias/" + c.indexPath(alias)
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return map[string]AliasInfo{}, err
+	}
+
+	esResp := make(map[string]json.RawMessage)
+	err = c.codec.Unmarshal(response, &esResp)
+	if err != nil {
+		return map[string]AliasInfo{}, err
+	}
+
+	aliases := make(map[string]AliasInfo, len(esResp))
+	for index, raw := range esResp {
+		// A missing alias answers with an error document, only objects describe indices
+		if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			continue
+		}
+
+		var info struct {
+			Aliases map[string]AliasInfo `json:"aliases"`
+		}
+		err = c.codec.Unmarshal(raw, &info)
+		if err != nil {
+			return map[string]AliasInfo{}, err
+		}
+		for _, aliasInfo := range info.Aliases {
+			aliases[index] = aliasInfo
+		}
+	}
+
+	return aliases, nil
+}
+
+// AliasExists allows to check if the alias exists or not.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-alias-exists.html
+func (c *client) AliasExists(alias string) (bool, error) {
+	return c.exists(nil, c.Host.String()+"/_alias/"+c.indexPath(alias))
+}
+
 // UpdateAlias updates the indices on which the alias point to.
 // The change is atomic.
 func (c *client) UpdateAlias(remove []string, add []string, alias string) (*Response, error) {
+	actions := make([]AliasAction, 0, len(remove)+len(add))
+	for _, index := range remove {
+		actions = append(actions, AliasAction{Type: AliasRemove, Index: index, Alias: alias})
+	}
+	for _, index := range add {
+		actions = append(actions, AliasAction{Type: AliasAdd, Index: index, Alias: alias})
+	}
+
+	return c.UpdateAliasActions(actions)
+}
+
+// UpdateAliasActions performs several alias actions, supporting filters, routing and write index.
+// The change is atomic.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-aliases.html
+func (c *client) UpdateAliasActions(actions []AliasAction) (*Response, error) {
 	url := c.Host.String() + "/_aliases"
-	body := getAliasQuery(remove, add, alias)
-	reader := bytes.NewBufferString(body)
+	body, err := c.codec.Marshal(map[string][]AliasAction{"actions": c.namespaceAliasActions(actions)})
+	if err != nil {
+		return &Response{}, err
+	}
+	reader := bytes.NewBuffer(body)
 
-	response, err := sendHTTPRequest("POST", url, reader)
+	response, err := c.sendHTTPRequest("POST", url, reader)
 	if err != nil {
 		return &Response{}, err
 	}
 
 	esResp := &Response{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &Response{}, err
 	}
@@ -343,15 +1010,15 @@ func (c *client) UpdateAlias(remove []string, add []string, alias string) (*Resp
 // UpdateByQuery updates documents that match the specified query.
 // https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-update-by-query.html
 func (c *client) UpdateByQuery(indexName, query string) (*UpdateByQueryResult, error) {
-	url := c.Host.String() + "/" + indexName + "/_update_by_query"
+	url := c.Host.String() + "/" + c.indexPath(indexName) + "/_update_by_query"
 	reader := bytes.NewBufferString(query)
-	response, err := sendHTTPRequest("POST", url, reader)
+	response, err := c.sendHTTPRequest("POST", url, reader)
 	if err != nil {
 		return &UpdateByQueryResult{}, err
 	}
 
 	esResp := &UpdateByQueryResult{}
-	err = json.Unmarshal(response, esResp)
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
 		return &UpdateByQueryResult{}, err
 	}
@@ -360,50 +1027,1146 @@ func (c *client) UpdateByQuery(indexName, query string) (*UpdateByQueryResult, e
 
 }
 
-func getAliasQuery(remove []string, add []string, alias string) string {
-	actions := make([]string, len(remove)+len(add))
+// DeleteByQuery deletes documents that match the specified query.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-delete-by-query.html
+func (c *client) DeleteByQuery(indexName, query string) (*DeleteByQueryResult, error) {
+	url := c.Host.String() + "/" + c.indexPath(indexName) + "/_delete_by_query"
+	reader := bytes.NewBufferString(query)
+	// The server errors passed through by sendStatusRequest are reported by Status and Error
+	status, response, err := c.sendStatusRequest(nil, "POST", url, reader)
+	if err != nil {
+		return &DeleteByQueryResult{}, err
+	}
+
+	esResp := &DeleteByQueryResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &DeleteByQueryResult{}, err
+	}
+	esResp.Status = status
+
+	return esResp, nil
+}
 
-	i := 0
-	for _, index := range remove {
-		actions[i] = "{ \"remove\": { \"index\": \"" + index + "\", \"alias\": \"" + alias + "\" }}"
-		i++
+// PutScript creates or updates a stored script
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/create-stored-script-api.html
+func (c *client) PutScript(id, script string) (*Response, error) {
+	url := c.Host.String() + "/_scripts/" + escapePath(id)
+	reader := bytes.NewBufferString(script)
+	response, err := c.sendHTTPRequest("PUT", url, reader)
+	if err != nil {
+		return &Response{}, err
 	}
 
-	for _, index := range add {
-		actions[i] = "{ \"add\": { \"index\": \"" + index + "\", \"alias\": \"" + alias + "\" }}"
-		i++
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
 	}
 
-	return "{\"actions\": [ " + strings.Join(actions, ",") + " ]}"
+	return esResp, nil
 }
 
-func sendHTTPRequest(method, url string, body io.Reader) ([]byte, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, body)
+// GetScript retrieves a stored script
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/get-stored-script-api.html
+func (c *client) GetScript(id string) (*StoredScript, error) {
+	url := c.Host.String() + "/_scripts/" + escapePath(id)
+	response, err := c.sendHTTPRequest("GET", url, nil)
 	if err != nil {
-		return nil, err
+		return &StoredScript{}, err
 	}
 
-	// if method == "POST" || method == "PUT" {
-	// 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	// }
+	esResp := &StoredScript{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &StoredScript{}, err
+	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return esResp, nil
+}
 
-	newReq, err := client.Do(req)
+// DeleteScript deletes a stored script
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/delete-stored-script-api.html
+func (c *client) DeleteScript(id string) (*Response, error) {
+	url := c.Host.String() + "/_scripts/" + escapePath(id)
+	response, err := c.sendHTTPRequest("DELETE", url, nil)
 	if err != nil {
-		return nil, err
+		return &Response{}, err
 	}
 
-	defer newReq.Body.Close()
-	response, err := ioutil.ReadAll(newReq.Body)
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
 	if err != nil {
-		return nil, err
+		return &Response{}, err
 	}
 
-	if newReq.StatusCode > http.StatusCreated && newReq.StatusCode < http.StatusNotFound {
-		return nil, errors.New(string(response))
+	return esResp, nil
+}
+
+// ExecutePainless executes a painless script against the context of the body, such as
+// {"script": {...}, "context": "filter", "context_setup": {"index": "orders", "document": {...}}},
+// to test a script before storing it. A failing script is returned as an error with its position.
+// https://www.elastic.co/guide/en/elasticsearch/painless/current/painless-execute-api.html
+func (c *client) ExecutePainless(body string) (*PainlessResult, error) {
+	esResp := &PainlessResult{}
+	if err := c.sendAPIRequest("POST", "/_scripts/painless/_execute", body, esResp); err != nil {
+		return &PainlessResult{}, err
 	}
 
+	return esResp, nil
+}
+
+// CreateSearchTemplate stores a mustache search template which can be referenced by its name.
+// The templates are stored as scripts from Elasticsearch 5.6, and with the former search template API before.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-template.html
+func (c *client) CreateSearchTemplate(name, template string) (*Response, error) {
+	if !c.legacySearchTemplates() {
+		return c.PutScript(name, searchTemplateScript(template))
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"template": searchTemplateSource(template)})
+	response, err := c.sendHTTPRequest("POST", c.Host.String()+"/_search/template/"+escapePath(name), bytes.NewReader(body))
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// GetSearchTemplate retrieves a stored search template, whatever the API it's been stored with
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-template.html
+func (c *client) GetSearchTemplate(name string) (*StoredScript, error) {
+	if !c.legacySearchTemplates() {
+		return c.GetScript(name)
+	}
+
+	response, err := c.sendHTTPRequest("GET", c.Host.String()+"/_search/template/"+escapePath(name), nil)
+	if err != nil {
+		return &StoredScript{}, err
+	}
+
+	legacy := struct {
+		ID       string          `json:"_id"`
+		Found    bool            `json:"found"`
+		Lang     string          `json:"lang"`
+		Template json.RawMessage `json:"template"`
+	}{}
+	err = c.codec.Unmarshal(response, &legacy)
+	if err != nil {
+		return &StoredScript{}, err
+	}
+
+	esResp := &StoredScript{ID: legacy.ID, Found: legacy.Found}
+	esResp.Script.Lang = legacy.Lang
+	esResp.Script.Source = string(legacy.Template)
+	// The template is a string when it's been stored as such, mustache sections making it invalid JSON
+	var source string
+	if c.codec.Unmarshal(legacy.Template, &source) == nil {
+		esResp.Script.Source = source
+	}
+
+	return esResp, nil
+}
+
+// DeleteSearchTemplate deletes a stored search template
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-template.html
+func (c *client) DeleteSearchTemplate(name string) (*Response, error) {
+	if !c.legacySearchTemplates() {
+		return c.DeleteScript(name)
+	}
+
+	response, err := c.sendHTTPRequest("DELETE", c.Host.String()+"/_search/template/"+escapePath(name), nil)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// legacySearchTemplates reports whether the server stores the search templates with the search template
+// API, replaced by the stored scripts in Elasticsearch 5.6
+func (c *client) legacySearchTemplates() bool {
+	version, ok := c.apiVersion()
+	return ok && !version.AtLeast(5, 6)
+}
+
+// RenderSearchTemplate renders a search template with its parameters, without executing the search
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/render-search-template-api.html
+func (c *client) RenderSearchTemplate(data string) (*RenderedTemplate, error) {
+	url := c.Host.String() + "/_render/template"
+	reader := bytes.NewBufferString(data)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &RenderedTemplate{}, err
+	}
+
+	esResp := &RenderedTemplate{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &RenderedTemplate{}, err
+	}
+
+	return esResp, nil
+}
+
+// searchTemplateScript wraps a template into a mustache stored script.
+// A JSON template is embedded as an object, anything else as a string.
+func searchTemplateScript(template string) string {
+	script := struct {
+		Script struct {
+			Lang   string      `json:"lang"`
+			Source interface{} `json:"source"`
+		} `json:"script"`
+	}{}
+	script.Script.Lang = "mustache"
+	script.Script.Source = searchTemplateSource(template)
+
+	body, _ := json.Marshal(script)
+	return string(body)
+}
+
+// searchTemplateSource returns the template as a JSON object when it's valid JSON, and as a string otherwise
+func searchTemplateSource(template string) interface{} {
+	if json.Valid([]byte(template)) {
+		return json.RawMessage(template)
+	}
+	return template
+}
+
+// NodesInfo retrieves the configuration of the nodes, restricted to the given metrics if any
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-nodes-info.html
+func (c *client) NodesInfo(metrics []string) (*NodesInfo, error) {
+	url := c.Host.String() + "/_nodes"
+	if len(metrics) > 0 {
+		url += "/" + strings.Join(metrics, ",")
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &NodesInfo{}, err
+	}
+
+	esResp := &NodesInfo{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &NodesInfo{}, err
+	}
+
+	return esResp, nil
+}
+
+// NodesStats retrieves the statistics of the nodes, restricted to the given metrics if any
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-nodes-stats.html
+func (c *client) NodesStats(metrics []string) (*NodesStats, error) {
+	url := c.Host.String() + "/_nodes/stats"
+	if len(metrics) > 0 {
+		url += "/" + strings.Join(metrics, ",")
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &NodesStats{}, err
+	}
+
+	esResp := &NodesStats{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &NodesStats{}, err
+	}
+
+	return esResp, nil
+}
+
+// PendingTasks returns the cluster-level changes which have not yet been executed
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-pending.html
+func (c *client) PendingTasks() (*PendingTasks, error) {
+	url := c.Host.String() + "/_cluster/pending_tasks"
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &PendingTasks{}, err
+	}
+
+	esResp := &PendingTasks{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &PendingTasks{}, err
+	}
+
+	return esResp, nil
+}
+
+// ClusterState returns the cluster state, restricted to comma-separated metrics and indices if any
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-state.html
+func (c *client) ClusterState(metrics, indices string) (*ClusterState, error) {
+	url := c.Host.String() + "/_cluster/state"
+	if metrics == "" && indices != "" {
+		metrics = "_all"
+	}
+	if metrics != "" {
+		url += "/" + metrics
+	}
+	if indices != "" {
+		url += "/" + c.indexPath(indices)
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &ClusterState{}, err
+	}
+
+	esResp := &ClusterState{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &ClusterState{}, err
+	}
+
+	return esResp, nil
+}
+
+// ClusterHealth returns the health of the cluster, or of the comma-separated indices if any
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-health.html
+func (c *client) ClusterHealth(indices string, opts ...RequestOption) (*ClusterHealth, error) {
+	url := c.Host.String() + "/_cluster/health"
+	if indices != "" {
+		url += "/" + c.indexPath(indices)
+	}
+	options := newRequestOptions(opts)
+	esResp := &ClusterHealth{}
+	if err := c.sendJSONRequest(options, "GET", options.url(url), nil, esResp); err != nil {
+		return &ClusterHealth{}, err
+	}
+
+	return esResp, nil
+}
+
+// healthStatuses ranks the health statuses, an index with a given status having the lower ones too
+var healthStatuses = map[string]int{"red": 0, "yellow": 1, "green": 2}
+
+// The interval between the health checks of WaitForStatus, and the time left to Elasticsearch
+// to answer once its wait has timed out
+var (
+	healthPollInterval  = 100 * time.Millisecond
+	healthRequestMargin = 5 * time.Second
+)
+
+// WaitForStatus blocks until the index reaches the status, yellow when its primary shards are
+// allocated and it can be used, or green when its replicas are allocated too. An index which
+// doesn't exist yet is waited for. An error is returned if the status isn't reached within timeout.
+func (c *client) WaitForStatus(indexName, status string, timeout time.Duration) (*ClusterHealth, error) {
+	expected, found := healthStatuses[status]
+	if !found {
+		return nil, fmt.Errorf("invalid health status %q, red, yellow or green expected", status)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		// Elasticsearch waits for the status itself, up to the remaining time
+		remaining := time.Until(deadline)
+		if remaining < time.Millisecond {
+			remaining = time.Millisecond
+		}
+		health, err := c.ClusterHealth(indexName,
+			WithParam("wait_for_status", status),
+			WithTimeout(remaining),
+			WithCallTimeout(remaining+healthRequestMargin),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !health.TimedOut && healthStatuses[health.Status] >= expected {
+			return health, nil
+		}
+		if time.Now().After(deadline) {
+			return health, fmt.Errorf("index %s is %s after %v, %s expected", indexName, health.Status, timeout, status)
+		}
+		time.Sleep(healthPollInterval)
+	}
+}
+
+// PostVotingConfigExclusions removes master-eligible nodes from the voting configuration, waiting up to
+// timeout (30s by default) for the removal, so that the nodes can be shut down without losing the quorum.
+// The exclusions are cleared with DeleteVotingConfigExclusions once the nodes have left the cluster.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/voting-config-exclusions.html
+func (c *client) PostVotingConfigExclusions(nodeNames []string, timeout time.Duration) error {
+	if len(nodeNames) == 0 {
+		return errors.New("no node to exclude from the voting configuration")
+	}
+	params := url.Values{}
+	if timeout > 0 {
+		params.Set("timeout", strconv.FormatInt(timeout.Milliseconds(), 10)+"ms")
+	}
+	path := "/_cluster/voting_config_exclusions"
+	// The nodes were given in the path before Elasticsearch 7.8
+	if version, ok := c.apiVersion(); ok && !version.AtLeast(7, 8) {
+		path += "/" + escapeIndices(strings.Join(nodeNames, ","))
+	} else {
+		params.Set("node_names", strings.Join(nodeNames, ","))
+	}
+	_, err := c.sendHTTPRequest("POST", c.Host.String()+path+"?"+params.Encode(), nil)
+	return err
+}
+
+// DeleteVotingConfigExclusions clears the voting configuration exclusions, waiting for the excluded
+// nodes to leave the cluster when waitForRemoval is true
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/voting-config-exclusions.html
+func (c *client) DeleteVotingConfigExclusions(waitForRemoval bool) error {
+	url := c.Host.String() + "/_cluster/voting_config_exclusions?wait_for_removal=" + strconv.FormatBool(waitForRemoval)
+	_, err := c.sendHTTPRequest("DELETE", url, nil)
+	return err
+}
+
+// ShrinkIndex shrinks an existing index into a new index with fewer primary shards
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-shrink-index.html
+func (c *client) ShrinkIndex(source, target, body string) (*Response, error) {
+	return c.resizeIndex("_shrink", source, target, body)
+}
+
+// SplitIndex splits an existing index into a new index with more primary shards
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-split-index.html
+func (c *client) SplitIndex(source, target, body string) (*Response, error) {
+	return c.resizeIndex("_split", source, target, body)
+}
+
+// CloneIndex clones an existing index into a new index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-clone-index.html
+func (c *client) CloneIndex(source, target, body string) (*Response, error) {
+	return c.resizeIndex("_clone", source, target, body)
+}
+
+func (c *client) resizeIndex(operation, source, target, body string) (*Response, error) {
+	url := c.Host.String() + "/" + c.indexPath(source) + "/" + operation + "/" + c.indexPath(target)
+	reader := bytes.NewBufferString(body)
+	response, err := c.sendHTTPRequest("POST", url, reader)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// IndexSegments returns the Lucene segments of the shards of the indices, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-segments.html
+func (c *client) IndexSegments(indices string) (*IndexSegments, error) {
+	url := c.Host.String() + "/_segments"
+	if indices != "" {
+		url = c.Host.String() + "/" + c.indexPath(indices) + "/_segments"
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &IndexSegments{}, err
+	}
+
+	esResp := &IndexSegments{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &IndexSegments{}, err
+	}
+
+	return esResp, nil
+}
+
+// IndexRecovery returns the recoveries of the shards of the indices by index, all of them if empty.
+// Only the ongoing recoveries are returned when activeOnly is true.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-recovery.html
+func (c *client) IndexRecovery(indices string, activeOnly bool) (map[string]IndexRecovery, error) {
+	url := c.Host.String() + "/_recovery"
+	if indices != "" {
+		url = c.Host.String() + "/" + c.indexPath(indices) + "/_recovery"
+	}
+	if activeOnly {
+		url += "?active_only=true"
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return map[string]IndexRecovery{}, err
+	}
+
+	esResp := make(map[string]IndexRecovery)
+	err = c.codec.Unmarshal(response, &esResp)
+	if err != nil {
+		return map[string]IndexRecovery{}, err
+	}
+
+	return esResp, nil
+}
+
+// ClearCache clears the caches of the indices, all of them if empty. The caches can be selected
+// by name (query, fielddata or request), every cache is cleared otherwise.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-clearcache.html
+func (c *client) ClearCache(indices string, caches ...string) (*BroadcastResponse, error) {
+	url := c.Host.String() + "/_cache/clear"
+	if indices != "" {
+		url = c.Host.String() + "/" + c.indexPath(indices) + "/_cache/clear"
+	}
+	if len(caches) > 0 {
+		params := make([]string, len(caches))
+		for i, cache := range caches {
+			params[i] = cache + "=true"
+		}
+		url += "?" + strings.Join(params, "&")
+	}
+	response, err := c.sendHTTPRequest("POST", url, nil)
+	if err != nil {
+		return &BroadcastResponse{}, err
+	}
+
+	esResp := &BroadcastResponse{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &BroadcastResponse{}, err
+	}
+
+	return esResp, nil
+}
+
+// FreezeIndex makes an index read-only and releases its memory, it's searched when
+// ignore_throttled is false. Frozen indices have been removed in Elasticsearch 8.
+// https://www.elastic.co/guide/en/elasticsearch/reference/7.17/freeze-index-api.html
+func (c *client) FreezeIndex(indexName string) (*Response, error) {
+	return c.freezeIndex(indexName, "_freeze")
+}
+
+// UnfreezeIndex makes a frozen index writable and searchable as usual
+// https://www.elastic.co/guide/en/elasticsearch/reference/7.17/unfreeze-index-api.html
+func (c *client) UnfreezeIndex(indexName string) (*Response, error) {
+	return c.freezeIndex(indexName, "_unfreeze")
+}
+
+func (c *client) freezeIndex(indexName, operation string) (*Response, error) {
+	url := c.Host.String() + "/" + c.indexPath(indexName) + "/" + operation
+	response, err := c.sendHTTPRequest("POST", url, nil)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// AddIndexBlock adds a block to the indices, such as BlockWrite to make them read-only
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/index-modules-blocks.html
+func (c *client) AddIndexBlock(indexName string, block IndexBlock) (*IndexBlockResult, error) {
+	url := c.Host.String() + "/" + c.indexPath(indexName) + "/_block/" + string(block)
+	response, err := c.sendHTTPRequest("PUT", url, nil)
+	if err != nil {
+		return &IndexBlockResult{}, err
+	}
+
+	esResp := &IndexBlockResult{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &IndexBlockResult{}, err
+	}
+
+	return esResp, nil
+}
+
+// ListDanglingIndices returns the indices found on the disks of the nodes but missing from the cluster state
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/dangling-indices-list.html
+func (c *client) ListDanglingIndices() (*DanglingIndices, error) {
+	url := c.Host.String() + "/_dangling"
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &DanglingIndices{}, err
+	}
+
+	esResp := &DanglingIndices{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &DanglingIndices{}, err
+	}
+
+	return esResp, nil
+}
+
+// ImportDanglingIndex imports a dangling index into the cluster. Elasticsearch cannot know whether
+// the data is up to date, acceptDataLoss must be true to acknowledge it.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/dangling-index-import.html
+func (c *client) ImportDanglingIndex(indexUUID string, acceptDataLoss bool) (*Response, error) {
+	return c.danglingIndex("POST", indexUUID, acceptDataLoss)
+}
+
+// DeleteDanglingIndex deletes a dangling index from the disks of the nodes.
+// acceptDataLoss must be true to acknowledge the data is lost.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/dangling-index-delete.html
+func (c *client) DeleteDanglingIndex(indexUUID string, acceptDataLoss bool) (*Response, error) {
+	return c.danglingIndex("DELETE", indexUUID, acceptDataLoss)
+}
+
+func (c *client) danglingIndex(method, indexUUID string, acceptDataLoss bool) (*Response, error) {
+	url := c.Host.String() + "/_dangling/" + escapePath(indexUUID) + "?accept_data_loss=" + strconv.FormatBool(acceptDataLoss)
+	response, err := c.sendHTTPRequest(method, url, nil)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	esResp := &Response{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+// DeprecationInfo returns the deprecated settings and features in use in the cluster, and in the index if any,
+// to check a cluster can be upgraded to the next major version
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/migration-api-deprecation.html
+func (c *client) DeprecationInfo(indexName string) (*DeprecationInfo, error) {
+	url := c.Host.String() + "/_migration/deprecations"
+	if indexName != "" {
+		url = c.Host.String() + "/" + c.indexPath(indexName) + "/_migration/deprecations"
+	}
+	response, err := c.sendHTTPRequest("GET", url, nil)
+	if err != nil {
+		return &DeprecationInfo{}, err
+	}
+
+	esResp := &DeprecationInfo{}
+	err = c.codec.Unmarshal(response, esResp)
+	if err != nil {
+		return &DeprecationInfo{}, err
+	}
+
+	return esResp, nil
+}
+
+// getMSearchQuery builds the NDJSON body of a multi search, each header and body being
+// compacted on a single line. An empty header targets the index of the request.
+func getMSearchQuery(queries []MSearchQuery) ([]byte, error) {
+	var buffer bytes.Buffer
+	for i, query := range queries {
+		header := query.Header
+		if strings.TrimSpace(header) == "" {
+			header = "{}"
+		}
+		if err := json.Compact(&buffer, []byte(header)); err != nil {
+			return nil, fmt.Errorf("invalid header of query %d: %v", i, err)
+		}
+		buffer.WriteByte('\n')
+
+		if err := json.Compact(&buffer, []byte(query.Body)); err != nil {
+			return nil, fmt.Errorf("invalid body of query %d: %v", i, err)
+		}
+		buffer.WriteByte('\n') // Don't forget trailing \n
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Do sends a request to any endpoint of Elasticsearch, such as the ones not modeled by the client yet.
+// The response is returned whatever its status code, the caller must close its body.
+// The request is checked and sent like the other ones: it fails for an unsupported product, is canceled
+// after the default timeout of the client, see WithRequestTimeout, and has the default parameters of the
+// client missing from params, such as the filter_path of WithDefaultFilterPath.
+func (c *client) Do(method, path string, params url.Values, body io.Reader) (*http.Response, error) {
+	url := c.Host.String() + "/" + strings.TrimPrefix(path, "/")
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	req, compressed, cancel, err := c.newRequest(nil, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.roundTrip(req, compressed)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The response is read by the caller, the context is canceled once its body is closed
+	response.Body = &cancelBody{ReadCloser: response.Body, cancel: cancel}
 	return response, nil
 }
+
+// cancelBody cancels the context of a request when its response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// sendAPIRequest sends a request to path, with a JSON body if not empty, and decodes the response into result
+func (c *client) sendAPIRequest(method, path, body string, result interface{}) error {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	response, err := c.sendHTTPRequest(method, c.Host.String()+path, reader)
+	if err != nil {
+		return err
+	}
+
+	return c.codec.Unmarshal(response, result)
+}
+
+// acknowledge is like sendAPIRequest, for the APIs answering with an acknowledgement
+func (c *client) acknowledge(method, path, body string) (*Response, error) {
+	esResp := &Response{}
+	if err := c.sendAPIRequest(method, path, body, esResp); err != nil {
+		return &Response{}, err
+	}
+
+	return esResp, nil
+}
+
+func (c *client) sendHTTPRequest(method, url string, body io.Reader) ([]byte, error) {
+	return c.sendRequest(nil, method, url, body)
+}
+
+// sendRequest is like sendHTTPRequest, with the context and timeout of the request options
+func (c *client) sendRequest(options *requestOptions, method, url string, body io.Reader) ([]byte, error) {
+	req, compressed, cancel, err := c.newRequest(options, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	return c.do(req, compressed)
+}
+
+// sendStatusRequest is like sendRequest, and returns the status of the response passed through by open
+func (c *client) sendStatusRequest(options *requestOptions, method, url string, body io.Reader) (int, []byte, error) {
+	req, compressed, cancel, err := c.newRequest(options, method, url, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cancel()
+
+	response, reader, err := c.open(req, compressed)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	return response.StatusCode, data, err
+}
+
+// sendJSONRequest is like sendRequest, but decodes the response into v while it's read
+func (c *client) sendJSONRequest(options *requestOptions, method, url string, body io.Reader, v interface{}) error {
+	req, compressed, cancel, err := c.newRequest(options, method, url, body)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return c.decode(req, compressed, v)
+}
+
+// requestContext returns the context of a request, with the timeout of the request options
+// or the default timeout of the client. The options may be nil.
+func (c *client) requestContext(options *requestOptions) (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	timeout := c.timeout
+	if options != nil {
+		if options.ctx != nil {
+			ctx = options.ctx
+		}
+		if options.hasTimeout {
+			timeout = options.timeout
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// newRequest creates a request, compressing its body when enabled. The body read to be compressed
+// is held by a pooled buffer, put back in the pool when the transport closes it.
+// cancel releases the context of the request, once its response is read.
+func (c *client) newRequest(options *requestOptions, method, url string, body io.Reader) (*http.Request, bool, context.CancelFunc, error) {
+	if err := c.checkProduct(); err != nil {
+		return nil, false, func() {}, err
+	}
+	ctx, cancel := c.requestContext(options)
+	url = c.withDefaultParams(url)
+	if body == nil || !c.compressRequests {
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			cancel()
+		}
+		return req, false, cancel, err
+	}
+
+	buffer, compressed, err := c.compressBody(body)
+	if err != nil {
+		cancel()
+		return nil, false, cancel, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		putBuffer(buffer)
+		cancel()
+		return nil, false, cancel, err
+	}
+	if buffer.Len() == 0 {
+		putBuffer(buffer)
+		return req, compressed, cancel, nil
+	}
+	req.Body = &pooledBody{Buffer: buffer}
+	req.ContentLength = int64(buffer.Len())
+	return req, compressed, cancel, nil
+}
+
+// withDefaultParams appends the default parameters of the client missing from the request URL
+func (c *client) withDefaultParams(rawurl string) string {
+	if len(c.defaultParams) == 0 {
+		return rawurl
+	}
+	query := ""
+	if i := strings.Index(rawurl, "?"); i >= 0 {
+		query = rawurl[i+1:]
+	}
+	present, _ := url.ParseQuery(query)
+	missing := url.Values{}
+	for key, values := range c.defaultParams {
+		if _, found := present[key]; !found {
+			missing[key] = values
+		}
+	}
+	if len(missing) == 0 {
+		return rawurl
+	}
+	if strings.Contains(rawurl, "?") {
+		return rawurl + "&" + missing.Encode()
+	}
+	return rawurl + "?" + missing.Encode()
+}
+
+// newStreamRequest creates a request whose body is read while it's sent, without buffering it.
+// A negative contentLength means the length is unknown.
+func (c *client) newStreamRequest(options *requestOptions, method, url string, body io.Reader, contentLength int64) (*http.Request, bool, context.CancelFunc, error) {
+	if err := c.checkProduct(); err != nil {
+		return nil, false, func() {}, err
+	}
+	ctx, cancel := c.requestContext(options)
+	url = c.withDefaultParams(url)
+	compressed := c.compressRequests && (contentLength < 0 || contentLength >= int64(c.compressionMinSize))
+	if compressed {
+		body = gzipStream(body)
+		contentLength = -1
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		cancel()
+		return nil, false, cancel, err
+	}
+	req.ContentLength = contentLength
+	return req, compressed, cancel, nil
+}
+
+// exists sends a HEAD request, the resource existing when the status is 200 and missing when it's 404.
+// The other statuses, such as 401 on a secured cluster, are returned as errors.
+func (c *client) exists(options *requestOptions, url string) (bool, error) {
+	req, compressed, cancel, err := c.newRequest(options, "HEAD", url, nil)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+
+	response, err := c.roundTrip(req, compressed)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %s", response.Status)
+	}
+}
+
+func (c *client) do(req *http.Request, compressed bool) ([]byte, error) {
+	response, body, err := c.open(req, compressed)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return ioutil.ReadAll(body)
+}
+
+// decode sends the request and decodes the response into v, streaming it when the codec
+// supports it and buffering it in a pooled buffer otherwise
+func (c *client) decode(req *http.Request, compressed bool, v interface{}) error {
+	response, body, err := c.open(req, compressed)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if codec, ok := c.codec.(StreamCodec); ok {
+		if err := codec.Decode(body, v); err != nil {
+			return err
+		}
+		// Drain the body to reuse the connection
+		_, err := io.Copy(ioutil.Discard, body)
+		return err
+	}
+
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+	if _, err := buffer.ReadFrom(body); err != nil {
+		return err
+	}
+	return c.codec.Unmarshal(buffer.Bytes(), v)
+}
+
+// open sends the request and returns the response with its decompressed body.
+// The error statuses are returned as errors, the body of the response being read and closed.
+func (c *client) open(req *http.Request, compressed bool) (*http.Response, io.Reader, error) {
+	newReq, responseBody, err := c.respond(req, compressed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if newReq.StatusCode >= http.StatusMultipleChoices && newReq.StatusCode < http.StatusNotFound || newReq.StatusCode == http.StatusConflict {
+		defer newReq.Body.Close()
+		response, err := ioutil.ReadAll(responseBody)
+		if err != nil {
+			return nil, nil, err
+		}
+		if newReq.StatusCode == http.StatusConflict {
+			return nil, nil, &ConflictError{Response: string(response)}
+		}
+		return nil, nil, errors.New(string(response))
+	}
+
+	return newReq, responseBody, nil
+}
+
+// respond sends the request and returns the response with its decompressed body, whatever its status
+func (c *client) respond(req *http.Request, compressed bool) (*http.Response, io.Reader, error) {
+	if c.compressResponses {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	newReq, err := c.roundTrip(req, compressed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responseBody io.Reader = newReq.Body
+	if newReq.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(newReq.Body)
+		if err != nil {
+			newReq.Body.Close()
+			return nil, nil, err
+		}
+		responseBody = gzipReader
+	}
+	return newReq, responseBody, nil
+}
+
+// documentPath returns the path of a document API, such as /{index}/_doc/{id} or /{index}/_source/{id}.
+// The document type is only used when the client is not typeless and the server still supports
+// document types, with the API after the identifier.
+func (c *client) documentPath(indexName, documentType, api, identifier string) string {
+	if c.typeless || documentType == "" || c.typelessServer() {
+		if api == "" {
+			api = "_doc"
+		}
+		return "/" + c.indexPath(indexName) + "/" + api + "/" + escapePath(identifier)
+	}
+
+	path := "/" + c.indexPath(indexName) + "/" + escapePath(documentType) + "/" + escapePath(identifier)
+	if api != "" {
+		path += "/" + api
+	}
+	return path
+}
+
+// escapePath escapes a segment of a path, such as a document identifier containing a slash
+func escapePath(segment string) string {
+	return url.PathEscape(segment)
+}
+
+// escapeIndices escapes a segment listing indices or aliases, keeping the commas separating
+// them and the wildcards readable. The plus sign of the time zone of a date math name, such
+// as <logs-{now/d{yyyy.MM.dd|+01:00}}>, is escaped as it would be decoded as a space.
+func escapeIndices(indices string) string {
+	return strings.NewReplacer("%2C", ",", "%2A", "*", "+", "%2B").Replace(url.PathEscape(indices))
+}
+
+// typelessServer reports whether the server rejects document types, from Elasticsearch 8.
+// OpenSearch is considered typeless as types are deprecated since its first version.
+func (c *client) typelessServer() bool {
+	if c.targetsOpenSearch() {
+		return true
+	}
+	version, ok := c.apiVersion()
+	return ok && version.Major >= 8
+}
+
+// roundTrip sends the request with the headers common to every request
+func (c *client) roundTrip(req *http.Request, compressed bool) (*http.Response, error) {
+	// if method == "POST" || method == "PUT" {
+	// 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// }
+
+	req.Header.Set("Content-Type", "application/json")
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgentHeader)
+	}
+	// The client meta header is specific to Elasticsearch
+	if !c.targetsOpenSearch() {
+		req.Header.Set("x-elastic-client-meta", c.clientMetaHeader)
+	}
+	// OpenSearch doesn't support the compatibility media types of Elasticsearch
+	if c.compatibleWith > 0 && !c.targetsOpenSearch() {
+		mediaType := "application/vnd.elasticsearch+json; compatible-with=" + strconv.Itoa(c.compatibleWith)
+		req.Header.Set("Content-Type", mediaType)
+		req.Header.Set("Accept", mediaType)
+	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	var body []byte
+	if c.maxRetries > 0 && req.Body != nil {
+		var err error
+		if body, err = readRequestBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	var waited time.Duration
+	for retries := 0; ; retries++ {
+		response, err := c.send(req, retries)
+		if err != nil || response.StatusCode != http.StatusTooManyRequests || c.maxRetries == 0 {
+			return response, err
+		}
+
+		wait, retry := c.retryWait(response, retries)
+		if !retry {
+			defer response.Body.Close()
+			raw, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				return nil, err
+			}
+			raw, err = decodeBody(raw, response.Header.Get("Content-Encoding"))
+			if err != nil {
+				return nil, err
+			}
+			return nil, &TooManyRequestsError{Attempts: retries + 1, Wait: waited, Response: string(raw)}
+		}
+		// Drain the body to reuse the connection
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+		waited += wait
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+	}
+}
+
+// send signs and sends the request once allowed by the rate limiter, reporting its metrics
+func (c *client) send(req *http.Request, retries int) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(req.Context(), endpointName(req.Method, req.URL.Path)); err != nil {
+			return nil, err
+		}
+	}
+	if c.signer != nil {
+		if err := c.signer.Sign(req); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	response, err := c.httpClient.Do(req)
+	if c.metrics != nil {
+		metrics := RequestMetrics{
+			Endpoint: endpointName(req.Method, req.URL.Path),
+			Duration: time.Since(start),
+			Retries:  retries,
+			Err:      err,
+		}
+		if response != nil {
+			metrics.StatusCode = response.StatusCode
+		}
+		c.metrics(metrics)
+	}
+	return response, err
+}
+
+// compressBody gzips the body when it's at least compressionMinSize bytes long.
+// The returned buffer comes from the pool, and must be put back once the request is sent.
+func (c *client) compressBody(body io.Reader) (*bytes.Buffer, bool, error) {
+	data := getBuffer()
+	if _, err := data.ReadFrom(body); err != nil {
+		putBuffer(data)
+		return nil, false, err
+	}
+	if data.Len() < c.compressionMinSize {
+		return data, false, nil
+	}
+	defer putBuffer(data)
+
+	buffer := getBuffer()
+	writer := getGzipWriter(buffer)
+	defer putGzipWriter(writer)
+	if _, err := writer.Write(data.Bytes()); err != nil {
+		putBuffer(buffer)
+		return nil, false, err
+	}
+	if err := writer.Close(); err != nil {
+		putBuffer(buffer)
+		return nil, false, err
+	}
+	return buffer, true, nil
+}
+
+// gzipStream compresses body while it's read
+func gzipStream(body io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		gzipWriter := gzip.NewWriter(writer)
+		_, err := io.Copy(gzipWriter, body)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
@@ -3,6 +3,10 @@ package elasticsearch_test
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,7 +50,7 @@ func TestIndexManagement(t *testing.T) {
 	if response, _ := client.IndexExists(IndexName); response {
 		delReponse, err := client.DeleteIndex(IndexName)
 		helper.OK(t, err)
-		helper.Assert(t, delReponse.Acknowledged, "Unable to remove existing index:"+delReponse.Error)
+		helper.Assert(t, delReponse.Acknowledged, "Unable to remove existing index: %v", delReponse.Error)
 	}
 
 	//Check if we have the test index
@@ -168,8 +172,8 @@ func TestSearch(t *testing.T) {
 	//MSearch
 
 	mqueries := make([]elasticsearch.MSearchQuery, 2)
-	mqueries[0] = elasticsearch.MSearchQuery{Header: `{ "index":` + IndexName + `, "type":"` + ProductDocumentType + `" }`, Body: `{ "query": {"match_all" : {}}, "from" : 0, "size" : 1} }`}
-	mqueries[1] = elasticsearch.MSearchQuery{Header: `{ "index":` + IndexName + `, "type":"` + ProductDocumentType + `" }`, Body: `{"query": {"match_all" : {}}, "from" : 0, "size" : 2}}`}
+	mqueries[0] = elasticsearch.MSearchQuery{Header: `{ "index":"` + IndexName + `", "type":"` + ProductDocumentType + `" }`, Body: `{ "query": {"match_all" : {}}, "from" : 0, "size" : 1}`}
+	mqueries[1] = elasticsearch.MSearchQuery{Header: `{ "index":"` + IndexName + `", "type":"` + ProductDocumentType + `" }`, Body: `{"query": {"match_all" : {}}, "from" : 0, "size" : 2}`}
 
 	msresult, err := client.MSearch(mqueries)
 	helper.OK(t, err)
@@ -219,26 +223,6 @@ func TestSuggestion(t *testing.T) {
 		Name InputSuggester `json:"name_suggest"`
 	}
 
-	type OutputSuggester struct {
-		Text    string           `json:"text"`
-		Score   float32          `json:"score"`
-		Payload PayLoadSuggester `json:"payload"`
-	}
-
-	type SuggestionResult struct {
-		Shards struct {
-			Total      int `json:"total"`
-			Successful int `json:"successful"`
-			Failed     int `json:"failed"`
-		} `json:"_shards"`
-		Suggestion []struct {
-			Text    string            `json:"text"`
-			Offset  float32           `json:"offset"`
-			Lenght  int               `json:"length"`
-			Options []OutputSuggester `json:"options"`
-		} `json:"suggestion"`
-	}
-
 	helper := Test{}
 	client := elasticsearch.NewClient(ESScheme, ESHost, ESPort)
 	client.CreateIndex(SuggestionIndexName, SuggestionIndexMapping)
@@ -261,11 +245,7 @@ func TestSuggestion(t *testing.T) {
 	//Suggest
 	suggestResponse, err := client.Suggest(SuggestionIndexName, SuggestByTermQuery("jean"))
 	helper.OK(t, err)
-
-	var s SuggestionResult
-	err = json.Unmarshal(suggestResponse, &s)
-	helper.OK(t, err)
-	helper.Assert(t, s.Shards.Failed == 0, "No suggestion inserted")
+	helper.Assert(t, suggestResponse.Shards.Failed == 0, "No suggestion inserted")
 
 	//Delete the index
 	deleteResponse, err := client.DeleteIndex(SuggestionIndexName)
@@ -284,3 +264,448 @@ func SuggestByTermQuery(term string) string {
 				}
 			}`
 }
+
+func TestExists(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "ApiKey c2VjcmV0" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+			w.Write([]byte(`{"version":{"number":"7.17.0"},"tagline":"You Know, for Search"}`))
+		case "/orders", "/orders/_mapping/order", "/_alias/orders-current":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithSigner(elasticsearch.APIKeySigner("c2VjcmV0")))
+	exists, err := client.IndexExists("orders", elasticsearch.WithExpandWildcards("open"))
+	helper.OK(t, err)
+	helper.Assert(t, exists, "The index is expected to exist")
+
+	exists, err = client.IndexExists("customers")
+	helper.OK(t, err)
+	helper.Assert(t, !exists, "The index isn't expected to exist")
+
+	exists, err = client.TypeExists("orders", "order")
+	helper.OK(t, err)
+	helper.Assert(t, exists, "The type is expected to exist")
+
+	exists, err = client.AliasExists("orders-current")
+	helper.OK(t, err)
+	helper.Assert(t, exists, "The alias is expected to exist")
+
+	helper.Equals(t, "HEAD /orders?expand_wildcards=open", requests[0])
+	helper.Equals(t, "HEAD /orders/_mapping/order", requests[len(requests)-2])
+
+	_, err = elasticsearch.NewClientFromUrl(server.URL).IndexExists("orders")
+	helper.Assert(t, err != nil, "An unauthorized request is expected to fail")
+
+	_, err = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithTypeless()).TypeExists("orders", "order")
+	helper.Assert(t, err != nil, "Types are expected to be rejected by a typeless client")
+}
+
+func TestIndexStats(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/orders/_stats", http.StatusOK, `{"_shards":{"total":2,"successful":1,"failed":0},
+		"_all":{"primaries":{"docs":{"count":42,"deleted":2}},"total":{"docs":{"count":84,"deleted":4}}},
+		"indices":{"orders":{"uuid":"x1","primaries":{"docs":{"count":42},"store":{"size_in_bytes":1024},"search":{"query_total":7}}}}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	stats, err := client.IndexStats("orders", elasticsearch.WithParam("level", "indices"))
+	helper.OK(t, err)
+	helper.Equals(t, int64(1), stats.Shards.Successful)
+	helper.Equals(t, int64(84), stats.All.Total.Docs.Count)
+	helper.Equals(t, int64(1024), stats.Indices["orders"].Primaries.Store.SizeInBytes)
+	helper.Equals(t, int64(7), stats.Indices["orders"].Primaries.Search.QueryTotal)
+
+	_, err = client.IndexStats("")
+	helper.OK(t, err)
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /orders/_stats?level=indices", requests[0].String())
+	helper.Equals(t, "GET /_stats", requests[1].String())
+}
+
+func TestVotingConfigExclusions(t *testing.T) {
+	helper := Test{}
+	for version, path := range map[string]string{
+		"7.17.0": "/_cluster/voting_config_exclusions?node_names=master-1%2Cmaster-2&timeout=60000ms",
+		"7.4.0":  "/_cluster/voting_config_exclusions/master-1,master-2?timeout=60000ms",
+	} {
+		recorder := elasticsearch.NewRequestRecorder()
+		recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"`+version+`"},"tagline":"You Know, for Search"}`)
+		recorder.Respond("", "/_cluster/voting_config_exclusions", http.StatusOK, "")
+		client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+		helper.OK(t, client.PostVotingConfigExclusions([]string{"master-1", "master-2"}, time.Minute))
+		helper.OK(t, client.DeleteVotingConfigExclusions(false))
+		requests := recorder.Requests()
+		helper.Equals(t, "POST "+path, requests[len(requests)-2].String())
+		helper.Equals(t, "DELETE /_cluster/voting_config_exclusions?wait_for_removal=false", requests[len(requests)-1].String())
+	}
+
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(elasticsearch.NewRequestRecorder()))
+	helper.Assert(t, client.PostVotingConfigExclusions(nil, 0) != nil, "An exclusion without node is expected to be rejected")
+}
+
+func TestExecutePainless(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.Contains(body, []byte("doc['missing']")) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"type":"script_exception","reason":"runtime error","script":"doc['missing'].value > 10","position":{"offset":4,"start":0,"end":25}},"status":400}`))
+			return
+		}
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	result, err := client.ExecutePainless(`{"script":{"source":"doc['total'].value > params.min","params":{"min":10}},"context":"filter","context_setup":{"index":"orders","document":{"total":12}}}`)
+	helper.OK(t, err)
+	helper.Equals(t, "true", string(result.Result))
+
+	_, err = client.ExecutePainless(`{"script":{"source":"doc['missing'].value > 10"},"context":"filter","context_setup":{"index":"orders","document":{}}}`)
+	helper.Assert(t, err != nil && strings.Contains(err.Error(), "script_exception"), "A failing script is expected to be returned as an error")
+}
+
+func TestMSearchTemplate(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/_msearch/template", http.StatusOK, `{"responses":[
+		{"took":2,"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_index":"staging-products","_id":"1","_score":1.2}]},"status":200},
+		{"error":{"type":"illegal_argument_exception","reason":"unable to find script [missing]"},"status":400}]}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	result, err := client.MSearchTemplate([]elasticsearch.MSearchQuery{
+		{Header: `{"index":"products"}`, Body: `{"id":"by-color","params":{"color":"red"}}`},
+		{Header: `{}`, Body: `{"id":"missing"}`},
+	})
+	helper.OK(t, err)
+	helper.Equals(t, "POST /_msearch/template\n"+
+		`{"index":"staging-products"}`+"\n"+`{"id":"by-color","params":{"color":"red"}}`+"\n"+
+		`{}`+"\n"+`{"id":"missing"}`+"\n", recorder.Requests()[0].String())
+	helper.Equals(t, 2, len(result.Responses))
+	helper.Equals(t, int64(1), result.Responses[0].Hits.Total.Value)
+	helper.Equals(t, "1", result.Responses[0].Hits.Hits[0].ID)
+	helper.Equals(t, 400, result.Responses[1].Status)
+	helper.Equals(t, "illegal_argument_exception", result.Responses[1].Error.Type)
+}
+
+func TestExplain(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"6.8.0"},"tagline":"You Know, for Search"}`)
+	recorder.Respond("POST", "/test/product/1/_explain", http.StatusOK, `{"_index":"test","_type":"product","_id":"1","matched":true,
+		"explanation":{"value":1.6943598,"description":"weight(Name:shirt in 0)","details":[{"value":2.2,"description":"idf","details":[]}]}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	result, err := client.Explain(IndexName, "product", "1", `{"query":{"match":{"Name":"shirt"}}}`)
+	helper.OK(t, err)
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /test/product/1/_explain\n"+`{"query":{"match":{"Name":"shirt"}}}`, requests[len(requests)-1].String())
+	helper.Assert(t, result.Matched, "The document is expected to match")
+	helper.Equals(t, float32(1.6943598), result.Explanation.Value)
+	helper.Equals(t, "weight(Name:shirt in 0)", result.Explanation.Description)
+	helper.Equals(t, "idf", result.Explanation.Details[0].Description)
+}
+
+func TestValidateQuery(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/test/_validate/query", http.StatusOK, `{"valid":false,"_shards":{"total":1,"successful":1,"failed":0},
+		"explanations":[{"index":"test","valid":false,"error":"org.elasticsearch.common.ParsingException: unknown query [mtch]"}]}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithTypeless())
+
+	result, err := client.ValidateQuery(IndexName, ProductDocumentType, `{"query":{"mtch":{"Name":"shirt"}}}`, true)
+	helper.OK(t, err)
+	helper.Equals(t, "POST /test/_validate/query?explain=true\n"+`{"query":{"mtch":{"Name":"shirt"}}}`, recorder.Requests()[0].String())
+	helper.Assert(t, !result.Valid, "The query isn't expected to be valid")
+	helper.Equals(t, int64(1), result.Shards.Successful)
+	helper.Equals(t, "org.elasticsearch.common.ParsingException: unknown query [mtch]", result.ParseError())
+
+	recorder = elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"6.8.0"},"tagline":"You Know, for Search"}`)
+	recorder.Respond("POST", "/test/product/_validate/query", http.StatusOK, `{"valid":true,"_shards":{"total":1,"successful":1,"failed":0}}`)
+	result, err = elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder)).
+		ValidateQuery(IndexName, "product", `{"query":{"match_all":{}}}`, false)
+	helper.OK(t, err)
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /test/product/_validate/query\n"+`{"query":{"match_all":{}}}`, requests[len(requests)-1].String())
+	helper.Assert(t, result.Valid, "The query is expected to be valid")
+	helper.Equals(t, "", result.ParseError())
+}
+
+func TestValidateQueryTypelessServer(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"8.11.0"},"tagline":"You Know, for Search"}`)
+	recorder.Respond("POST", "/test/_validate/query", http.StatusOK, `{"valid":true,"_shards":{"total":1,"successful":1,"failed":0}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	// The document type is dropped for Elasticsearch 8, which rejects it
+	result, err := client.ValidateQuery(IndexName, "product", `{"query":{"match_all":{}}}`, false)
+	helper.OK(t, err)
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /test/_validate/query\n"+`{"query":{"match_all":{}}}`, requests[len(requests)-1].String())
+	helper.Assert(t, result.Valid, "The query is expected to be valid")
+}
+
+func TestNodesInfoAndStats(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_nodes/jvm,thread_pool", http.StatusOK, `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"search","nodes":{"n1":{
+		"name":"node-1","ip":"10.0.0.1","version":"7.17.0","roles":["data","master"],
+		"jvm":{"pid":42,"version":"17.0.2","mem":{"heap_init_in_bytes":1073741824,"heap_max_in_bytes":4294967296}},
+		"thread_pool":{"write":{"type":"fixed","size":8,"queue_size":10000}}}}}`)
+	recorder.Respond("GET", "/_nodes/stats/jvm,fs,transport", http.StatusOK, `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"search","nodes":{"n1":{
+		"name":"node-1","timestamp":1700000000000,
+		"jvm":{"mem":{"heap_used_in_bytes":2147483648,"heap_used_percent":50,"heap_max_in_bytes":4294967296},"gc":{"collectors":{"young":{"collection_count":12,"collection_time_in_millis":340}}}},
+		"thread_pool":{"write":{"threads":8,"queue":3,"active":8,"rejected":17}},
+		"fs":{"total":{"total_in_bytes":107374182400,"free_in_bytes":53687091200,"available_in_bytes":48318382080}},
+		"transport":{"server_open":13,"rx_count":100,"tx_count":120}}}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	info, err := client.NodesInfo([]string{"jvm", "thread_pool"})
+	helper.OK(t, err)
+	helper.Equals(t, int64(1), info.Nodes.Successful)
+	helper.Equals(t, "search", info.ClusterName)
+	node := info.Info["n1"]
+	helper.Equals(t, "node-1", node.Name)
+	helper.Equals(t, []string{"data", "master"}, node.Roles)
+	helper.Equals(t, int64(4294967296), node.JVM.Mem.HeapMaxInBytes)
+	helper.Equals(t, 10000, node.ThreadPool["write"].QueueSize)
+
+	stats, err := client.NodesStats([]string{"jvm", "fs", "transport"})
+	helper.OK(t, err)
+	nodeStats := stats.Stats["n1"]
+	helper.Equals(t, 50, nodeStats.JVM.Mem.HeapUsedPercent)
+	helper.Equals(t, int64(12), nodeStats.JVM.GC.Collectors["young"].CollectionCount)
+	helper.Equals(t, int64(17), nodeStats.ThreadPool["write"].Rejected)
+	helper.Equals(t, int64(48318382080), nodeStats.FS.Total.AvailableInBytes)
+	helper.Equals(t, 13, nodeStats.Transport.ServerOpen)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_nodes/jvm,thread_pool", requests[0].String())
+	helper.Equals(t, "GET /_nodes/stats/jvm,fs,transport", requests[1].String())
+
+	_, err = client.NodesStats(nil)
+	helper.OK(t, err)
+	helper.Equals(t, "GET /_nodes/stats", recorder.Requests()[2].String())
+}
+
+func TestPendingTasksAndClusterState(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_cluster/pending_tasks", http.StatusOK, `{"tasks":[{"insert_order":101,"priority":"URGENT","source":"create-index [foo_9], cause [api]","executing":true,"time_in_queue_millis":86,"time_in_queue":"86ms"}]}`)
+	recorder.Respond("GET", "/_cluster/state/metadata,routing_table/staging-orders", http.StatusOK, `{"cluster_name":"search","cluster_uuid":"c1","version":42,"state_uuid":"s1","master_node":"n1",
+		"metadata":{"indices":{"staging-orders":{"state":"open"}}},"routing_table":{"indices":{}}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	pending, err := client.PendingTasks()
+	helper.OK(t, err)
+	helper.Equals(t, 1, len(pending.Tasks))
+	helper.Equals(t, "URGENT", pending.Tasks[0].Priority)
+	helper.Assert(t, pending.Tasks[0].Executing, "The task is expected to be executing")
+	helper.Equals(t, int64(86), pending.Tasks[0].TimeInQueueMillis)
+
+	state, err := client.ClusterState("metadata,routing_table", "orders")
+	helper.OK(t, err)
+	helper.Equals(t, int64(42), state.Version)
+	helper.Equals(t, "n1", state.MasterNode)
+	helper.Equals(t, `{"indices":{"staging-orders":{"state":"open"}}}`, string(state.Metadata))
+
+	_, err = client.ClusterState("", "orders")
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_cluster/pending_tasks", requests[0].String())
+	helper.Equals(t, "GET /_cluster/state/metadata,routing_table/staging-orders", requests[1].String())
+	helper.Equals(t, "GET /_cluster/state/_all/staging-orders", requests[2].String())
+}
+
+func TestResizeIndex(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/staging-logs-2020/_shrink/staging-logs-2020-shrunk", http.StatusOK, `{"acknowledged":true,"shards_acknowledged":true,"index":"staging-logs-2020-shrunk"}`)
+	recorder.Respond("POST", "/staging-orders/_split/staging-orders-split", http.StatusBadRequest, `{"error":{"type":"illegal_state_exception","reason":"index staging-orders must be read-only to resize index"},"status":400}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	response, err := client.ShrinkIndex("logs-2020", "logs-2020-shrunk", `{"settings":{"index.number_of_shards":1}}`)
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The shrink is expected to be acknowledged")
+
+	_, err = client.SplitIndex("orders", "orders-split", `{"settings":{"index.number_of_shards":6}}`)
+	helper.Assert(t, err != nil && strings.Contains(err.Error(), "must be read-only"), "A read-only error is expected, got %v", err)
+
+	_, err = client.CloneIndex("orders", "orders-clone", "")
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /staging-logs-2020/_shrink/staging-logs-2020-shrunk\n"+`{"settings":{"index.number_of_shards":1}}`, requests[0].String())
+	helper.Equals(t, "POST /staging-orders/_split/staging-orders-split\n"+`{"settings":{"index.number_of_shards":6}}`, requests[1].String())
+	helper.Equals(t, "POST /staging-orders/_clone/staging-orders-clone", requests[2].String())
+}
+
+func TestGetAlias(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_alias/staging-orders", http.StatusOK, `{
+		"staging-orders-v1":{"aliases":{"staging-orders":{"filter":{"term":{"active":true}},"index_routing":"1","search_routing":"1,2"}}},
+		"staging-orders-v2":{"aliases":{"staging-orders":{"is_write_index":true}}}}`)
+	recorder.Respond("GET", "/_alias/staging-missing", http.StatusNotFound, `{"error":"alias [staging-missing] missing","status":404}`)
+	recorder.Respond("HEAD", "/_alias/staging-missing", http.StatusNotFound, ``)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	aliases, err := client.GetAlias("orders")
+	helper.OK(t, err)
+	helper.Equals(t, map[string]elasticsearch.AliasInfo{
+		"staging-orders-v1": {Filter: json.RawMessage(`{"term":{"active":true}}`), IndexRouting: "1", SearchRouting: "1,2"},
+		"staging-orders-v2": {IsWriteIndex: true},
+	}, aliases)
+
+	aliases, err = client.GetAlias("missing")
+	helper.OK(t, err)
+	helper.Equals(t, 0, len(aliases))
+
+	exists, err := client.AliasExists("orders")
+	helper.OK(t, err)
+	helper.Assert(t, exists, "The alias is expected to exist")
+	exists, err = client.AliasExists("missing")
+	helper.OK(t, err)
+	helper.Assert(t, !exists, "The alias isn't expected to exist")
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_alias/staging-orders", requests[0].String())
+	helper.Equals(t, "HEAD /_alias/staging-orders", requests[2].String())
+}
+
+func TestDocumentExistsAndSource(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("HEAD", "/test/_doc/2", http.StatusNotFound, ``)
+	recorder.Respond("GET", "/test/_source/1", http.StatusOK, `{"Name":"shirt","Colors":["red"]}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithTypeless())
+
+	exists, err := client.DocumentExists(IndexName, ProductDocumentType, "1", elasticsearch.WithParam("routing", "user-1"))
+	helper.OK(t, err)
+	helper.Assert(t, exists, "The document is expected to exist")
+	exists, err = client.DocumentExists(IndexName, ProductDocumentType, "2")
+	helper.OK(t, err)
+	helper.Assert(t, !exists, "The document isn't expected to exist")
+
+	source, err := client.DocumentSource(IndexName, ProductDocumentType, "1", elasticsearch.WithParam("_source_includes", "Name,Colors"))
+	helper.OK(t, err)
+	helper.Equals(t, `{"Name":"shirt","Colors":["red"]}`, string(source))
+
+	requests := recorder.Requests()
+	helper.Equals(t, "HEAD /test/_doc/1?routing=user-1", requests[0].String())
+	helper.Equals(t, "HEAD /test/_doc/2", requests[1].String())
+	helper.Equals(t, "GET /test/_source/1?_source_includes=Name%2CColors", requests[2].String())
+}
+
+func TestRankEval(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/staging-products/_rank_eval", http.StatusOK, `{"metric_score":0.5,
+		"details":{"shirts":{"metric_score":0.5,"unrated_docs":[{"_index":"staging-products","_id":"3"}],
+			"hits":[{"hit":{"_index":"staging-products","_id":"1","_score":1.8},"rating":1},{"hit":{"_index":"staging-products","_id":"3","_score":1.1},"rating":null}],
+			"metric_details":{"precision":{"relevant_docs_retrieved":1,"docs_retrieved":2}}}},
+		"failures":{}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	body := `{"requests":[{"id":"shirts","request":{"query":{"match":{"Name":"shirt"}}},"ratings":[{"_index":"staging-products","_id":"1","rating":1}]}],"metric":{"precision":{"k":2}}}`
+	result, err := client.RankEval("products", body)
+	helper.OK(t, err)
+	helper.Equals(t, "POST /staging-products/_rank_eval\n"+body, recorder.Requests()[0].String())
+	helper.Equals(t, 0.5, result.MetricScore)
+	shirts := result.Details["shirts"]
+	helper.Equals(t, "3", shirts.UnratedDocs[0].ID)
+	helper.Equals(t, 1, *shirts.Hits[0].Rating)
+	helper.Assert(t, shirts.Hits[1].Rating == nil, "The second hit isn't expected to be rated")
+	helper.Equals(t, `{"relevant_docs_retrieved":1,"docs_retrieved":2}`, string(shirts.MetricDetails["precision"]))
+	helper.Equals(t, 0, len(result.Failures))
+}
+
+func TestClearCache(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/staging-orders/_cache/clear", http.StatusOK, `{"_shards":{"total":6,"successful":6,"failed":0}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	result, err := client.ClearCache("orders", "query", "fielddata")
+	helper.OK(t, err)
+	helper.Equals(t, int64(6), result.Shards.Successful)
+	_, err = client.ClearCache("")
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /staging-orders/_cache/clear?query=true&fielddata=true", requests[0].String())
+	helper.Equals(t, "POST /_cache/clear", requests[1].String())
+}
+
+func TestFreezeAndIndexBlocks(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/staging-logs-2019/_freeze", http.StatusOK, `{"acknowledged":true,"shards_acknowledged":true}`)
+	recorder.Respond("POST", "/staging-logs-2019/_unfreeze", http.StatusOK, `{"acknowledged":true,"shards_acknowledged":true}`)
+	recorder.Respond("PUT", "/staging-logs-2020/_block/write", http.StatusOK, `{"acknowledged":true,"shards_acknowledged":true,"indices":[{"name":"staging-logs-2020","blocked":true}]}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithIndexPrefix("staging-"))
+
+	response, err := client.FreezeIndex("logs-2019")
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The freeze is expected to be acknowledged")
+	response, err = client.UnfreezeIndex("logs-2019")
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The unfreeze is expected to be acknowledged")
+
+	block, err := client.AddIndexBlock("logs-2020", elasticsearch.BlockWrite)
+	helper.OK(t, err)
+	helper.Assert(t, block.Acknowledged && block.ShardsAcknowledged, "The block is expected to be acknowledged")
+	helper.Equals(t, "staging-logs-2020", block.Indices[0].Name)
+	helper.Assert(t, block.Indices[0].Blocked, "The index is expected to be blocked")
+
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /staging-logs-2019/_freeze", requests[0].String())
+	helper.Equals(t, "POST /staging-logs-2019/_unfreeze", requests[1].String())
+	helper.Equals(t, "PUT /staging-logs-2020/_block/write", requests[2].String())
+}
+
+func TestDanglingIndices(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_dangling", http.StatusOK, `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"search",
+		"dangling_indices":[{"index_name":"orders","index_uuid":"zmM4e0JtBkeUjiHD-MihPQ","creation_date_millis":1589414451372,"node_ids":["pL47UN3dAb2d5RCWP6lQ3e"]}]}`)
+	recorder.Respond("POST", "/_dangling/zmM4e0JtBkeUjiHD-MihPQ", http.StatusAccepted, `{"acknowledged":true}`)
+	recorder.Respond("DELETE", "/_dangling/zmM4e0JtBkeUjiHD-MihPQ", http.StatusBadRequest, `{"error":{"type":"illegal_argument_exception","reason":"accept_data_loss must be set to true"},"status":400}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	dangling, err := client.ListDanglingIndices()
+	helper.OK(t, err)
+	helper.Equals(t, int64(2), dangling.Nodes.Successful)
+	helper.Equals(t, 1, len(dangling.DanglingIndices))
+	index := dangling.DanglingIndices[0]
+	helper.Equals(t, "zmM4e0JtBkeUjiHD-MihPQ", index.IndexUUID)
+	helper.Equals(t, int64(1589414451372), index.CreationDateMillis)
+	helper.Equals(t, []string{"pL47UN3dAb2d5RCWP6lQ3e"}, index.NodeIDs)
+
+	response, err := client.ImportDanglingIndex(index.IndexUUID, true)
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The import is expected to be acknowledged")
+	_, err = client.DeleteDanglingIndex(index.IndexUUID, false)
+	helper.Assert(t, err != nil && strings.Contains(err.Error(), "accept_data_loss"), "The deletion is expected to be rejected, got %v", err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "GET /_dangling", requests[0].String())
+	helper.Equals(t, "POST /_dangling/zmM4e0JtBkeUjiHD-MihPQ?accept_data_loss=true", requests[1].String())
+	helper.Equals(t, "DELETE /_dangling/zmM4e0JtBkeUjiHD-MihPQ?accept_data_loss=false", requests[2].String())
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func (e *esctl) runIndex(args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	action, index := args[0], args[1]
+	switch {
+	case action == "create" && len(args) <= 3:
+		mapping := ""
+		if len(args) == 3 {
+			body, err := ioutil.ReadFile(args[2])
+			if err != nil {
+				return err
+			}
+			mapping = string(body)
+		}
+		return e.acknowledged(e.client.CreateIndex(index, mapping))
+	case action == "delete" && len(args) == 2:
+		return e.acknowledged(e.client.DeleteIndex(index))
+	case action == "exists" && len(args) == 2:
+		exists, err := e.client.IndexExists(index)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errNotFound
+		}
+		return nil
+	}
+	return errUsage
+}
+
+func (e *esctl) runDocument(args []string) error {
+	if len(args) < 3 {
+		return errUsage
+	}
+
+	action, index, id := args[0], args[1], args[2]
+	switch {
+	case action == "get" && len(args) == 3:
+		document, err := e.client.Document(index, "", id)
+		if err != nil {
+			return err
+		}
+		if !document.Found {
+			return errNotFound
+		}
+		return e.printJSON(document.Source)
+	case action == "put" && len(args) <= 4:
+		data, err := readInput(args[3:], e.stdin)
+		if err != nil {
+			return err
+		}
+		result, err := e.client.InsertDocument(index, "", id, data)
+		if err != nil {
+			return err
+		}
+		return e.printJSON(result)
+	}
+	return errUsage
+}
+
+func (e *esctl) runSearch(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errUsage
+	}
+
+	query := []byte(`{"query":{"match_all":{}}}`)
+	if len(args) == 2 {
+		var err error
+		if query, err = ioutil.ReadFile(args[1]); err != nil {
+			return err
+		}
+	}
+	result, err := e.client.Search(args[0], "", string(query), false)
+	if err != nil {
+		return err
+	}
+	return e.printJSON(result)
+}
+
+func (e *esctl) runBulk(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	result, err := e.client.BulkReader(args[0], file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		if item.Error != nil {
+			failed++
+			fmt.Fprintf(e.stdout, "%s %s: %v\n", item.Action, item.ID, item.Error)
+		}
+	}
+	fmt.Fprintf(e.stdout, "%d actions, %d failed in %dms\n", len(result.Items), failed, result.Took)
+	if failed > 0 {
+		return fmt.Errorf("%d actions failed", failed)
+	}
+	return nil
+}
+
+func (e *esctl) runAlias(args []string) error {
+	if len(args) != 3 || args[0] != "swap" {
+		return errUsage
+	}
+
+	alias, index := args[1], args[2]
+	current, err := e.client.GetIndicesFromAlias(alias)
+	if err != nil {
+		return err
+	}
+	remove := make([]string, 0, len(current))
+	for _, name := range current {
+		if name != index {
+			remove = append(remove, name)
+		}
+	}
+	return e.acknowledged(e.client.UpdateAlias(remove, []string{index}, alias))
+}
+
+func (e *esctl) runHealth(args []string) error {
+	flags := flag.NewFlagSet("health", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	wait := flags.String("wait", "", "status to wait for, yellow or green")
+	timeout := flags.Duration("wait-timeout", time.Minute, "maximum time to wait for the status")
+
+	// The index is accepted before or after the flags
+	index := ""
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		index, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() > 1 || flags.NArg() == 1 && index != "" {
+		return errUsage
+	}
+	if flags.NArg() == 1 {
+		index = flags.Arg(0)
+	}
+
+	var health *elasticsearch.ClusterHealth
+	var err error
+	if *wait != "" {
+		health, err = e.client.WaitForStatus(index, *wait, *timeout)
+	} else {
+		health, err = e.client.ClusterHealth(index)
+	}
+	if health != nil {
+		if printErr := e.printJSON(health); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+// readInput reads the file of args, or stdin when no file is given
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	if len(args) == 1 {
+		return ioutil.ReadFile(args[0])
+	}
+	return ioutil.ReadAll(stdin)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestAliasSwap(t *testing.T) {
+	var actions string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/*/_alias/products":
+			w.Write([]byte(`{"products-1":{"aliases":{"products":{}}},"products-2":{"aliases":{"products":{}}}}`))
+		case "/_aliases":
+			body, _ := ioutil.ReadAll(r.Body)
+			actions = string(body)
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer server.Close()
+
+	var stdout bytes.Buffer
+	cli := &esctl{client: elasticsearch.NewClientFromUrl(server.URL), stdout: &stdout}
+	if err := cli.run([]string{"alias", "swap", "products", "products-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"actions":[{"remove":{"index":"products-1","alias":"products"}},{"add":{"index":"products-2","alias":"products"}}]}`; actions != expected {
+		t.Errorf("expected %s, got %s", expected, actions)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cli := &esctl{client: elasticsearch.NewClientFromUrl(server.URL), stdin: strings.NewReader(""), stdout: ioutil.Discard}
+	for _, test := range []struct {
+		args     []string
+		expected error
+	}{
+		{[]string{"index", "exists", "products"}, errNotFound},
+		{[]string{"index", "drop", "products"}, errUsage},
+		{[]string{"alias", "products"}, errUsage},
+		{[]string{}, errUsage},
+	} {
+		if err := cli.run(test.args); err != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.args, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes request bodies and decodes responses, to plug a faster JSON library
+// (jsoniter, go-json, sonic...) than encoding/json on large search results and bulk loads.
+// Implementations must honor the json struct tags and the Marshaler/Unmarshaler interfaces.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StreamCodec is implemented by the codecs able to decode a response while it's read, such as JSONCodec.
+// The other codecs, and every codec when WithRawResponses is set, decode the response once read in a pooled buffer.
+type StreamCodec interface {
+	Codec
+	Decode(r io.Reader, v interface{}) error
+}
+
+// JSONCodec is the default codec, backed by encoding/json.
+// The types decoding themselves (ErrorCause, InsertDocument, BulkItem, TotalHits and Bucket) are only given
+// their bytes by encoding/json, not the options of the decoder: they accept the unknown fields whatever
+// DisallowUnknownFields, and the numeric key of a Bucket is a float64 whatever UseNumber.
+type JSONCodec struct {
+	// DisallowUnknownFields fails the decoding of the responses having fields missing from the structs,
+	// to catch the drift of the structs against a new Elasticsearch version in tests.
+	DisallowUnknownFields bool
+
+	// UseNumber decodes the numbers of the interface{} values as json.Number instead of float64,
+	// keeping the precision of the integers beyond 2^53 such as the sort values of the hits
+	UseNumber bool
+}
+
+// Marshal encodes v with json.Marshal
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes data with json.Unmarshal, or a json.Decoder when an option is set
+func (j JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	if !j.DisallowUnknownFields && !j.UseNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	return j.decoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Decode decodes the response read from r with a json.Decoder
+func (j JSONCodec) Decode(r io.Reader, v interface{}) error {
+	return j.decoder(r).Decode(v)
+}
+
+func (j JSONCodec) decoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if j.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if j.UseNumber {
+		decoder.UseNumber()
+	}
+	return decoder
+}
+
+// WithCodec sets the codec used to encode the request bodies and decode the responses
+func WithCodec(codec Codec) ClientOption {
+	return func(c *client) {
+		c.codec = codec
+	}
+}
+
+// WithDisallowUnknownFields sets DisallowUnknownFields on the JSONCodec of the client, failing the decoding
+// of the responses having fields missing from the returned structs. It's meant for tests, as new
+// Elasticsearch versions add fields. It has no effect on a codec set by WithCodec.
+func WithDisallowUnknownFields() ClientOption {
+	return func(c *client) {
+		if codec, ok := c.codec.(JSONCodec); ok {
+			codec.DisallowUnknownFields = true
+			c.codec = codec
+		}
+	}
+}
+
+// WithUseNumber sets UseNumber on the JSONCodec of the client, the numbers of the interface{} values of
+// the responses being decoded as json.Number. It has no effect on a codec set by WithCodec.
+func WithUseNumber() ClientOption {
+	return func(c *client) {
+		if codec, ok := c.codec.(JSONCodec); ok {
+			codec.UseNumber = true
+			c.codec = codec
+		}
+	}
+}
+
+// WithRawResponses keeps the undecoded body of the responses in the Raw field of the returned structs
+// embedding RawResponse, such as SearchResult or Document, at the cost of a copy of every response
+func WithRawResponses() ClientOption {
+	return func(c *client) {
+		c.rawResponses = true
+	}
+}
+
+// rawSetter is implemented by the structs embedding RawResponse
+type rawSetter interface {
+	setRaw(data []byte)
+}
+
+// rawCodec keeps the undecoded body in the structs embedding RawResponse
+type rawCodec struct {
+	Codec
+}
+
+// Unmarshal decodes data with the wrapped codec, and keeps a copy of it in v if supported
+func (r rawCodec) Unmarshal(data []byte, v interface{}) error {
+	if err := r.Codec.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if setter, ok := v.(rawSetter); ok {
+		setter.setRaw(data)
+	}
+	return nil
+}
+
+// codecOf returns the codec of a client created by this package, JSONCodec otherwise
+func codecOf(target Client) Codec {
+	if c, ok := target.(*client); ok && c.codec != nil {
+		return c.codec
+	}
+	return JSONCodec{}
+}
@@ -0,0 +1,146 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestCodec(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_id":"1","_source":{"Name":"shirt"}}]}}`))
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithCodec(codec))
+	size := 1
+	_, err := client.SearchWithRequest(IndexName, &elasticsearch.SearchRequest{Size: &size})
+	helper.OK(t, err)
+	helper.Equals(t, 1, codec.marshals)
+	helper.Equals(t, 1, codec.unmarshals)
+
+	type product struct{ Name string }
+	result, err := elasticsearch.SearchTyped[product](client, IndexName, `{}`)
+	helper.OK(t, err)
+	helper.Equals(t, "shirt", result.Documents[0].Name)
+	helper.Equals(t, 3, codec.unmarshals)
+}
+
+type streamCodec struct {
+	countingCodec
+	decodes int
+}
+
+func (c *streamCodec) Decode(r io.Reader, v interface{}) error {
+	c.decodes++
+	return json.NewDecoder(r).Decode(v)
+}
+
+func TestStreamCodec(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_id":"1","status":201}}]}`))
+	}))
+	defer server.Close()
+
+	codec := &streamCodec{}
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithCodec(codec))
+	bulk, err := client.BulkReader(IndexName, strings.NewReader("{\"index\":{\"_id\":\"1\"}}\n{}\n"), -1)
+	helper.OK(t, err)
+	helper.Equals(t, 1, len(bulk.Items))
+	helper.Equals(t, 1, codec.decodes)
+	helper.Equals(t, 0, codec.unmarshals)
+}
+
+func TestDecodingModes(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"took":1,"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_id":"1","sort":[9007199254740993]}]},"new_field":true}`))
+	}))
+	defer server.Close()
+
+	// Tolerant by default
+	result, err := elasticsearch.NewClientFromUrl(server.URL).Search(IndexName, "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, float64(9007199254740992), result.Hits.Hits[0].Sort[0])
+
+	_, err = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithDisallowUnknownFields()).Search(IndexName, "", `{}`, false)
+	helper.Assert(t, err != nil && strings.Contains(err.Error(), "new_field"), "An unknown field error is expected, got %v", err)
+
+	result, err = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithUseNumber()).Search(IndexName, "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, json.Number("9007199254740993"), result.Hits.Hits[0].Sort[0])
+}
+
+func TestRawResponses(t *testing.T) {
+	helper := Test{}
+	body := `{"_index":"test","_id":"1","found":true,"_source":{},"_ignored":["Description.keyword"]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	document, err := elasticsearch.NewClientFromUrl(server.URL).Document(IndexName, "", "1")
+	helper.OK(t, err)
+	helper.Assert(t, document.Raw == nil, "The raw response is only kept when enabled")
+
+	document, err = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithRawResponses(), elasticsearch.WithCodec(&streamCodec{})).Document(IndexName, "", "1")
+	helper.OK(t, err)
+	helper.Equals(t, body, string(document.Raw))
+	var ignored struct {
+		Ignored []string `json:"_ignored"`
+	}
+	helper.OK(t, json.Unmarshal(document.Raw, &ignored))
+	helper.Equals(t, []string{"Description.keyword"}, ignored.Ignored)
+}
+
+func TestRawResponsesNonSearch(t *testing.T) {
+	helper := Test{}
+	dangling := `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"search","dangling_indices":[]}`
+	rollover := `{"acknowledged":true,"old_index":"logs-000001","new_index":"logs-000002","rolled_over":true,"conditions":{"[max_docs: 1000]":true}}`
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_dangling", http.StatusOK, dangling)
+	recorder.Respond("POST", "/logs/_rollover", http.StatusOK, rollover)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithRawResponses())
+
+	indices, err := client.ListDanglingIndices()
+	helper.OK(t, err)
+	helper.Equals(t, dangling, string(indices.Raw))
+
+	result, err := client.Rollover("logs", `{"conditions":{"max_docs":1000}}`)
+	helper.OK(t, err)
+	helper.Equals(t, rollover, string(result.Raw))
+}
+
+func TestJSONCodecDecode(t *testing.T) {
+	helper := Test{}
+	var codec elasticsearch.StreamCodec = elasticsearch.JSONCodec{UseNumber: true, DisallowUnknownFields: true}
+
+	var value struct{ Sort []interface{} }
+	helper.OK(t, codec.Decode(strings.NewReader(`{"Sort":[9007199254740993]}`), &value))
+	helper.Equals(t, json.Number("9007199254740993"), value.Sort[0])
+
+	err := codec.Decode(strings.NewReader(`{"Sort":[],"new_field":true}`), &value)
+	helper.Assert(t, err != nil && strings.Contains(err.Error(), "new_field"), "An unknown field error is expected, got %v", err)
+}
@@ -0,0 +1,317 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateMath represents a date math index name, resolved by Elasticsearch from the current date,
+// such as <logs-{now/d}> for logs-2024.03.22 or <logs-{now/M{yyyy.MM}}> for logs-2024.03.
+// The names are accepted by the methods taking indices, and escaped by them.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/api-conventions.html#api-date-math-index-names
+type DateMath struct {
+	Prefix   string // static part before the date, such as "logs-"
+	Math     string // date math expression, such as "now/d" or "now-1M/M", now if empty
+	Format   string // format of the date in the Java syntax, yyyy.MM.dd if empty
+	TimeZone string // time zone of the date, such as "+01:00" or "Europe/Paris", UTC if empty
+	Suffix   string // static part after the date
+}
+
+// String returns the date math name, such as <logs-{now/d{yyyy.MM.dd|+01:00}}>
+func (d DateMath) String() string {
+	math := d.Math
+	if math == "" {
+		math = "now"
+	}
+	expression := math
+	if d.Format != "" || d.TimeZone != "" {
+		format := d.Format
+		if format == "" {
+			format = defaultDateMathFormat
+		}
+		if d.TimeZone != "" {
+			format += "|" + d.TimeZone
+		}
+		expression += "{" + format + "}"
+	}
+	return "<" + escapeDateMath(d.Prefix) + "{" + expression + "}" + escapeDateMath(d.Suffix) + ">"
+}
+
+// Resolve returns the concrete name of the index at the given time, see ResolveIndexName
+func (d DateMath) Resolve(now time.Time) (string, error) {
+	return ResolveIndexName(d.String(), now)
+}
+
+// EscapeIndexName escapes comma-separated index names to be used in a path, such as with Do.
+// The date math names are fully escaped, as expected by Elasticsearch.
+func EscapeIndexName(indices string) string {
+	return escapeIndices(indices)
+}
+
+const defaultDateMathFormat = "yyyy.MM.dd"
+
+// escapeDateMath escapes the characters of a static part having a meaning in a date math name
+func escapeDateMath(static string) string {
+	return strings.NewReplacer(`\`, `\\`, "{", `\{`, "}", `\}`).Replace(static)
+}
+
+// ResolveIndexName computes client-side the concrete name of a date math index name at the given
+// time, the same way Elasticsearch does. A name which isn't enclosed in angle brackets is returned as is.
+func ResolveIndexName(name string, now time.Time) (string, error) {
+	if !strings.HasPrefix(name, "<") || !strings.HasSuffix(name, ">") {
+		return name, nil
+	}
+
+	var resolved strings.Builder
+	inner := name[1 : len(name)-1]
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			if i+1 == len(inner) {
+				return "", fmt.Errorf("invalid date math name %s: trailing escape character", name)
+			}
+			i++
+			resolved.WriteByte(inner[i])
+		case '{':
+			end := closingBrace(inner, i)
+			if end < 0 {
+				return "", fmt.Errorf("invalid date math name %s: missing closing brace", name)
+			}
+			date, err := resolveDateMath(inner[i+1:end], now)
+			if err != nil {
+				return "", fmt.Errorf("invalid date math name %s: %v", name, err)
+			}
+			resolved.WriteString(date)
+			i = end
+		case '}':
+			return "", fmt.Errorf("invalid date math name %s: unexpected closing brace", name)
+		default:
+			resolved.WriteByte(inner[i])
+		}
+	}
+	return resolved.String(), nil
+}
+
+// closingBrace returns the position of the brace closing the one at start, -1 if missing
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// resolveDateMath resolves an expression such as now/d or now-1M/M{yyyy.MM|+01:00}
+func resolveDateMath(expression string, now time.Time) (string, error) {
+	math, format, zone := expression, defaultDateMathFormat, ""
+	if open := strings.IndexByte(expression, '{'); open >= 0 {
+		if !strings.HasSuffix(expression, "}") {
+			return "", fmt.Errorf("invalid date format in %s", expression)
+		}
+		math, format = expression[:open], expression[open+1:len(expression)-1]
+		if pipe := strings.IndexByte(format, '|'); pipe >= 0 {
+			format, zone = format[:pipe], format[pipe+1:]
+		}
+		if format == "" {
+			format = defaultDateMathFormat
+		}
+	}
+
+	location, err := dateMathLocation(zone)
+	if err != nil {
+		return "", err
+	}
+	date, err := evaluateDateMath(math, now.In(location))
+	if err != nil {
+		return "", err
+	}
+	return formatJavaDate(date, format)
+}
+
+// dateMathLocation returns the location of a time zone, an offset such as +01:00 or a name such as Europe/Paris
+func dateMathLocation(zone string) (*time.Location, error) {
+	switch {
+	case zone == "" || zone == "Z" || zone == "UTC":
+		return time.UTC, nil
+	case zone[0] == '+' || zone[0] == '-':
+		offset, err := time.Parse("-07:00", zone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %s", zone)
+		}
+		_, seconds := offset.Zone()
+		return time.FixedZone(zone, seconds), nil
+	}
+	return time.LoadLocation(zone)
+}
+
+// evaluateDateMath applies the operations of an expression such as now-1d/d, the rounding
+// being done in the location of now
+func evaluateDateMath(math string, now time.Time) (time.Time, error) {
+	if !strings.HasPrefix(math, "now") {
+		return time.Time{}, fmt.Errorf("date math %s must start with now", math)
+	}
+
+	date := now
+	operations := math[len("now"):]
+	for len(operations) > 0 {
+		operator := operations[0]
+		operations = operations[1:]
+
+		if operator == '/' {
+			if len(operations) == 0 {
+				return time.Time{}, fmt.Errorf("missing rounding unit in %s", math)
+			}
+			rounded, err := roundDate(date, operations[0])
+			if err != nil {
+				return time.Time{}, err
+			}
+			date, operations = rounded, operations[1:]
+			continue
+		}
+		if operator != '+' && operator != '-' {
+			return time.Time{}, fmt.Errorf("invalid operator %c in %s", operator, math)
+		}
+
+		digits := 0
+		for digits < len(operations) && operations[digits] >= '0' && operations[digits] <= '9' {
+			digits++
+		}
+		amount := 1
+		if digits > 0 {
+			amount, _ = strconv.Atoi(operations[:digits])
+		}
+		if digits == len(operations) {
+			return time.Time{}, fmt.Errorf("missing unit in %s", math)
+		}
+		if operator == '-' {
+			amount = -amount
+		}
+		shifted, err := shiftDate(date, amount, operations[digits])
+		if err != nil {
+			return time.Time{}, err
+		}
+		date, operations = shifted, operations[digits+1:]
+	}
+	return date, nil
+}
+
+func shiftDate(date time.Time, amount int, unit byte) (time.Time, error) {
+	switch unit {
+	case 'y':
+		return date.AddDate(amount, 0, 0), nil
+	case 'M':
+		return date.AddDate(0, amount, 0), nil
+	case 'w':
+		return date.AddDate(0, 0, 7*amount), nil
+	case 'd':
+		return date.AddDate(0, 0, amount), nil
+	case 'h', 'H':
+		return date.Add(time.Duration(amount) * time.Hour), nil
+	case 'm':
+		return date.Add(time.Duration(amount) * time.Minute), nil
+	case 's':
+		return date.Add(time.Duration(amount) * time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date math unit %c", unit)
+}
+
+func roundDate(date time.Time, unit byte) (time.Time, error) {
+	year, month, day := date.Date()
+	location := date.Location()
+	switch unit {
+	case 'y':
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location), nil
+	case 'M':
+		return time.Date(year, month, 1, 0, 0, 0, 0, location), nil
+	case 'w':
+		// Weeks start on Monday
+		offset := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, location), nil
+	case 'd':
+		return time.Date(year, month, day, 0, 0, 0, 0, location), nil
+	case 'h', 'H':
+		return time.Date(year, month, day, date.Hour(), 0, 0, 0, location), nil
+	case 'm':
+		return time.Date(year, month, day, date.Hour(), date.Minute(), 0, 0, location), nil
+	case 's':
+		return time.Date(year, month, day, date.Hour(), date.Minute(), date.Second(), 0, location), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date math unit %c", unit)
+}
+
+// formatJavaDate formats a date with the common patterns of the Java syntax used by Elasticsearch,
+// such as yyyy.MM.dd or yyyy-ww. Text between single quotes is copied as is.
+func formatJavaDate(date time.Time, format string) (string, error) {
+	var formatted strings.Builder
+	for i := 0; i < len(format); {
+		letter := format[i]
+		if letter == '\'' {
+			end := strings.IndexByte(format[i+1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated quote in date format %s", format)
+			}
+			formatted.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		count := 1
+		for i+count < len(format) && format[i+count] == letter {
+			count++
+		}
+		i += count
+
+		_, week := date.ISOWeek()
+		var value int
+		switch letter {
+		case 'y', 'u', 'Y':
+			value = date.Year()
+			if letter == 'Y' {
+				value, _ = date.ISOWeek()
+			}
+			if count == 2 {
+				value %= 100
+			}
+		case 'M':
+			switch {
+			case count == 3:
+				formatted.WriteString(date.Month().String()[:3])
+				continue
+			case count > 3:
+				formatted.WriteString(date.Month().String())
+				continue
+			}
+			value = int(date.Month())
+		case 'd':
+			value = date.Day()
+		case 'D':
+			value = date.YearDay()
+		case 'w':
+			value = week
+		case 'H':
+			value = date.Hour()
+		case 'm':
+			value = date.Minute()
+		case 's':
+			value = date.Second()
+		default:
+			if letter >= 'a' && letter <= 'z' || letter >= 'A' && letter <= 'Z' {
+				return "", fmt.Errorf("unsupported pattern %s in date format %s", strings.Repeat(string(letter), count), format)
+			}
+			formatted.WriteString(strings.Repeat(string(letter), count))
+			continue
+		}
+		formatted.WriteString(fmt.Sprintf("%0*d", count, value))
+	}
+	return formatted.String(), nil
+}
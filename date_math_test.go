@@ -0,0 +1,65 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestResolveIndexName(t *testing.T) {
+	helper := Test{}
+	now := time.Date(2024, time.March, 22, 23, 30, 15, 0, time.UTC)
+	for name, expected := range map[string]string{
+		"logs":                                  "logs",
+		"<logs-{now}>":                          "logs-2024.03.22",
+		"<logs-{now/d}>":                        "logs-2024.03.22",
+		"<logs-{now/M{yyyy.MM}}>":               "logs-2024.03",
+		"<logs-{now/M-1M{yyyy.MM}}>":            "logs-2024.02",
+		"<logs-{now-2d/d}>":                     "logs-2024.03.20",
+		"<logs-{now/w{yyyy.MM.dd}}>":            "logs-2024.03.18",
+		"<logs-{now{yyyy.MM.dd|+01:00}}>":       "logs-2024.03.23",
+		"<logs-{now/d{yyyy.MM.dd|-02:00}}>":     "logs-2024.03.22",
+		"<logs-{now+1h{yyyy-MM-dd'T'HH}}>":      "logs-2024-03-23T00",
+		"<logs-{now{yyyy-ww}}>":                 "logs-2024-12",
+		`<elastic\{ON\}-{now/M}>`:               "elastic{ON}-2024.03.01",
+		"<logs-{now{yyyy.MM.dd|Europe/Paris}}>": "logs-2024.03.23",
+	} {
+		resolved, err := elasticsearch.ResolveIndexName(name, now)
+		helper.OK(t, err)
+		helper.Equals(t, expected, resolved)
+	}
+
+	for _, name := range []string{"<logs-{now/d>", "<logs-{yesterday}>", "<logs-{now/q}>", "<logs-{now{yyyy.QQ}}>"} {
+		_, err := elasticsearch.ResolveIndexName(name, now)
+		helper.Assert(t, err != nil, "%s is invalid", name)
+	}
+}
+
+func TestDateMath(t *testing.T) {
+	helper := Test{}
+	name := elasticsearch.DateMath{Prefix: "logs-", Math: "now/d", TimeZone: "+01:00"}
+	helper.Equals(t, "<logs-{now/d{yyyy.MM.dd|+01:00}}>", name.String())
+	helper.Equals(t, "%3Clogs-%7Bnow%2Fd%7Byyyy.MM.dd%7C%2B01:00%7D%7D%3E", elasticsearch.EscapeIndexName(name.String()))
+	resolved, err := name.Resolve(time.Date(2024, time.March, 22, 23, 30, 0, 0, time.UTC))
+	helper.OK(t, err)
+	helper.Equals(t, "logs-2024.03.23", resolved)
+
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.RequestURI
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err = client.CreateIndex(elasticsearch.DateMath{Prefix: "logs-"}.String(), "")
+	helper.OK(t, err)
+	helper.Equals(t, "/%3Clogs-%7Bnow%7D%3E", uri)
+
+	_, err = client.Search("<logs-{now/d}>,<logs-{now/d-1d}>", "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, "/%3Clogs-%7Bnow%2Fd%7D%3E,%3Clogs-%7Bnow%2Fd-1d%7D%3E/_search", uri)
+}
@@ -0,0 +1,177 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DeletePartition restricts a delete by query to a part of the keyspace
+type DeletePartition struct {
+	Name   string
+	Filter string // query clause selecting the documents of the partition
+}
+
+// DeletePartitionResult represents the outcome of the delete by query of one partition
+type DeletePartitionResult struct {
+	Partition DeletePartition
+	Result    *DeleteByQueryResult
+	Error     error
+}
+
+// DeleteByQueryReport aggregates the results of a chunked delete by query
+type DeleteByQueryReport struct {
+	Partitions       int
+	Succeeded        int
+	Deleted          int64
+	VersionConflicts int64
+	Failures         []DeletePartitionResult
+	Took             time.Duration
+}
+
+// DeleteByQueryProgress is sent after each partition completes
+type DeleteByQueryProgress struct {
+	Done    int
+	Total   int
+	Deleted int64
+	Last    DeletePartitionResult
+}
+
+// TimeRangePartitions splits [from, to) on field into ranges of step duration
+func TimeRangePartitions(field string, from, to time.Time, step time.Duration) []DeletePartition {
+	partitions := []DeletePartition{}
+	if step <= 0 {
+		return partitions
+	}
+
+	for start := from; start.Before(to); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(to) {
+			end = to
+		}
+		clause := map[string]interface{}{
+			"range": map[string]interface{}{
+				field: map[string]string{
+					"gte": start.Format(time.RFC3339Nano),
+					"lt":  end.Format(time.RFC3339Nano),
+				},
+			},
+		}
+		filter, _ := json.Marshal(clause)
+		partitions = append(partitions, DeletePartition{
+			Name:   start.Format(time.RFC3339) + "/" + end.Format(time.RFC3339),
+			Filter: string(filter),
+		})
+	}
+	return partitions
+}
+
+// IDPrefixPartitions creates one partition per prefix of field, usually _id
+func IDPrefixPartitions(field string, prefixes []string) []DeletePartition {
+	partitions := make([]DeletePartition, len(prefixes))
+	for i, prefix := range prefixes {
+		clause := map[string]interface{}{
+			"prefix": map[string]string{field: prefix},
+		}
+		filter, _ := json.Marshal(clause)
+		partitions[i] = DeletePartition{Name: field + ":" + prefix, Filter: string(filter)}
+	}
+	return partitions
+}
+
+// ChunkedDeleteByQuery runs a delete by query for every partition, with at most concurrency
+// requests in flight. query is a query clause combined with the filter of each partition,
+// an empty query deletes all the documents of the partitions.
+// A failing partition doesn't stop the others, it's reported in the Failures of the report, as the
+// partitions answered with an error status or which timed out before deleting all their documents.
+func ChunkedDeleteByQuery(client Client, indexName, query string, partitions []DeletePartition, concurrency int, progress func(DeleteByQueryProgress)) (*DeleteByQueryReport, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if query != "" && !json.Valid([]byte(query)) {
+		return nil, fmt.Errorf("invalid query clause: %s", query)
+	}
+
+	start := time.Now()
+	report := &DeleteByQueryReport{Partitions: len(partitions)}
+	results := make(chan DeletePartitionResult)
+	jobs := make(chan DeletePartition)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range jobs {
+				result, err := client.DeleteByQuery(indexName, partitionQuery(query, partition))
+				if err == nil {
+					err = partitionError(partition, result)
+				}
+				results <- DeletePartitionResult{Partition: partition, Result: result, Error: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, partition := range partitions {
+			jobs <- partition
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	done := 0
+	for result := range results {
+		done++
+		if result.Result != nil {
+			report.Deleted += result.Result.Deleted
+			report.VersionConflicts += result.Result.VersionConflicts
+		}
+		if result.Error != nil {
+			report.Failures = append(report.Failures, result)
+		} else {
+			report.Succeeded++
+		}
+
+		if progress != nil {
+			progress(DeleteByQueryProgress{Done: done, Total: len(partitions), Deleted: report.Deleted, Last: result})
+		}
+	}
+
+	report.Took = time.Since(start)
+	return report, nil
+}
+
+// partitionError reports the error statuses, the failures and the timeouts of a partition
+func partitionError(partition DeletePartition, result *DeleteByQueryResult) error {
+	switch {
+	case result.Error != nil:
+		return fmt.Errorf("status %d while deleting partition %s: %v", result.Status, partition.Name, result.Error)
+	case result.Status != 0 && (result.Status < 200 || result.Status > 299):
+		return fmt.Errorf("status %d while deleting partition %s", result.Status, partition.Name)
+	case len(result.Failures) > 0:
+		return fmt.Errorf("%d failures while deleting partition %s", len(result.Failures), partition.Name)
+	case result.TimedOut:
+		return fmt.Errorf("timed out while deleting partition %s", partition.Name)
+	}
+	return nil
+}
+
+func partitionQuery(query string, partition DeletePartition) string {
+	filters := []json.RawMessage{json.RawMessage(partition.Filter)}
+	if query != "" {
+		filters = append(filters, json.RawMessage(query))
+	}
+
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filters,
+			},
+		},
+	}
+	encoded, _ := json.Marshal(body)
+	return string(encoded)
+}
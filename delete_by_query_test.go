@@ -0,0 +1,85 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestChunkedDeleteByQuery(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), `"gte":"2020-01-02T00:00:00Z"`) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"boom"}`))
+			return
+		}
+		w.Write([]byte(`{"deleted":10,"failures":[]}`))
+	}))
+	defer server.Close()
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	partitions := elasticsearch.TimeRangePartitions("timestamp", from, from.Add(72*time.Hour), 24*time.Hour)
+	helper.Equals(t, 3, len(partitions))
+
+	calls := 0
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	report, err := elasticsearch.ChunkedDeleteByQuery(client, "logs", `{"term":{"level":"debug"}}`, partitions, 2, func(p elasticsearch.DeleteByQueryProgress) {
+		calls++
+	})
+	helper.OK(t, err)
+	helper.Equals(t, 3, calls)
+	helper.Equals(t, 2, report.Succeeded)
+	helper.Equals(t, int64(20), report.Deleted)
+	helper.Equals(t, 1, len(report.Failures))
+}
+
+func TestChunkedDeleteByQueryFailures(t *testing.T) {
+	helper := Test{}
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"_id:a": {http.StatusOK, `{"deleted":5,"timed_out":false,"failures":[]}`},
+		"_id:b": {http.StatusInternalServerError, `{"error":{"type":"search_phase_execution_exception","reason":"all shards failed"},"status":500}`},
+		"_id:c": {http.StatusServiceUnavailable, `{}`},
+		"_id:d": {http.StatusOK, `{"deleted":3,"timed_out":true,"failures":[]}`},
+		"_id:e": {http.StatusOK, `{"deleted":1,"timed_out":false,"failures":[{"cause":{"type":"version_conflict_engine_exception"}}]}`},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		for name, response := range responses {
+			if strings.Contains(string(body), `"_id":"`+strings.TrimPrefix(name, "_id:")+`"`) {
+				w.WriteHeader(response.status)
+				w.Write([]byte(response.body))
+				return
+			}
+		}
+		t.Errorf("Unexpected partition %s", body)
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	partitions := elasticsearch.IDPrefixPartitions("_id", []string{"a", "b", "c", "d", "e"})
+	report, err := elasticsearch.ChunkedDeleteByQuery(client, "logs", "", partitions, 1, nil)
+	helper.OK(t, err)
+	helper.Equals(t, 1, report.Succeeded)
+	helper.Equals(t, int64(9), report.Deleted)
+
+	failures := map[string]string{}
+	for _, failure := range report.Failures {
+		failures[failure.Partition.Name] = failure.Error.Error()
+	}
+	helper.Equals(t, map[string]string{
+		"_id:b": "status 500 while deleting partition _id:b: search_phase_execution_exception: all shards failed",
+		"_id:c": "status 503 while deleting partition _id:c",
+		"_id:d": "timed out while deleting partition _id:d",
+		"_id:e": "1 failures while deleting partition _id:e",
+	}, failures)
+}
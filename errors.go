@@ -0,0 +1,24 @@
+package elasticsearch
+
+import "strings"
+
+// ConflictError is returned when a write conflicts with the current state of a document,
+// such as an existing document on create or a stale sequence number
+type ConflictError struct {
+	Response string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Response
+}
+
+// MissingPrivilegesError is returned by PrivilegesResult.Err when the user doesn't have
+// all the requested privileges
+type MissingPrivilegesError struct {
+	Username string
+	Missing  []string // such as "cluster:monitor" or "index:orders-*:write"
+}
+
+func (e *MissingPrivilegesError) Error() string {
+	return "user " + e.Username + " is missing privileges: " + strings.Join(e.Missing, ", ")
+}
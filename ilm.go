@@ -0,0 +1,109 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ILM exposes the index lifecycle management APIs, rolling over, shrinking and deleting indices
+// according to lifecycle policies
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/index-lifecycle-management-api.html
+type ILM interface {
+	PutPolicy(name, body string) (*Response, error)
+	GetPolicies(name string) (map[string]LifecyclePolicy, error)
+	DeletePolicy(name string) (*Response, error)
+	Bootstrap(alias, policy, template string) (bool, error)
+}
+
+// LifecyclePolicy represents a lifecycle policy, with its phases in Policy
+type LifecyclePolicy struct {
+	Version      int             `json:"version"`
+	ModifiedDate string          `json:"modified_date"`
+	Policy       json.RawMessage `json:"policy"`
+}
+
+type ilm struct {
+	client *client
+}
+
+// ILM returns the client of the index lifecycle management APIs
+func (c *client) ILM() ILM {
+	return &ilm{client: c}
+}
+
+// PutPolicy creates or updates a lifecycle policy, such as {"policy": {"phases": {...}}}
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-put-lifecycle.html
+func (i *ilm) PutPolicy(name, body string) (*Response, error) {
+	return i.client.acknowledge("PUT", "/_ilm/policy/"+escapePath(name), body)
+}
+
+// GetPolicies returns the lifecycle policies by name, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-get-lifecycle.html
+func (i *ilm) GetPolicies(name string) (map[string]LifecyclePolicy, error) {
+	path := "/_ilm/policy"
+	if name != "" {
+		path += "/" + escapePath(name)
+	}
+	policies := make(map[string]LifecyclePolicy)
+	if err := i.client.sendAPIRequest("GET", path, "", &policies); err != nil {
+		return map[string]LifecyclePolicy{}, err
+	}
+	return policies, nil
+}
+
+// DeletePolicy deletes a lifecycle policy which isn't used by any index
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-delete-lifecycle.html
+func (i *ilm) DeletePolicy(name string) (*Response, error) {
+	return i.client.acknowledge("DELETE", "/_ilm/policy/"+escapePath(name), "")
+}
+
+// Bootstrap sets up the rolling indices of an alias managed by the lifecycle policy, such as
+// logs-000001, logs-000002... written through the logs alias. The index template named after the alias
+// (namespaced as the alias), whose body has the settings and mappings of the indices, is put with the index patterns and the
+// lifecycle settings, then the initial write index <alias>-000001 is created unless the alias exists.
+// It reports whether the initial index has been created, so that it can be called on every start.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/getting-started-index-lifecycle-management.html
+func (i *ilm) Bootstrap(alias, policy, template string) (bool, error) {
+	name := i.client.namespace(alias)
+	body, err := rolloverTemplate(name, policy, template)
+	if err != nil {
+		return false, err
+	}
+	if err := responseError(i.client.PutIndexTemplate(name, body)); err != nil {
+		return false, err
+	}
+
+	exists, err := i.client.AliasExists(alias)
+	if err != nil || exists {
+		return false, err
+	}
+	aliases, _ := json.Marshal(map[string]interface{}{
+		"aliases": map[string]interface{}{name: map[string]bool{"is_write_index": true}},
+	})
+	if err := responseError(i.client.CreateIndex(alias+"-000001", string(aliases))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rolloverTemplate completes the template of the indices of an alias with its index patterns and
+// the lifecycle settings of the policy
+func rolloverTemplate(alias, policy, template string) (string, error) {
+	body := map[string]interface{}{}
+	if template != "" {
+		if err := json.Unmarshal([]byte(template), &body); err != nil {
+			return "", fmt.Errorf("invalid index template: %v", err)
+		}
+	}
+	settings, ok := body["settings"].(map[string]interface{})
+	if !ok {
+		settings = map[string]interface{}{}
+		body["settings"] = settings
+	}
+	settings["index.lifecycle.name"] = policy
+	settings["index.lifecycle.rollover_alias"] = alias
+	body["index_patterns"] = []string{alias + "-*"}
+
+	data, err := json.Marshal(body)
+	return string(data), err
+}
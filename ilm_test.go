@@ -0,0 +1,54 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestILMBootstrap(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	aliasExists := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.Method + " " + r.URL.Path {
+		case "HEAD /_alias/staging-logs":
+			if !aliasExists {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "POST /staging-logs/_rollover":
+			w.Write([]byte(`{"acknowledged":true,"shards_acknowledged":true,"old_index":"staging-logs-000001","new_index":"staging-logs-000002","rolled_over":true,"dry_run":false,"conditions":{"[max_age: 7d]":true}}`))
+		default:
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-"))
+	_, err := client.ILM().PutPolicy("logs", `{"policy":{"phases":{"hot":{"actions":{"rollover":{"max_age":"7d"}}}}}}`)
+	helper.OK(t, err)
+	created, err := client.ILM().Bootstrap("logs", "logs", `{"settings":{"number_of_shards":1},"mappings":{"properties":{"message":{"type":"text"}}}}`)
+	helper.OK(t, err)
+	helper.Assert(t, created, "The initial write index is expected to be created")
+
+	helper.Equals(t, `PUT /_template/staging-logs {"index_patterns":["staging-logs-*"],"mappings":{"properties":{"message":{"type":"text"}}},"settings":{"index.lifecycle.name":"logs","index.lifecycle.rollover_alias":"staging-logs","number_of_shards":1}}`, requests[1])
+	helper.Equals(t, "HEAD /_alias/staging-logs ", requests[2])
+	helper.Equals(t, `PUT /staging-logs-000001 {"aliases":{"staging-logs":{"is_write_index":true}}}`, requests[3])
+
+	aliasExists = true
+	created, err = client.ILM().Bootstrap("logs", "logs", "")
+	helper.OK(t, err)
+	helper.Assert(t, !created, "The initial write index isn't expected to be created again")
+	helper.Equals(t, 6, len(requests))
+
+	result, err := client.Rollover("logs", `{"conditions":{"max_age":"7d"}}`)
+	helper.OK(t, err)
+	helper.Assert(t, result.RolledOver, "The alias is expected to be rolled over")
+	helper.Equals(t, "staging-logs-000002", result.NewIndex)
+	helper.Equals(t, map[string]bool{"[max_age: 7d]": true}, result.Conditions)
+}
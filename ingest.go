@@ -0,0 +1,114 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Ingest exposes the GeoIP APIs of the ingest nodes, to check that the GeoIP databases used by the
+// geoip processor are downloaded and loaded before enabling the pipelines using it
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/geoip-processor.html
+type Ingest interface {
+	GeoIPStats() (*GeoIPStats, error)
+	PutGeoIPDatabase(id, body string) (*Response, error)
+	GetGeoIPDatabases(id string) (*GeoIPDatabases, error)
+	DeleteGeoIPDatabase(id string) (*Response, error)
+}
+
+// GeoIPStats represents the download statistics of the GeoIP databases, and the databases loaded by node
+type GeoIPStats struct {
+	RawResponse
+
+	Stats struct {
+		SuccessfulDownloads     int64 `json:"successful_downloads"`
+		FailedDownloads         int64 `json:"failed_downloads"`
+		TotalDownloadTimeMillis int64 `json:"total_download_time"`
+		DatabasesCount          int   `json:"databases_count"`
+		SkippedUpdates          int64 `json:"skipped_updates"`
+		ExpiredDatabases        int   `json:"expired_databases"`
+	} `json:"stats"`
+	Nodes map[string]struct {
+		Databases []struct {
+			Name string `json:"name"` // such as GeoLite2-City.mmdb
+		} `json:"databases"`
+		FilesInTemp []string `json:"files_in_temp"`
+	} `json:"nodes"`
+}
+
+// Missing returns the sorted identifiers of the nodes which haven't loaded all the databases, such as
+// "GeoLite2-City.mmdb". The databases are loaded lazily by the nodes running a geoip processor.
+func (s *GeoIPStats) Missing(databases ...string) []string {
+	missing := []string{}
+	for id, node := range s.Nodes {
+		loaded := map[string]bool{}
+		for _, database := range node.Databases {
+			loaded[database.Name] = true
+		}
+		for _, database := range databases {
+			if !loaded[database] {
+				missing = append(missing, id)
+				break
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// GeoIPDatabases represents the configurations of the GeoIP databases downloaded by the cluster
+type GeoIPDatabases struct {
+	RawResponse
+
+	Databases []struct {
+		ID           string          `json:"id"`
+		Version      int64           `json:"version"`
+		ModifiedDate int64           `json:"modified_date_millis"`
+		Database     json.RawMessage `json:"database"` // such as {"name": "GeoIP2-City", "maxmind": {"account_id": "1234567"}}
+	} `json:"databases"`
+}
+
+type ingest struct {
+	client *client
+}
+
+// Ingest returns the client of the ingest APIs
+func (c *client) Ingest() Ingest {
+	return &ingest{client: c}
+}
+
+// GeoIPStats returns the download statistics of the GeoIP databases, and the databases loaded by node
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/geoip-stats-api.html
+func (i *ingest) GeoIPStats() (*GeoIPStats, error) {
+	result := &GeoIPStats{}
+	if err := i.client.sendAPIRequest("GET", "/_ingest/geoip/stats", "", result); err != nil {
+		return &GeoIPStats{}, err
+	}
+	return result, nil
+}
+
+// PutGeoIPDatabase creates or updates the configuration of a GeoIP database downloaded from MaxMind,
+// such as {"name": "GeoIP2-City", "maxmind": {"account_id": "1234567"}}
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/put-geoip-database-api.html
+func (i *ingest) PutGeoIPDatabase(id, body string) (*Response, error) {
+	return i.client.acknowledge("PUT", "/_ingest/geoip/database/"+escapePath(id), body)
+}
+
+// GetGeoIPDatabases returns the configuration of a GeoIP database, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/get-geoip-database-api.html
+func (i *ingest) GetGeoIPDatabases(id string) (*GeoIPDatabases, error) {
+	path := "/_ingest/geoip/database"
+	if id != "" {
+		path += "/" + escapePath(id)
+	}
+	result := &GeoIPDatabases{}
+	if err := i.client.sendAPIRequest("GET", path, "", result); err != nil {
+		return &GeoIPDatabases{}, err
+	}
+	return result, nil
+}
+
+// DeleteGeoIPDatabase deletes the configuration of a GeoIP database
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/delete-geoip-database-api.html
+func (i *ingest) DeleteGeoIPDatabase(id string) (*Response, error) {
+	return i.client.acknowledge("DELETE", "/_ingest/geoip/database/"+escapePath(id), "")
+}
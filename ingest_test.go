@@ -0,0 +1,36 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestGeoIP(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_ingest/geoip/stats", http.StatusOK, `{"stats":{"successful_downloads":3,"failed_downloads":0,"total_download_time":5412,"databases_count":3,"skipped_updates":0,"expired_databases":0},
+		"nodes":{"node-b":{"databases":[{"name":"GeoLite2-ASN.mmdb"}],"files_in_temp":[]},"node-a":{"databases":[{"name":"GeoLite2-City.mmdb"},{"name":"GeoLite2-ASN.mmdb"}]},"node-c":{}}}`)
+	recorder.Respond("GET", "/_ingest/geoip/database/city", http.StatusOK, `{"databases":[{"id":"city","version":1,"modified_date_millis":1700000000000,"database":{"name":"GeoIP2-City","maxmind":{"account_id":"1234567"}}}]}`)
+	ingest := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder)).Ingest()
+
+	stats, err := ingest.GeoIPStats()
+	helper.OK(t, err)
+	helper.Equals(t, 3, stats.Stats.DatabasesCount)
+	helper.Equals(t, []string{"node-b", "node-c"}, stats.Missing("GeoLite2-City.mmdb"))
+	helper.Equals(t, []string{"node-c"}, stats.Missing("GeoLite2-ASN.mmdb"))
+
+	_, err = ingest.PutGeoIPDatabase("city", `{"name":"GeoIP2-City","maxmind":{"account_id":"1234567"}}`)
+	helper.OK(t, err)
+	databases, err := ingest.GetGeoIPDatabases("city")
+	helper.OK(t, err)
+	helper.Equals(t, "city", databases.Databases[0].ID)
+	helper.Equals(t, `{"name":"GeoIP2-City","maxmind":{"account_id":"1234567"}}`, string(databases.Databases[0].Database))
+	_, err = ingest.DeleteGeoIPDatabase("city")
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "PUT /_ingest/geoip/database/city\n"+`{"name":"GeoIP2-City","maxmind":{"account_id":"1234567"}}`, requests[1].String())
+	helper.Equals(t, "DELETE /_ingest/geoip/database/city", requests[3].String())
+}
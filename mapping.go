@@ -0,0 +1,233 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildMapping generates the mapping of the documents represented by v, a struct or a pointer to a struct.
+// The fields are named after their json tag, and their type is inferred from their Go type
+// (string as text, int as long, time.Time as date, struct as object...) unless set by the es tag.
+// The other parameters of the es tag are copied to the mapping of the field, and "fields.<name>"
+// adds a multi-field of the given type. The "id" option marks the identifier used by Repository:
+//
+//	type Product struct {
+//		ID       string    `json:"id" es:"id,type:keyword"`
+//		Name     string    `json:"name" es:"type:text,analyzer:english,fields.raw:keyword"`
+//		Colors   []string  `json:"colors" es:"type:keyword"`
+//		Price    float64   `json:"price" es:"type:scaled_float,scaling_factor:100"`
+//		Created  time.Time `json:"created"`
+//		Internal string    `json:"internal" es:"-"`
+//	}
+//
+// The returned mapping is the body of PutMapping, see IndexMapping for the body of CreateIndex.
+func BuildMapping(v interface{}) (map[string]interface{}, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot build the mapping of %v, a struct is expected", t)
+	}
+
+	properties, err := mappingProperties(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"properties": properties}, nil
+}
+
+// MappingJSON generates the mapping of v as JSON, to be used with PutMapping
+func MappingJSON(v interface{}) (string, error) {
+	mapping, err := BuildMapping(v)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(mapping)
+	return string(body), err
+}
+
+// IndexMapping generates the body of CreateIndex for the documents represented by v
+func IndexMapping(v interface{}) (string, error) {
+	mapping, err := BuildMapping(v)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(map[string]interface{}{"mappings": mapping})
+	return string(body), err
+}
+
+// idTagOption marks the field holding the identifier of the document in the es tag
+const idTagOption = "id"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// mappingProperties returns the properties of the fields of a struct, the embedded structs being flattened
+func mappingProperties(t reflect.Type, visiting map[reflect.Type]bool) (map[string]interface{}, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("cannot build the mapping of the recursive type %v", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+
+		tag := field.Tag.Get("es")
+		name, skip := jsonFieldName(field)
+		if skip || tag == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded, err := mappingProperties(fieldType, visiting)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range embedded {
+				if _, found := properties[key]; !found {
+					properties[key] = value
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := fieldMapping(fieldType, tag, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if property != nil {
+			properties[name] = property
+		}
+	}
+	return properties, nil
+}
+
+// jsonFieldName returns the name of a field from its json tag, empty if the tag has no name
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+// fieldMapping returns the mapping of a field from its type and es tag, nil when no type can be inferred
+func fieldMapping(t reflect.Type, tag string, visiting map[reflect.Type]bool) (map[string]interface{}, error) {
+	property := map[string]interface{}{}
+	fields := map[string]interface{}{}
+	for _, option := range strings.Split(tag, ",") {
+		if option == "" || option == idTagOption {
+			continue
+		}
+		parts := strings.SplitN(option, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid es tag option %q, key:value expected", option)
+		}
+		if strings.HasPrefix(parts[0], "fields.") {
+			fields[strings.TrimPrefix(parts[0], "fields.")] = map[string]interface{}{"type": parts[1]}
+			continue
+		}
+		property[parts[0]] = tagValue(parts[1])
+	}
+	if len(fields) > 0 {
+		property["fields"] = fields
+	}
+
+	// Arrays have the mapping of their elements
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if t == rawMessageType || t.Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		t = t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+
+	kind, _ := property["type"].(string)
+	if kind == "" {
+		kind = inferType(t)
+		if kind == "" {
+			return nil, nil
+		}
+		property["type"] = kind
+	}
+
+	if (kind == "object" || kind == "nested") && t.Kind() == reflect.Struct && t != timeType {
+		properties, err := mappingProperties(t, visiting)
+		if err != nil {
+			return nil, err
+		}
+		property["properties"] = properties
+	}
+	return property, nil
+}
+
+// inferType returns the Elasticsearch type of a Go type, empty when it cannot be inferred
+func inferType(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "date"
+	case t == rawMessageType:
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8:
+		return "byte"
+	case reflect.Int16:
+		return "short"
+	case reflect.Int32:
+		return "integer"
+	case reflect.Int, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "long"
+	case reflect.Uint, reflect.Uint64:
+		return "unsigned_long"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	case reflect.Slice:
+		return "binary" // []byte, encoded as base64 by encoding/json
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return ""
+}
+
+// tagValue converts the value of an es tag option to a boolean or a number when possible
+func tagValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+type mappingBase struct {
+	ID string `json:"id" es:"type:keyword"`
+}
+
+type mappingVariant struct {
+	SKU   string `json:"sku" es:"type:keyword,ignore_above:64"`
+	Stock int32  `json:"stock"`
+}
+
+type mappingProduct struct {
+	mappingBase
+	Name      string           `json:"name" es:"type:text,analyzer:english,fields.raw:keyword"`
+	Colors    []string         `json:"colors" es:"type:keyword"`
+	Price     float64          `json:"price" es:"type:scaled_float,scaling_factor:100"`
+	Available *bool            `json:"available,omitempty"`
+	Created   time.Time        `json:"created"`
+	Variants  []mappingVariant `json:"variants" es:"type:nested"`
+	Vendor    mappingVariant   `json:"vendor"`
+	Extra     json.RawMessage  `json:"extra"`
+	Internal  string           `json:"internal" es:"-"`
+	Ignored   string           `json:"-"`
+	Views     uint64
+}
+
+func TestBuildMapping(t *testing.T) {
+	helper := Test{}
+	mapping, err := elasticsearch.MappingJSON(&mappingProduct{})
+	helper.OK(t, err)
+
+	expected := `{"properties":{
+		"id":{"type":"keyword"},
+		"name":{"type":"text","analyzer":"english","fields":{"raw":{"type":"keyword"}}},
+		"colors":{"type":"keyword"},
+		"price":{"type":"scaled_float","scaling_factor":100},
+		"available":{"type":"boolean"},
+		"created":{"type":"date"},
+		"variants":{"type":"nested","properties":{"sku":{"type":"keyword","ignore_above":64},"stock":{"type":"integer"}}},
+		"vendor":{"type":"object","properties":{"sku":{"type":"keyword","ignore_above":64},"stock":{"type":"integer"}}},
+		"Views":{"type":"unsigned_long"}
+	}}`
+	var actual, wanted interface{}
+	helper.OK(t, json.Unmarshal([]byte(mapping), &actual))
+	helper.OK(t, json.Unmarshal([]byte(expected), &wanted))
+	helper.Equals(t, wanted, actual)
+
+	_, err = elasticsearch.BuildMapping("not a struct")
+	helper.Assert(t, err != nil, "Only structs can be mapped")
+
+	type invalid struct {
+		Name string `es:"analyzer"`
+	}
+	_, err = elasticsearch.BuildMapping(invalid{})
+	helper.Assert(t, err != nil, "An invalid tag must be rejected")
+}
+
+func TestGetMappings(t *testing.T) {
+	helper := Test{}
+	for version, mappings := range map[string]string{
+		"7.17.0": `{"orders":{"mappings":{"dynamic":"strict","properties":{"title":{"type":"text","analyzer":"english","fields":{"keyword":{"type":"keyword","ignore_above":256}}},"address":{"properties":{"city":{"type":"keyword"}}}}}}}`,
+		"6.8.0":  `{"orders":{"mappings":{"_doc":{"dynamic":"strict","properties":{"title":{"type":"text","analyzer":"english","fields":{"keyword":{"type":"keyword","ignore_above":256}}},"address":{"properties":{"city":{"type":"keyword"}}}}}}}}`,
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+			if r.URL.Path == "/" {
+				w.Write([]byte(`{"version":{"number":"` + version + `"},"tagline":"You Know, for Search"}`))
+				return
+			}
+			w.Write([]byte(mappings))
+		}))
+
+		indices, err := elasticsearch.NewClientFromUrl(server.URL).GetMappings("orders")
+		helper.OK(t, err)
+		orders := indices["orders"]
+		helper.Equals(t, "strict", orders.Dynamic)
+		title, found := orders.Field("title")
+		helper.Assert(t, found, "The title field is expected to be found on "+version)
+		helper.Equals(t, "english", title.Analyzer)
+		keyword, found := orders.Field("title.keyword")
+		helper.Assert(t, found, "The multi-field is expected to be found on "+version)
+		helper.Equals(t, 256, keyword.IgnoreAbove)
+		city, found := orders.Field("address.city")
+		helper.Assert(t, found, "The sub-field is expected to be found on "+version)
+		helper.Equals(t, "keyword", city.Type)
+		_, found = orders.Field("address.country")
+		helper.Assert(t, !found, "A missing field isn't expected to be found")
+		if version == "6.8.0" {
+			helper.Equals(t, "keyword", orders.Types["_doc"].Properties["address"].Properties["city"].Type)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,119 @@
+package elasticsearch
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestMetrics describes a request sent to Elasticsearch
+type RequestMetrics struct {
+	Endpoint   string        // method and path of the request, index names, document ids and other names replaced by placeholders
+	StatusCode int           // 0 when no response has been received
+	Duration   time.Duration // time until the response headers have been received
+	Retries    int           // number of attempts which preceded this one
+	Err        error
+}
+
+// Failed reports whether the request failed to get a response or got an error status code
+func (m RequestMetrics) Failed() bool {
+	return m.Err != nil || m.StatusCode >= 400
+}
+
+// MetricsHook is called after each request, it must be safe for concurrent use
+type MetricsHook func(RequestMetrics)
+
+// EndpointStats aggregates the metrics of the requests sent to an endpoint
+type EndpointStats struct {
+	Requests      int64
+	Errors        int64
+	Retries       int64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+// AverageDuration returns the average duration of the requests
+func (s EndpointStats) AverageDuration() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Requests)
+}
+
+// Stats aggregates the metrics of the requests per endpoint.
+// Its Record method can be used as a MetricsHook:
+//
+//	stats := elasticsearch.NewStats()
+//	client := elasticsearch.NewClient("http", "localhost", "9200", elasticsearch.WithMetrics(stats.Record))
+type Stats struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointStats
+}
+
+// NewStats creates an empty Stats
+func NewStats() *Stats {
+	return &Stats{endpoints: map[string]*EndpointStats{}}
+}
+
+// Record adds the metrics of a request to the stats of its endpoint
+func (s *Stats) Record(m RequestMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, found := s.endpoints[m.Endpoint]
+	if !found {
+		stats = &EndpointStats{}
+		s.endpoints[m.Endpoint] = stats
+	}
+
+	stats.Requests++
+	stats.Retries += int64(m.Retries)
+	if m.Failed() {
+		stats.Errors++
+	}
+	stats.TotalDuration += m.Duration
+	if m.Duration > stats.MaxDuration {
+		stats.MaxDuration = m.Duration
+	}
+}
+
+// Snapshot returns a copy of the stats of every endpoint
+func (s *Stats) Snapshot() map[string]EndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]EndpointStats, len(s.endpoints))
+	for endpoint, stats := range s.endpoints {
+		snapshot[endpoint] = *stats
+	}
+	return snapshot
+}
+
+// endpointName replaces the index names, document ids and other names of a path by placeholders,
+// such as the user of /_security/user/{name}, so every request to the same API shares the same
+// endpoint name and the number of endpoints is bounded
+func endpointName(method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	api := false
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, "_"):
+			api = true
+		case !api:
+			segments[i] = "{index}"
+		case isDocumentAPI(segments[i-1]):
+			segments[i] = "{id}"
+		default:
+			segments[i] = "{name}"
+		}
+	}
+	return method + " /" + strings.Join(segments, "/")
+}
+
+func isDocumentAPI(segment string) bool {
+	switch segment {
+	case "_doc", "_create", "_update", "_source", "_explain":
+		return true
+	}
+	return false
+}
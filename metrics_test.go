@@ -0,0 +1,64 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestMetrics(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	stats := elasticsearch.NewStats()
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithMetrics(stats.Record))
+	client.InsertDocument(IndexName, ProductDocumentType, "1", []byte(`{}`))
+	client.InsertDocument(IndexName, ProductDocumentType, "2", []byte(`{}`))
+	client.Search(IndexName, ProductDocumentType, `{}`, false)
+	client.DeleteIndex(IndexName)
+
+	snapshot := stats.Snapshot()
+	helper.Equals(t, int64(2), snapshot["POST /{index}/_doc/{id}"].Requests)
+	helper.Equals(t, int64(1), snapshot["POST /{index}/_search"].Requests)
+	helper.Equals(t, int64(1), snapshot["DELETE /{index}"].Errors)
+}
+
+func TestMetricsEndpoints(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var endpoint string
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithMetrics(func(m elasticsearch.RequestMetrics) {
+		endpoint = m.Endpoint
+	}))
+	for _, test := range []struct {
+		method, path, endpoint string
+	}{
+		{"GET", "/orders/_doc/1", "GET /{index}/_doc/{id}"},
+		{"GET", "/orders/_source/1", "GET /{index}/_source/{id}"},
+		{"POST", "/orders,logs-*/_search", "POST /{index}/_search"},
+		{"GET", "/_security/user/alice", "GET /_security/{name}/{name}"},
+		{"PUT", "/_scripts/my-script", "PUT /_scripts/{name}"},
+		{"DELETE", "/_dangling/zmM4e0JtBkeUjiHD-MihPQ", "DELETE /_dangling/{name}"},
+		{"POST", "/_transform/orders-by-day/_start", "POST /_transform/{name}/_start"},
+		{"GET", "/_ilm/policy/logs", "GET /_ilm/{name}/{name}"},
+		{"GET", "/_cluster/health/orders", "GET /_cluster/{name}/{name}"},
+		{"GET", "/_cluster/health", "GET /_cluster/{name}"},
+	} {
+		response, err := client.Do(test.method, test.path, nil, nil)
+		helper.OK(t, err)
+		response.Body.Close()
+		helper.Equals(t, test.endpoint, endpoint)
+	}
+}
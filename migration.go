@@ -0,0 +1,408 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IndexSchema declares the mappings and settings expected on an index, such as
+// {"properties": {...}} and {"number_of_replicas": 1, "refresh_interval": "5s"}
+type IndexSchema struct {
+	Mappings string
+	Settings string
+}
+
+// Schema change kinds reported by PlanMigration
+const (
+	ChangeIndexMissing   = "index_missing"
+	ChangeFieldAdded     = "field_added"
+	ChangeFieldUpdated   = "field_updated"
+	ChangeFieldConflict  = "field_conflict"
+	ChangeMappingUpdated = "mapping_updated"
+	ChangeSettingUpdated = "setting_updated"
+	ChangeSettingStatic  = "setting_static"
+)
+
+// SchemaChange represents a difference between the desired schema and the live index.
+// A breaking change cannot be applied in place, the documents must be reindexed into a new index.
+type SchemaChange struct {
+	Kind     string
+	Path     string // field path such as "variants.sku", or setting name such as "number_of_replicas"
+	Actual   string
+	Desired  string
+	Breaking bool
+
+	value interface{}
+}
+
+func (c SchemaChange) String() string {
+	switch {
+	case c.Kind == ChangeIndexMissing:
+		return "index is missing"
+	case c.Actual == "":
+		return fmt.Sprintf("%s: %s is missing", c.Kind, c.Path)
+	}
+	return fmt.Sprintf("%s: %s is %s instead of %s", c.Kind, c.Path, c.Actual, c.Desired)
+}
+
+// MigrationPlan represents the changes needed to migrate an index to the desired schema
+type MigrationPlan struct {
+	Index   string
+	Desired IndexSchema
+	Changes []SchemaChange
+}
+
+// BreakingChangeError is returned when applying a plan containing breaking changes
+type BreakingChangeError struct {
+	Index   string
+	Changes []SchemaChange
+}
+
+func (e *BreakingChangeError) Error() string {
+	changes := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		changes[i] = change.String()
+	}
+	return "index " + e.Index + " must be reindexed: " + strings.Join(changes, ", ")
+}
+
+// updatableFieldParams are the mapping parameters of an existing field which can be changed in place
+var updatableFieldParams = map[string]bool{
+	"ignore_above":          true,
+	"search_analyzer":       true,
+	"search_quote_analyzer": true,
+	"ignore_malformed":      true,
+	"meta":                  true,
+}
+
+// updatableMappingParams are the root mapping parameters which can be changed in place
+var updatableMappingParams = map[string]bool{
+	"dynamic":           true,
+	"dynamic_templates": true,
+	"date_detection":    true,
+	"numeric_detection": true,
+	"_meta":             true,
+}
+
+// staticSettings are the index settings which cannot be changed on an open index
+var staticSettings = []string{
+	"number_of_shards",
+	"number_of_routing_shards",
+	"codec",
+	"routing_partition_size",
+	"soft_deletes.",
+	"sort.",
+	"analysis.",
+	"store.",
+	"shard.check_on_startup",
+}
+
+// PlanMigration compares the desired schema against the live index. Fields and settings present on the
+// index but not declared in the schema are ignored, as the defaults added by the cluster.
+func PlanMigration(client Client, indexName string, desired IndexSchema) (*MigrationPlan, error) {
+	plan := &MigrationPlan{Index: indexName, Desired: desired, Changes: []SchemaChange{}}
+
+	exists, err := client.IndexExists(indexName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		plan.Changes = append(plan.Changes, SchemaChange{Kind: ChangeIndexMissing, Path: indexName})
+		return plan, nil
+	}
+
+	indices, err := client.GetIndex(indexName)
+	if err != nil {
+		return nil, err
+	}
+	actual, found := indices[namespaceOf(client, indexName)]
+	if !found {
+		return nil, fmt.Errorf("index %s not found in the response, an alias cannot be migrated", indexName)
+	}
+
+	if desired.Mappings != "" {
+		changes, err := diffMappings(actual.Mappings, []byte(desired.Mappings))
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	if desired.Settings != "" {
+		changes, err := diffSettings(actual.Settings, []byte(desired.Settings))
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+// Migrate plans the migration of the index to the desired schema and applies it
+func Migrate(client Client, indexName string, desired IndexSchema) (*MigrationPlan, error) {
+	plan, err := PlanMigration(client, indexName, desired)
+	if err != nil {
+		return nil, err
+	}
+	return plan, plan.Apply(client)
+}
+
+// Breaking returns the changes requiring a reindex
+func (p *MigrationPlan) Breaking() []SchemaChange {
+	breaking := []SchemaChange{}
+	for _, change := range p.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// Apply creates the missing index, or applies the additive changes with PutMapping and UpdateIndexSetting.
+// Nothing is applied when the plan has a breaking change, a BreakingChangeError is returned instead.
+func (p *MigrationPlan) Apply(client Client) error {
+	if breaking := p.Breaking(); len(breaking) > 0 {
+		return &BreakingChangeError{Index: p.Index, Changes: breaking}
+	}
+
+	mappingChanged := false
+	settings := map[string]interface{}{}
+	for _, change := range p.Changes {
+		switch change.Kind {
+		case ChangeIndexMissing:
+			return p.createIndex(client)
+		case ChangeFieldAdded, ChangeFieldUpdated, ChangeMappingUpdated:
+			mappingChanged = true
+		case ChangeSettingUpdated:
+			settings["index."+change.Path] = change.value
+		}
+	}
+
+	if mappingChanged {
+		if err := responseError(client.PutMapping(p.Index, p.Desired.Mappings)); err != nil {
+			return err
+		}
+	}
+	if len(settings) > 0 {
+		body, err := json.Marshal(settings)
+		if err != nil {
+			return err
+		}
+		if err := responseError(client.UpdateIndexSetting(p.Index, string(body))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MigrationPlan) createIndex(client Client) error {
+	body := map[string]json.RawMessage{}
+	if p.Desired.Mappings != "" {
+		body["mappings"] = json.RawMessage(p.Desired.Mappings)
+	}
+	if p.Desired.Settings != "" {
+		body["settings"] = json.RawMessage(p.Desired.Settings)
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return responseError(client.CreateIndex(p.Index, string(encoded)))
+}
+
+func responseError(response *Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	return nil
+}
+
+// diffMappings compares the fields and the root parameters declared in the desired mapping
+func diffMappings(actual, desired []byte) ([]SchemaChange, error) {
+	actualMapping := map[string]interface{}{}
+	if len(actual) > 0 {
+		if err := json.Unmarshal(actual, &actualMapping); err != nil {
+			return nil, err
+		}
+	}
+	desiredMapping := map[string]interface{}{}
+	if err := json.Unmarshal(desired, &desiredMapping); err != nil {
+		return nil, fmt.Errorf("invalid desired mappings: %v", err)
+	}
+
+	// Elasticsearch 6 returns the mapping under its document type
+	if _, found := actualMapping["properties"]; !found && len(actualMapping) == 1 {
+		for _, typed := range actualMapping {
+			if inner, ok := typed.(map[string]interface{}); ok {
+				actualMapping = inner
+			}
+		}
+	}
+
+	changes := []SchemaChange{}
+	for _, key := range sortedMapKeys(desiredMapping) {
+		if key == "properties" {
+			continue
+		}
+		actualValue, desiredValue := encodeValue(actualMapping[key]), encodeValue(desiredMapping[key])
+		if _, found := actualMapping[key]; found && actualValue == desiredValue {
+			continue
+		}
+		if _, found := actualMapping[key]; !found {
+			actualValue = ""
+		}
+		changes = append(changes, SchemaChange{
+			Kind:     ChangeMappingUpdated,
+			Path:     key,
+			Actual:   actualValue,
+			Desired:  desiredValue,
+			Breaking: !updatableMappingParams[key],
+		})
+	}
+
+	actualProperties, _ := actualMapping["properties"].(map[string]interface{})
+	desiredProperties, _ := desiredMapping["properties"].(map[string]interface{})
+	return append(changes, diffProperties("", actualProperties, desiredProperties)...), nil
+}
+
+func diffProperties(prefix string, actual, desired map[string]interface{}) []SchemaChange {
+	changes := []SchemaChange{}
+	for _, name := range sortedMapKeys(desired) {
+		path := prefix + name
+		desiredField, _ := desired[name].(map[string]interface{})
+		actualField, found := actual[name].(map[string]interface{})
+		if !found {
+			changes = append(changes, SchemaChange{Kind: ChangeFieldAdded, Path: path, Desired: encodeValue(desiredField)})
+			continue
+		}
+		changes = append(changes, diffField(path, actualField, desiredField)...)
+	}
+	return changes
+}
+
+func diffField(path string, actual, desired map[string]interface{}) []SchemaChange {
+	changes := []SchemaChange{}
+	for _, key := range sortedMapKeys(desired) {
+		switch key {
+		case "properties", "fields":
+			actualProperties, _ := actual[key].(map[string]interface{})
+			desiredProperties, _ := desired[key].(map[string]interface{})
+			changes = append(changes, diffProperties(path+".", actualProperties, desiredProperties)...)
+			continue
+		}
+
+		actualValue, found := actual[key]
+		// Objects are returned without their default type
+		if !found && key == "type" && desired[key] == "object" {
+			continue
+		}
+		if found && encodeValue(actualValue) == encodeValue(desired[key]) {
+			continue
+		}
+
+		change := SchemaChange{
+			Kind:    ChangeFieldUpdated,
+			Path:    path + "." + key,
+			Desired: encodeValue(desired[key]),
+		}
+		if found {
+			change.Actual = encodeValue(actualValue)
+		}
+		if !updatableFieldParams[key] {
+			change.Kind = ChangeFieldConflict
+			change.Breaking = true
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffSettings compares the settings declared in the desired settings, with or without the index prefix
+func diffSettings(actual, desired []byte) ([]SchemaChange, error) {
+	var a, d interface{}
+	if len(actual) > 0 {
+		if err := json.Unmarshal(actual, &a); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(desired, &d); err != nil {
+		return nil, fmt.Errorf("invalid desired settings: %v", err)
+	}
+
+	actualValues := map[string]string{}
+	flatten("", a, actualValues)
+	desiredValues := map[string]interface{}{}
+	flattenValues("", d, desiredValues)
+
+	changes := []SchemaChange{}
+	keys := make([]string, 0, len(desiredValues))
+	for key := range desiredValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, "index.")
+		desiredValue := encodeValue(desiredValues[key])
+		actualValue, found := actualValues["index."+name]
+		if found && actualValue == desiredValue {
+			continue
+		}
+
+		change := SchemaChange{Kind: ChangeSettingUpdated, Path: name, Actual: actualValue, Desired: desiredValue, value: desiredValues[key]}
+		if staticSetting(name) {
+			change.Kind = ChangeSettingStatic
+			change.Breaking = true
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func staticSetting(name string) bool {
+	for _, static := range staticSettings {
+		if name == static || strings.HasSuffix(static, ".") && strings.HasPrefix(name, static) {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenValues is like flatten, keeping the leaf values as decoded
+func flattenValues(prefix string, v interface{}, values map[string]interface{}) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		values[prefix] = v
+		return
+	}
+	for k, child := range object {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flattenValues(key, child, values)
+	}
+}
+
+// encodeValue encodes a decoded JSON value to compare it, scalars being compared as strings
+// because the cluster returns some numbers and booleans as strings
+func encodeValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return fmt.Sprint(v)
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func migrationServer(exists bool, requests map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests[r.Method+" "+r.URL.Path] = string(body)
+		switch {
+		case r.Method == "HEAD" && !exists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET":
+			w.Write([]byte(`{"products":{"aliases":{},
+				"mappings":{"dynamic":"strict","properties":{
+					"name":{"type":"text","fields":{"raw":{"type":"keyword","ignore_above":256}}},
+					"price":{"type":"double"},
+					"vendor":{"properties":{"name":{"type":"keyword"}}}}},
+				"settings":{"index":{"number_of_shards":"1","number_of_replicas":"1","refresh_interval":"1s","uuid":"x"}}}}`))
+		default:
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+}
+
+func TestMigrationAdditive(t *testing.T) {
+	helper := Test{}
+	requests := map[string]string{}
+	server := migrationServer(true, requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	desired := elasticsearch.IndexSchema{
+		Mappings: `{"dynamic":"strict","properties":{
+			"name":{"type":"text","fields":{"raw":{"type":"keyword","ignore_above":512}}},
+			"price":{"type":"double"},
+			"vendor":{"type":"object","properties":{"name":{"type":"keyword"},"country":{"type":"keyword"}}},
+			"colors":{"type":"keyword"}}}`,
+		Settings: `{"number_of_shards":1,"index":{"number_of_replicas":2}}`,
+	}
+	plan, err := elasticsearch.PlanMigration(client, "products", desired)
+	helper.OK(t, err)
+	helper.Equals(t, []string{
+		"field_added: colors is missing",
+		"field_updated: name.raw.ignore_above is 256 instead of 512",
+		"field_added: vendor.country is missing",
+		"setting_updated: number_of_replicas is 1 instead of 2",
+	}, changeStrings(plan.Changes))
+	helper.Equals(t, 0, len(plan.Breaking()))
+
+	helper.OK(t, plan.Apply(client))
+	helper.Equals(t, desired.Mappings, requests["PUT /products/_mapping"])
+	helper.Equals(t, `{"index.number_of_replicas":2}`, requests["PUT /products/_settings"])
+}
+
+func TestMigrationBreaking(t *testing.T) {
+	helper := Test{}
+	requests := map[string]string{}
+	server := migrationServer(true, requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	plan, err := elasticsearch.Migrate(client, "products", elasticsearch.IndexSchema{
+		Mappings: `{"dynamic":false,"properties":{"price":{"type":"scaled_float","scaling_factor":100}}}`,
+		Settings: `{"number_of_shards":3}`,
+	})
+	breaking, ok := err.(*elasticsearch.BreakingChangeError)
+	helper.Assert(t, ok, "A breaking change error is expected, got %v", err)
+	helper.Equals(t, []string{
+		"field_conflict: price.scaling_factor is missing",
+		"field_conflict: price.type is double instead of scaled_float",
+		"setting_static: number_of_shards is 1 instead of 3",
+	}, changeStrings(breaking.Changes))
+	helper.Equals(t, 4, len(plan.Changes))
+	_, applied := requests["PUT /products/_mapping"]
+	helper.Assert(t, !applied, "Nothing must be applied")
+}
+
+func TestMigrationMissingIndex(t *testing.T) {
+	helper := Test{}
+	requests := map[string]string{}
+	server := migrationServer(false, requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := elasticsearch.Migrate(client, "products", elasticsearch.IndexSchema{
+		Mappings: `{"properties":{"name":{"type":"text"}}}`,
+		Settings: `{"number_of_shards":1}`,
+	})
+	helper.OK(t, err)
+	helper.Equals(t, `{"mappings":{"properties":{"name":{"type":"text"}}},"settings":{"number_of_shards":1}}`, requests["PUT /products"])
+}
+
+func TestMigrationNamespaced(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helper.Equals(t, "/staging-products", r.URL.Path)
+		if r.Method == "GET" {
+			w.Write([]byte(`{"staging-products":{"aliases":{},"mappings":{"properties":{"name":{"type":"text"}}},
+				"settings":{"index":{"number_of_shards":"1","number_of_replicas":"1"}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-"))
+	plan, err := elasticsearch.PlanMigration(client, "products", elasticsearch.IndexSchema{
+		Mappings: `{"properties":{"name":{"type":"text"},"price":{"type":"double"}}}`,
+		Settings: `{"number_of_replicas":1}`,
+	})
+	helper.OK(t, err)
+	helper.Equals(t, []string{"field_added: price is missing"}, changeStrings(plan.Changes))
+}
+
+func changeStrings(changes []elasticsearch.SchemaChange) []string {
+	strings := make([]string, len(changes))
+	for i, change := range changes {
+		strings[i] = change.String()
+	}
+	return strings
+}
@@ -0,0 +1,234 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// namespaced reports whether the client has an index namespace, see WithIndexPrefix
+func (c *client) namespaced() bool {
+	return c.indexPrefix != "" || c.indexSuffix != ""
+}
+
+// indexPath namespaces and escapes comma-separated indices or aliases to be used in a path
+func (c *client) indexPath(indices string) string {
+	return escapeIndices(c.namespace(indices))
+}
+
+// namespace adds the prefix and the suffix of the client to comma-separated indices or aliases.
+// The names already in the namespace are kept as is, such as the ones returned by the cluster.
+// The exclusions (-logs-old), the remote indices (cluster:logs) and the date math names are namespaced,
+// _all and * are restricted to the namespace. An empty list is kept empty.
+func (c *client) namespace(indices string) string {
+	if !c.namespaced() || indices == "" {
+		return indices
+	}
+
+	names := strings.Split(indices, ",")
+	for i, name := range names {
+		names[i] = c.namespaceName(name)
+	}
+	return strings.Join(names, ",")
+}
+
+// namespaceOf namespaces the indices for a client created by this package, see namespace.
+// The names are kept as is for the other implementations of Client.
+func namespaceOf(target Client, indices string) string {
+	if c, ok := target.(*client); ok {
+		return c.namespace(indices)
+	}
+	return indices
+}
+
+func (c *client) namespaceName(name string) string {
+	exclusion := ""
+	if strings.HasPrefix(name, "-") {
+		exclusion, name = "-", name[1:]
+	}
+	remote := ""
+	if colon := strings.LastIndexByte(name, ':'); colon >= 0 && !strings.HasPrefix(name, "<") {
+		remote, name = name[:colon+1], name[colon+1:]
+	}
+	if name == "_all" {
+		name = "*"
+	}
+
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		inner := name[1 : len(name)-1]
+		if !c.inNamespace(inner) {
+			inner = escapeDateMath(c.indexPrefix) + inner + escapeDateMath(c.indexSuffix)
+		}
+		return exclusion + remote + "<" + inner + ">"
+	}
+	if !c.inNamespace(name) {
+		name = c.indexPrefix + name + c.indexSuffix
+	}
+	return exclusion + remote + name
+}
+
+func (c *client) inNamespace(name string) bool {
+	return len(name) > len(c.indexPrefix)+len(c.indexSuffix) &&
+		strings.HasPrefix(name, c.indexPrefix) && strings.HasSuffix(name, c.indexSuffix)
+}
+
+// namespaceAliasActions namespaces the indices and the aliases of alias actions
+func (c *client) namespaceAliasActions(actions []AliasAction) []AliasAction {
+	if !c.namespaced() {
+		return actions
+	}
+
+	namespaced := make([]AliasAction, len(actions))
+	for i, action := range actions {
+		action.Index = c.namespace(action.Index)
+		action.Alias = c.namespace(action.Alias)
+		namespaced[i] = action
+	}
+	return namespaced
+}
+
+// namespaceMSearch namespaces the indices of the headers of multi search queries
+func (c *client) namespaceMSearch(queries []MSearchQuery) ([]MSearchQuery, error) {
+	if !c.namespaced() {
+		return queries, nil
+	}
+
+	namespaced := make([]MSearchQuery, len(queries))
+	for i, query := range queries {
+		header, err := c.namespaceMetadata([]byte(query.Header), "index")
+		if err != nil {
+			return nil, fmt.Errorf("invalid header of query %d: %v", i, err)
+		}
+		namespaced[i] = MSearchQuery{Header: string(header), Body: query.Body}
+	}
+	return namespaced, nil
+}
+
+// namespaceMetadata namespaces the index of a JSON object, a string or an array of strings under key.
+// The object is returned as is when it has no index.
+func (c *client) namespaceMetadata(object []byte, key string) ([]byte, error) {
+	if !bytes.Contains(object, []byte(`"`+key+`"`)) {
+		return object, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return nil, err
+	}
+	value, found := fields[key]
+	if !found {
+		return object, nil
+	}
+
+	var indices []string
+	if err := json.Unmarshal(value, &indices); err != nil {
+		var index string
+		if err := json.Unmarshal(value, &index); err != nil {
+			return nil, fmt.Errorf("%s must be a string or an array of strings", key)
+		}
+		indices = []string{index}
+	}
+	for i, index := range indices {
+		indices[i] = c.namespace(index)
+	}
+
+	var err error
+	if len(indices) == 1 && value[0] == '"' {
+		fields[key], err = json.Marshal(indices[0])
+	} else {
+		fields[key], err = json.Marshal(indices)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// namespaceBulk namespaces the indices of the actions of a bulk payload
+func (c *client) namespaceBulk(data []byte) ([]byte, error) {
+	if !c.namespaced() || !bytes.Contains(data, []byte(`"_index"`)) {
+		return data, nil
+	}
+
+	var namespaced bytes.Buffer
+	namespaced.Grow(len(data) + len(data)/10)
+	err := c.copyNamespacedBulk(&namespaced, bytes.NewReader(data))
+	return namespaced.Bytes(), err
+}
+
+// namespaceBulkReader namespaces the indices of the actions of a bulk payload streamed from r
+func (c *client) namespaceBulkReader(r io.Reader) io.Reader {
+	if !c.namespaced() {
+		return r
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(c.copyNamespacedBulk(writer, r))
+	}()
+	return reader
+}
+
+// copyNamespacedBulk copies a bulk payload, namespacing the _index of its actions.
+// An action is followed by its source, except delete which has none.
+func (c *client) copyNamespacedBulk(w io.Writer, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	source := false
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			if source {
+				source = false
+			} else {
+				action, hasSource, err := c.namespaceBulkAction(line)
+				if err != nil {
+					return err
+				}
+				line, source = action, hasSource
+			}
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// namespaceBulkAction namespaces the _index of an action line, and reports whether it has a source
+func (c *client) namespaceBulkAction(line []byte) ([]byte, bool, error) {
+	actions := map[string]json.RawMessage{}
+	if err := json.Unmarshal(line, &actions); err != nil {
+		return nil, false, fmt.Errorf("invalid bulk action %s: %v", bytes.TrimSpace(line), err)
+	}
+	_, deleted := actions[BulkDelete]
+	if !bytes.Contains(line, []byte(`"_index"`)) {
+		return line, !deleted, nil
+	}
+
+	for name, metadata := range actions {
+		namespaced, err := c.namespaceMetadata(metadata, "_index")
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid bulk action %s: %v", bytes.TrimSpace(line), err)
+		}
+		actions[name] = namespaced
+	}
+
+	encoded, err := json.Marshal(actions)
+	if err != nil {
+		return nil, false, err
+	}
+	if bytes.HasSuffix(line, []byte("\n")) {
+		encoded = append(encoded, '\n')
+	}
+	return encoded, !deleted, nil
+}
@@ -0,0 +1,80 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestIndexNamespace(t *testing.T) {
+	helper := Test{}
+	var uri, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.RequestURI
+		received, _ := ioutil.ReadAll(r.Body)
+		body = string(received)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-"))
+	_, err := client.Search("products,staging-orders,logs-*,-logs-old,_all,remote:products", "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, "/staging-products,staging-orders,staging-logs-*,-staging-logs-old,staging-*,remote:staging-products/_search", uri)
+
+	_, err = client.CreateIndex("<logs-{now/d}>", "")
+	helper.OK(t, err)
+	helper.Equals(t, "/%3Cstaging-logs-%7Bnow%2Fd%7D%3E", uri)
+
+	_, err = client.Document("products", "", "1")
+	helper.OK(t, err)
+	helper.Equals(t, "/staging-products/_doc/1", uri)
+
+	bulk := `{"index":{"_index":"products","_id":"1"}}
+{"name":"delete"}
+{"delete":{"_index":"products","_id":"2"}}
+{"update":{"_id":"3"}}
+{"doc":{"_index":"products"}}
+`
+	expected := `{"index":{"_id":"1","_index":"staging-products"}}
+{"name":"delete"}
+{"delete":{"_id":"2","_index":"staging-products"}}
+{"update":{"_id":"3"}}
+{"doc":{"_index":"products"}}
+`
+	_, err = client.Bulk("products", []byte(bulk))
+	helper.OK(t, err)
+	helper.Equals(t, "/staging-products/_bulk", uri)
+	helper.Equals(t, expected, body)
+
+	_, err = client.BulkReader("", strings.NewReader(bulk), int64(len(bulk)))
+	helper.OK(t, err)
+	helper.Equals(t, expected, body)
+
+	_, err = client.MSearch([]elasticsearch.MSearchQuery{
+		{Header: `{"index":"products"}`, Body: `{}`},
+		{Header: `{"index":["products","orders"],"preference":"x"}`, Body: `{}`},
+		{Header: `{}`, Body: `{}`},
+	})
+	helper.OK(t, err)
+	helper.Equals(t, `{"index":"staging-products"}
+{}
+{"index":["staging-products","staging-orders"],"preference":"x"}
+{}
+{}
+{}
+`, body)
+
+	_, err = client.UpdateAlias([]string{"staging-products-1"}, []string{"products-2"}, "products")
+	helper.OK(t, err)
+	helper.Equals(t, `{"actions":[{"remove":{"index":"staging-products-1","alias":"staging-products"}},{"add":{"index":"staging-products-2","alias":"staging-products"}}]}`, body)
+
+	suffixed := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexSuffix("-eu"))
+	_, err = suffixed.Search("products,<logs-{now/d}>", "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, "/products-eu,%3Clogs-%7Bnow%2Fd%7D-eu%3E/_search", uri)
+}
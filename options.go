@@ -0,0 +1,350 @@
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientOption configures a client at creation
+type ClientOption func(*client)
+
+// WithRequestCompression gzips the request bodies of at least minSize bytes,
+// such as large bulk payloads
+func WithRequestCompression(minSize int) ClientOption {
+	return func(c *client) {
+		c.compressRequests = true
+		c.compressionMinSize = minSize
+	}
+}
+
+// WithResponseCompression asks Elasticsearch for gzipped responses, which are transparently decompressed
+func WithResponseCompression() ClientOption {
+	return func(c *client) {
+		c.compressResponses = true
+	}
+}
+
+// WithTypeless ignores the document types and uses the typeless routes (/{index}/_doc/{id}),
+// required by Elasticsearch 8 which rejects document types
+func WithTypeless() ClientOption {
+	return func(c *client) {
+		c.typeless = true
+	}
+}
+
+// WithCompatibility sends the REST API compatibility headers, asking a newer Elasticsearch
+// to answer as the given major version (compatible-with=7 for Elasticsearch 8)
+func WithCompatibility(major int) ClientOption {
+	return func(c *client) {
+		c.compatibleWith = major
+	}
+}
+
+// WithOpenSearch targets OpenSearch without waiting for its detection by Info:
+// the typeless routes are used and the Elasticsearch compatibility headers are not sent
+func WithOpenSearch() ClientOption {
+	return func(c *client) {
+		c.openSearch = true
+	}
+}
+
+// WithTransport sets the transport used to send the requests, such as a middleware wrapping
+// http.DefaultTransport
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *client) {
+		c.transport = transport
+	}
+}
+
+// WithMetrics reports the metrics of every request to hook, see Stats for a ready to use hook
+func WithMetrics(hook MetricsHook) ClientOption {
+	return func(c *client) {
+		c.metrics = hook
+	}
+}
+
+// WithRequestTimeout cancels every request taking longer than timeout, such as a request
+// blocked by a hung node. WithCallTimeout overrides it for a single request.
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithSearchTimeout sets the time Elasticsearch waits for each shard of every search before
+// returning partial results, unless the search has its own WithTimeout
+func WithSearchTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) {
+		c.searchTimeout = timeout
+	}
+}
+
+// WithDefaultFilterPath sets the filter_path of every request not having its own, trimming the
+// responses to the listed fields. Responses are then missing the other fields of the returned structs.
+func WithDefaultFilterPath(paths ...string) ClientOption {
+	return func(c *client) {
+		if c.defaultParams == nil {
+			c.defaultParams = url.Values{}
+		}
+		c.defaultParams.Set("filter_path", strings.Join(paths, ","))
+	}
+}
+
+// WithDebug asks for pretty printed and human readable responses, with the stack trace of the errors,
+// to troubleshoot failing requests from their logged responses. It's not meant for production.
+func WithDebug() ClientOption {
+	return func(c *client) {
+		if c.defaultParams == nil {
+			c.defaultParams = url.Values{}
+		}
+		c.defaultParams.Set("pretty", "true")
+		c.defaultParams.Set("human", "true")
+		c.defaultParams.Set("error_trace", "true")
+	}
+}
+
+// WithIndexPrefix prefixes every index and alias name with a tenant or environment namespace,
+// such as "staging-": in the paths, the bulk actions, the multi search headers and the alias actions.
+// The names already prefixed are kept, and the wildcards are restricted to the namespace.
+// The requests sent with Do and the bodies of the other requests, such as the index patterns
+// of the templates, are not namespaced.
+func WithIndexPrefix(prefix string) ClientOption {
+	return func(c *client) {
+		c.indexPrefix = prefix
+	}
+}
+
+// WithIndexSuffix is like WithIndexPrefix, with a namespace appended to the index and alias names
+func WithIndexSuffix(suffix string) ClientOption {
+	return func(c *client) {
+		c.indexSuffix = suffix
+	}
+}
+
+// RequestOption customizes a single request sent to Elasticsearch
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	params     url.Values
+	ctx        context.Context
+	timeout    time.Duration
+	hasTimeout bool
+
+	batchSize   int // queries per multi search request
+	concurrency int // multi search requests sent concurrently
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{params: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// url appends the query string parameters to a request URL
+func (o *requestOptions) url(base string) string {
+	if len(o.params) == 0 {
+		return base
+	}
+	return base + "?" + o.params.Encode()
+}
+
+// WithParam sets a query string parameter, for the ones without a dedicated option
+func WithParam(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.params.Set(key, value)
+	}
+}
+
+// WithFilterPath trims the response to the listed fields, such as "took", "hits.hits._id" or "items.*.error",
+// overriding the filter_path of the client. The response is then missing the other fields of the returned struct.
+func WithFilterPath(paths ...string) RequestOption {
+	return WithParam("filter_path", strings.Join(paths, ","))
+}
+
+// WithPretty asks for a pretty printed response
+func WithPretty() RequestOption {
+	return WithParam("pretty", "true")
+}
+
+// WithHuman asks for human readable values in the response, such as "1.2gb" along with the size in bytes
+func WithHuman() RequestOption {
+	return WithParam("human", "true")
+}
+
+// WithErrorTrace asks for the stack trace of the error returned by the request
+func WithErrorTrace() RequestOption {
+	return WithParam("error_trace", "true")
+}
+
+// WithExpandWildcards sets the states of the indices matched by wildcard expressions:
+// open, closed, hidden, none or all
+func WithExpandWildcards(states ...string) RequestOption {
+	return WithParam("expand_wildcards", strings.Join(states, ","))
+}
+
+// WithIgnoreUnavailable ignores the missing or closed indices instead of failing
+func WithIgnoreUnavailable(ignore bool) RequestOption {
+	return WithParam("ignore_unavailable", strconv.FormatBool(ignore))
+}
+
+// WithAllowNoIndices sets whether a wildcard expression or _all matching no index is accepted
+func WithAllowNoIndices(allow bool) RequestOption {
+	return WithParam("allow_no_indices", strconv.FormatBool(allow))
+}
+
+// WithSize sets the number of hits to return
+func WithSize(size int) RequestOption {
+	return WithParam("size", strconv.Itoa(size))
+}
+
+// WithFrom sets the offset of the first hit to return
+func WithFrom(from int) RequestOption {
+	return WithParam("from", strconv.Itoa(from))
+}
+
+// WithSort sorts the hits, each value being a field optionally followed by :asc or :desc
+func WithSort(sort ...string) RequestOption {
+	return WithParam("sort", strings.Join(sort, ","))
+}
+
+// WithSource restricts the fields of the _source returned in the hits
+func WithSource(fields ...string) RequestOption {
+	return WithParam("_source", strings.Join(fields, ","))
+}
+
+// WithSourceExcludes excludes fields from the _source returned in the hits
+func WithSourceExcludes(fields ...string) RequestOption {
+	return WithParam("_source_excludes", strings.Join(fields, ","))
+}
+
+// WithRouting targets the shards of the given routing values
+func WithRouting(routing ...string) RequestOption {
+	return WithParam("routing", strings.Join(routing, ","))
+}
+
+// WithPreference sets the nodes and shards used for the search (_local, _only_nodes:..., or a custom string).
+// A custom string, such as a user session ID, sends the searches with the same value to the same shard copies,
+// keeping the scoring and the order of the hits consistent across the pages of results. With MSearch, it is set
+// in the headers of the queries not having their own preference.
+func WithPreference(preference string) RequestOption {
+	return WithParam("preference", preference)
+}
+
+// PreferenceLocal is the preference for the shard copies of the node receiving the search
+const PreferenceLocal = "_local"
+
+// PreferenceOnlyNodes returns the preference restricting the search to the shard copies of the given nodes,
+// IDs or names such as "node-1" or "attr:value"
+func PreferenceOnlyNodes(nodes ...string) string {
+	return "_only_nodes:" + strings.Join(nodes, ",")
+}
+
+// PreferencePreferNodes returns the preference for the shard copies of the given nodes, when available
+func PreferencePreferNodes(nodes ...string) string {
+	return "_prefer_nodes:" + strings.Join(nodes, ",")
+}
+
+// PreferenceShards returns the preference restricting the search to the given shards, optionally followed by
+// another preference such as PreferenceLocal
+func PreferenceShards(shards []int, preference string) string {
+	ids := make([]string, len(shards))
+	for i, shard := range shards {
+		ids[i] = strconv.Itoa(shard)
+	}
+	if preference != "" {
+		return "_shards:" + strings.Join(ids, ",") + "|" + preference
+	}
+	return "_shards:" + strings.Join(ids, ",")
+}
+
+// WithTimeout sets the time Elasticsearch waits for each shard before returning partial results.
+// The request isn't canceled on the client side, see WithCallTimeout.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return WithParam("timeout", strconv.FormatInt(timeout.Milliseconds(), 10)+"ms")
+}
+
+// WithTerminateAfter sets the maximum number of documents to collect for each shard
+func WithTerminateAfter(count int) RequestOption {
+	return WithParam("terminate_after", strconv.Itoa(count))
+}
+
+// WithTrackTotalHits enables or disables the accurate count of the hits matching the query
+func WithTrackTotalHits(track bool) RequestOption {
+	return WithParam("track_total_hits", strconv.FormatBool(track))
+}
+
+// WithTrackTotalHitsUpTo counts the hits matching the query accurately up to limit
+func WithTrackTotalHitsUpTo(limit int) RequestOption {
+	return WithParam("track_total_hits", strconv.Itoa(limit))
+}
+
+// WithIfSeqNo only performs the write if the document has not been changed since the
+// sequence number and primary term returned by the last read or write
+func WithIfSeqNo(seqNo, primaryTerm int64) RequestOption {
+	return func(o *requestOptions) {
+		o.params.Set("if_seq_no", strconv.FormatInt(seqNo, 10))
+		o.params.Set("if_primary_term", strconv.FormatInt(primaryTerm, 10))
+	}
+}
+
+// WithVersion only performs the write if the version matches, according to versionType
+// (internal, external or external_gte). Prefer WithIfSeqNo with internal versioning.
+func WithVersion(version int64, versionType string) RequestOption {
+	return func(o *requestOptions) {
+		o.params.Set("version", strconv.FormatInt(version, 10))
+		if versionType != "" {
+			o.params.Set("version_type", versionType)
+		}
+	}
+}
+
+// WithRetryOnConflict retries an update up to retries times when the document is changed by another
+// write between its read and its reindexing, such as the concurrent increments of a counter
+func WithRetryOnConflict(retries int) RequestOption {
+	return WithParam("retry_on_conflict", strconv.Itoa(retries))
+}
+
+// WithWaitForActiveShards waits until count shard copies are active before a write is performed,
+// count being a number or "all"
+func WithWaitForActiveShards(count string) RequestOption {
+	return WithParam("wait_for_active_shards", count)
+}
+
+// WithContext sends the request with ctx, to cancel it or bound it by a deadline
+func WithContext(ctx context.Context) RequestOption {
+	return func(o *requestOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithMSearchBatchSize splits the queries of a multi search into requests of at most size queries.
+// The responses are returned in the order of the queries, a failing request failing the responses
+// of its queries only.
+func WithMSearchBatchSize(size int) RequestOption {
+	return func(o *requestOptions) {
+		o.batchSize = size
+	}
+}
+
+// WithMSearchConcurrency sends up to requests batches of a multi search concurrently, see WithMSearchBatchSize.
+// The batches are sent one after the other by default.
+func WithMSearchConcurrency(requests int) RequestOption {
+	return func(o *requestOptions) {
+		o.concurrency = requests
+	}
+}
+
+// WithCallTimeout cancels the request if it takes longer than timeout, overriding the
+// WithRequestTimeout of the client. A zero timeout disables it.
+func WithCallTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+		o.hasTimeout = true
+	}
+}
@@ -0,0 +1,522 @@
+package elasticsearch_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestSearchOptions(t *testing.T) {
+	helper := Test{}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := client.Search(IndexName, "", `{}`, true,
+		elasticsearch.WithSize(20),
+		elasticsearch.WithRouting("tenant&1"),
+		elasticsearch.WithSource("Name", "Colors"),
+		elasticsearch.WithTimeout(2*time.Second),
+		elasticsearch.WithTrackTotalHitsUpTo(1000))
+	helper.OK(t, err)
+	helper.Equals(t, "_source=Name%2CColors&explain=true&routing=tenant%261&size=20&timeout=2000ms&track_total_hits=1000", query)
+}
+
+func TestCreateDocumentConflict(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":{"type":"version_conflict_engine_exception","reason":"[1]: version conflict, document already exists"},"status":409}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := client.CreateDocument(IndexName, ProductDocumentType, "1", []byte(`{}`))
+	_, conflict := err.(*elasticsearch.ConflictError)
+	helper.Assert(t, conflict, "A conflict error is expected, got %v", err)
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithTypeless(), elasticsearch.WithDryRun(recorder))
+
+	_, err := client.InsertDocument(IndexName, "", "1", []byte(`{"Name":"shirt"}`), elasticsearch.WithIfSeqNo(12, 3))
+	helper.OK(t, err)
+	_, err = client.InsertDocument(IndexName, "", "1", []byte(`{"Name":"shirt"}`), elasticsearch.WithVersion(42, "external"))
+	helper.OK(t, err)
+	_, err = client.UpdateDocument(IndexName, "", "1", []byte(`{"doc":{"Name":"shirt"}}`), elasticsearch.WithIfSeqNo(12, 3))
+	helper.OK(t, err)
+	_, err = client.UpdateDocument(IndexName, "", "1", []byte(`{"doc":{"Name":"shirt"}}`), elasticsearch.WithRetryOnConflict(5))
+	helper.OK(t, err)
+	_, err = client.DeleteDocument(IndexName, "", "1", elasticsearch.WithIfSeqNo(12, 3))
+	helper.OK(t, err)
+	_, err = client.DeleteDocument(IndexName, "", "1", elasticsearch.WithVersion(42, ""))
+	helper.OK(t, err)
+
+	uris := []string{}
+	for _, request := range recorder.Requests() {
+		uris = append(uris, request.Method+" "+request.URL)
+	}
+	helper.Equals(t, []string{
+		"POST /test/_doc/1?if_primary_term=3&if_seq_no=12",
+		"POST /test/_doc/1?version=42&version_type=external",
+		"POST /test/_update/1?if_primary_term=3&if_seq_no=12",
+		"POST /test/_update/1?retry_on_conflict=5",
+		"DELETE /test/_doc/1?if_primary_term=3&if_seq_no=12",
+		"DELETE /test/_doc/1?version=42",
+	}, uris)
+
+	conflict := `{"error":{"type":"version_conflict_engine_exception","reason":"[1]: version conflict, required seqNo [12], primary term [3]. current document has seqNo [13] and primary term [3]"},"status":409}`
+	recorder.Respond("POST", "/test/_update/1", http.StatusConflict, conflict)
+	recorder.Respond("DELETE", "/test/_doc/1", http.StatusConflict, conflict)
+	_, err = client.UpdateDocument(IndexName, "", "1", []byte(`{"doc":{}}`), elasticsearch.WithIfSeqNo(12, 3))
+	_, isConflict := err.(*elasticsearch.ConflictError)
+	helper.Assert(t, isConflict, "A conflict error is expected on update, got %v", err)
+	helper.Equals(t, conflict, err.Error())
+	_, err = client.DeleteDocument(IndexName, "", "1", elasticsearch.WithIfSeqNo(12, 3))
+	_, isConflict = err.(*elasticsearch.ConflictError)
+	helper.Assert(t, isConflict, "A conflict error is expected on delete, got %v", err)
+}
+
+func TestWaitForActiveShards(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/test/_bulk", http.StatusOK, `{"took":3,"errors":false,"items":[{"index":{"_id":"1","status":201,"_shards":{"total":3,"successful":3,"failed":0}}}]}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithTypeless(), elasticsearch.WithDryRun(recorder))
+
+	_, err := client.InsertDocument(IndexName, "", "1", []byte(`{"Name":"shirt"}`), elasticsearch.WithWaitForActiveShards("all"))
+	helper.OK(t, err)
+	_, err = client.UpdateDocument(IndexName, "", "1", []byte(`{"doc":{"Name":"shirt"}}`), elasticsearch.WithWaitForActiveShards("2"))
+	helper.OK(t, err)
+	_, err = client.DeleteDocument(IndexName, "", "1", elasticsearch.WithWaitForActiveShards("2"))
+	helper.OK(t, err)
+	bulk, err := client.Bulk(IndexName, []byte(`{"index":{"_id":"1"}}`+"\n"+`{"Name":"shirt"}`+"\n"), elasticsearch.WithWaitForActiveShards("all"))
+	helper.OK(t, err)
+	helper.Equals(t, int64(3), bulk.Items[0].Shards.Successful)
+
+	uris := []string{}
+	for _, request := range recorder.Requests() {
+		uris = append(uris, request.Method+" "+request.URL)
+	}
+	helper.Equals(t, []string{
+		"POST /test/_doc/1?wait_for_active_shards=all",
+		"POST /test/_update/1?wait_for_active_shards=2",
+		"DELETE /test/_doc/1?wait_for_active_shards=2",
+		"POST /test/_bulk?wait_for_active_shards=all",
+	}, uris)
+}
+
+func TestCompression(t *testing.T) {
+	helper := Test{}
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helper.Equals(t, "gzip", r.Header.Get("Content-Encoding"))
+		reader, err := gzip.NewReader(r.Body)
+		helper.OK(t, err)
+		body, _ := ioutil.ReadAll(reader)
+		received = string(body)
+
+		helper.Equals(t, "gzip", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		writer.Write([]byte(`{"took":3,"errors":false,"items":[]}`))
+		writer.Close()
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithRequestCompression(0), elasticsearch.WithResponseCompression())
+	bulk, err := client.Bulk(IndexName, []byte(`{"delete":{"_id":"1"}}`+"\n"))
+	helper.OK(t, err)
+	helper.Equals(t, int64(3), bulk.Took)
+	helper.Equals(t, `{"delete":{"_id":"1"}}`+"\n", received)
+}
+
+func TestBulkReader(t *testing.T) {
+	helper := Test{}
+	var received string
+	var length int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		length = r.ContentLength
+		w.Write([]byte(`{"took":1,"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	payload := `{"delete":{"_id":"1"}}` + "\n"
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := client.BulkReader(IndexName, strings.NewReader(payload), int64(len(payload)))
+	helper.OK(t, err)
+	helper.Equals(t, payload, received)
+	helper.Equals(t, int64(len(payload)), length)
+}
+
+func TestDo(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(r.Method + " " + r.URL.String()))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	response, err := client.Do("GET", "/_ilm/policy/logs", url.Values{"human": {"true"}}, nil)
+	helper.OK(t, err)
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	helper.Equals(t, http.StatusNotFound, response.StatusCode)
+	helper.Equals(t, "GET /_ilm/policy/logs?human=true", string(body))
+}
+
+func TestDoDefaultParams(t *testing.T) {
+	helper := Test{}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithDefaultFilterPath("*.policy"))
+	response, err := client.Do("GET", "/_ilm/policy/logs", nil, nil)
+	helper.OK(t, err)
+	response.Body.Close()
+	helper.Equals(t, "filter_path=%2A.policy", query)
+
+	response, err = client.Do("GET", "/_ilm/policy/logs", url.Values{"filter_path": {"logs"}}, nil)
+	helper.OK(t, err)
+	response.Body.Close()
+	helper.Equals(t, "filter_path=logs", query)
+}
+
+func TestDoTimeout(t *testing.T) {
+	helper := Test{}
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-released
+	}))
+	defer server.Close()
+	defer close(released)
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithRequestTimeout(20*time.Millisecond))
+	_, err := client.Do("GET", "/_ilm/policy/logs", nil, nil)
+	helper.Assert(t, errors.Is(err, context.DeadlineExceeded), "The request must time out, got %v", err)
+}
+
+func TestTypeless(t *testing.T) {
+	helper := Test{}
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		helper.Equals(t, "application/vnd.elasticsearch+json; compatible-with=7", r.Header.Get("Accept"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithTypeless(), elasticsearch.WithCompatibility(7))
+	client.Document(IndexName, ProductDocumentType, "1")
+	client.DocumentSource(IndexName, ProductDocumentType, "1")
+	client.Explain(IndexName, ProductDocumentType, "1", `{}`)
+	helper.Equals(t, []string{"/test/_doc/1", "/test/_source/1", "/test/_explain/1"}, paths)
+}
+
+func TestDocumentWritePaths(t *testing.T) {
+	helper := Test{}
+	for version, paths := range map[string][]string{
+		"6.8.0": {"PUT /test/product/1/_create", "POST /test/product/1/_update"},
+		"8.6.0": {"PUT /test/_create/1", "POST /test/_update/1"},
+	} {
+		recorder := elasticsearch.NewRequestRecorder()
+		recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"`+version+`"},"tagline":"You Know, for Search"}`)
+		client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+		_, err := client.CreateDocument(IndexName, "product", "1", []byte(`{"Name":"shirt"}`))
+		helper.OK(t, err)
+		_, err = client.UpdateDocument(IndexName, "product", "1", []byte(`{"doc":{"Name":"shirt"}}`))
+		helper.OK(t, err)
+
+		requests := recorder.Requests()
+		helper.Equals(t, paths[0], requests[len(requests)-2].Method+" "+requests[len(requests)-2].URL)
+		helper.Equals(t, paths[1], requests[len(requests)-1].Method+" "+requests[len(requests)-1].URL)
+	}
+}
+
+func TestOpenSearchDetection(t *testing.T) {
+	helper := Test{}
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/" {
+			helper.Equals(t, "", r.Header.Get("Accept"))
+		}
+		w.Write([]byte(`{"name":"node-1","version":{"distribution":"opensearch","number":"2.11.0"}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithCompatibility(7))
+	isOpenSearch, err := client.IsOpenSearch()
+	helper.OK(t, err)
+	helper.Assert(t, isOpenSearch, "OpenSearch has not been detected")
+
+	client.Document(IndexName, ProductDocumentType, "1")
+	helper.Equals(t, []string{"/", "/test/_doc/1"}, paths)
+}
+
+func TestMSearchBody(t *testing.T) {
+	helper := Test{}
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte(`{"responses":[]}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := client.MSearch([]elasticsearch.MSearchQuery{
+		{Header: "{\n  \"index\": \"test\"\n}", Body: "{\n  \"query\": {\"match\": {\"Name\": \"line\\nbreak\"}}\n}"},
+		{Body: `{"query": {"match_all": {}}}`},
+	})
+	helper.OK(t, err)
+	helper.Equals(t, "{\"index\":\"test\"}\n{\"query\":{\"match\":{\"Name\":\"line\\nbreak\"}}}\n{}\n{\"query\":{\"match_all\":{}}}\n", body)
+
+	_, err = client.MSearch([]elasticsearch.MSearchQuery{{Header: `{"index": test}`, Body: `{}`}})
+	helper.Assert(t, err != nil, "An invalid header must be rejected")
+}
+
+func TestPathEscaping(t *testing.T) {
+	helper := Test{}
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.RequestURI
+		w.Write([]byte(`{"_index":"test","_id":"x","found":true}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithTypeless())
+	for id, expected := range map[string]string{
+		"a/b":        "/test/_doc/a%2Fb",
+		"a#b?c=d":    "/test/_doc/a%23b%3Fc=d",
+		"with space": "/test/_doc/with%20space",
+		"été/日本":     "/test/_doc/%C3%A9t%C3%A9%2F%E6%97%A5%E6%9C%AC",
+	} {
+		_, err := client.Document(IndexName, ProductDocumentType, id)
+		helper.OK(t, err)
+		helper.Equals(t, expected, uri)
+	}
+
+	_, err := client.Search("test,logs-*", "", `{}`, false)
+	helper.OK(t, err)
+	helper.Equals(t, "/test,logs-*/_search", uri)
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	helper := Test{}
+	// The handler keeps running after the timed out request has returned
+	queries := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.RawQuery
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL,
+		elasticsearch.WithRequestTimeout(20*time.Millisecond),
+		elasticsearch.WithSearchTimeout(time.Second))
+	_, err := client.Search(IndexName, "", `{}`, false)
+	helper.Assert(t, err != nil, "The search must time out")
+	helper.Equals(t, "timeout=1000ms", <-queries)
+
+	_, err = client.Search(IndexName, "", `{}`, false,
+		elasticsearch.WithCallTimeout(time.Second),
+		elasticsearch.WithTimeout(500*time.Millisecond))
+	helper.OK(t, err)
+	helper.Equals(t, "timeout=500ms", <-queries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Document(IndexName, ProductDocumentType, "1",
+		elasticsearch.WithContext(ctx), elasticsearch.WithCallTimeout(0))
+	helper.Assert(t, errors.Is(err, context.Canceled), "The request must be canceled, got %v", err)
+}
+
+func TestFilterPath(t *testing.T) {
+	helper := Test{}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"errors":false}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithDefaultFilterPath("errors", "items.*.error"))
+	bulk, err := client.Bulk(IndexName, []byte("{\"delete\":{\"_id\":\"1\"}}\n"), elasticsearch.WithRouting("1"))
+	helper.OK(t, err)
+	helper.Assert(t, !bulk.Errors, "No error expected")
+	helper.Equals(t, "routing=1&filter_path=errors%2Citems.%2A.error", query)
+
+	_, err = client.Search(IndexName, "", `{}`, false, elasticsearch.WithFilterPath("hits.hits._id"))
+	helper.OK(t, err)
+	helper.Equals(t, "filter_path=hits.hits._id", query)
+
+	_, err = client.DeleteIndex(IndexName)
+	helper.OK(t, err)
+	helper.Equals(t, "filter_path=errors%2Citems.%2A.error", query)
+}
+
+func TestDebug(t *testing.T) {
+	helper := Test{}
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte("{\n  \"acknowledged\" : true\n}\n"))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithDebug())
+	response, err := client.DeleteIndex(IndexName)
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The pretty printed response must be decoded")
+	helper.Equals(t, url.Values{"pretty": {"true"}, "human": {"true"}, "error_trace": {"true"}}, query)
+
+	client = elasticsearch.NewClientFromUrl(server.URL)
+	_, err = client.Document(IndexName, ProductDocumentType, "1", elasticsearch.WithErrorTrace())
+	helper.OK(t, err)
+	helper.Equals(t, url.Values{"error_trace": {"true"}}, query)
+}
+
+func TestIndexPatterns(t *testing.T) {
+	helper := Test{}
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.RequestURI
+		w.Write([]byte(`{"logs-1":{"settings":{"index":{"number_of_shards":"1"}}},"logs-2":{"settings":{"index":{"number_of_shards":"2"}}}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	settings, err := client.IndicesSettings("logs-*,-logs-0",
+		elasticsearch.WithExpandWildcards("open", "hidden"),
+		elasticsearch.WithIgnoreUnavailable(true),
+		elasticsearch.WithAllowNoIndices(false))
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(settings))
+	helper.Equals(t, "/logs-*,-logs-0/_settings?allow_no_indices=false&expand_wildcards=open%2Chidden&ignore_unavailable=true", uri)
+
+	_, err = client.DeleteIndex("logs-*", elasticsearch.WithExpandWildcards("all"))
+	helper.OK(t, err)
+	helper.Equals(t, "/logs-*?expand_wildcards=all", uri)
+}
+
+func TestWaitForStatus(t *testing.T) {
+	helper := Test{}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		helper.Equals(t, "/_cluster/health/products", r.URL.Path)
+		helper.Equals(t, "yellow", r.URL.Query().Get("wait_for_status"))
+		if calls == 1 || r.URL.Query().Get("timeout") == "1ms" {
+			w.WriteHeader(http.StatusRequestTimeout)
+			w.Write([]byte(`{"status":"red","timed_out":true}`))
+			return
+		}
+		w.Write([]byte(`{"status":"green","timed_out":false,"active_primary_shards":1}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	health, err := client.WaitForStatus("products", "yellow", 5*time.Second)
+	helper.OK(t, err)
+	helper.Equals(t, "green", health.Status)
+	helper.Equals(t, 2, calls)
+
+	_, err = client.WaitForStatus("products", "yellow", 0)
+	helper.Assert(t, err != nil, "The status isn't reached within the timeout")
+
+	_, err = client.WaitForStatus("products", "blue", time.Second)
+	helper.Assert(t, err != nil, "The status is invalid")
+}
+
+func TestMSearchBatches(t *testing.T) {
+	helper := Test{}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "down") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"type":"node_not_connected_exception"},"status":500}`))
+			return
+		}
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		responses := []string{}
+		for i := 1; i < len(lines); i += 2 {
+			if strings.Contains(lines[i], "fail") {
+				responses = append(responses, `{"error":{"type":"query_shard_exception","reason":"failed to create query"},"status":400}`)
+				continue
+			}
+			responses = append(responses, `{"status":200,"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_id":"`+lines[i][len(`{"id":"`):len(lines[i])-2]+`"}]}}`)
+		}
+		w.Write([]byte(`{"responses":[` + strings.Join(responses, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	queries := []elasticsearch.MSearchQuery{}
+	for _, id := range []string{"a", "fail", "c", "d", "down", "f", "g"} {
+		queries = append(queries, elasticsearch.MSearchQuery{Header: `{"index":"test"}`, Body: `{"id":"` + id + `"}`})
+	}
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	result, err := client.MSearch(queries, elasticsearch.WithMSearchBatchSize(3), elasticsearch.WithMSearchConcurrency(2))
+	helper.OK(t, err)
+	helper.Equals(t, int32(3), atomic.LoadInt32(&requests))
+	helper.Equals(t, 7, len(result.Responses))
+	helper.Equals(t, "a", result.Responses[0].Hits.Hits[0].ID)
+	helper.Equals(t, 400, result.Responses[1].Status)
+	helper.Equals(t, "query_shard_exception", result.Responses[1].Error.Type)
+	helper.Equals(t, "c", result.Responses[2].Hits.Hits[0].ID)
+	for _, response := range result.Responses[3:6] {
+		helper.Assert(t, response.Error != nil, "The queries of the failing batch must fail")
+	}
+	helper.Equals(t, "g", result.Responses[6].Hits.Hits[0].ID)
+	helper.Equals(t, 200, result.Responses[6].Status)
+}
+
+func TestMSearchPreference(t *testing.T) {
+	helper := Test{}
+	var body, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		body, query = string(content), r.URL.RawQuery
+		w.Write([]byte(`{"responses":[{},{}]}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	_, err := client.MSearch([]elasticsearch.MSearchQuery{
+		{Header: elasticsearch.MSearchHeader{Index: "products"}.String(), Body: `{}`},
+		{Header: elasticsearch.MSearchHeader{Index: "orders", Preference: elasticsearch.PreferenceLocal}.String(), Body: `{}`},
+	}, elasticsearch.WithPreference("session-42"))
+	helper.OK(t, err)
+	helper.Equals(t, "", query)
+	helper.Equals(t, "{\"index\":\"products\",\"preference\":\"session-42\"}\n{}\n{\"index\":\"orders\",\"preference\":\"_local\"}\n{}\n", body)
+
+	helper.Equals(t, "_only_nodes:node-1,node-2", elasticsearch.PreferenceOnlyNodes("node-1", "node-2"))
+	helper.Equals(t, "_shards:0,2|_local", elasticsearch.PreferenceShards([]int{0, 2}, elasticsearch.PreferenceLocal))
+}
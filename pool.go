@@ -0,0 +1,60 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which a buffer is not put back in the pool,
+// to avoid keeping the memory of an exceptionally large response
+const maxPooledBufferSize = 1 << 22
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBufferSize {
+		return
+	}
+	buffer.Reset()
+	bufferPool.Put(buffer)
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+func getGzipWriter(w io.Writer) *gzip.Writer {
+	writer := gzipWriterPool.Get().(*gzip.Writer)
+	writer.Reset(w)
+	return writer
+}
+
+func putGzipWriter(writer *gzip.Writer) {
+	gzipWriterPool.Put(writer)
+}
+
+// pooledBody is a request body put back in the pool when the transport closes it,
+// which may happen after the response is returned
+type pooledBody struct {
+	*bytes.Buffer
+	once sync.Once
+}
+
+func (b *pooledBody) Close() error {
+	b.once.Do(func() {
+		putBuffer(b.Buffer)
+	})
+	return nil
+}
@@ -0,0 +1,121 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// elasticsearchTagLine is the tag line returned by Elasticsearch before the X-Elastic-Product header
+const elasticsearchTagLine = "You Know, for Search"
+
+// WithProductCheck verifies on the first request that the server is a genuine Elasticsearch, as the
+// official clients do, and fails every request with an UnsupportedProductError otherwise.
+// OpenSearch is accepted when acceptOpenSearch is true, or when configured with WithOpenSearch.
+func WithProductCheck(acceptOpenSearch bool) ClientOption {
+	return func(c *client) {
+		c.productCheck = true
+		c.acceptOpenSearch = acceptOpenSearch
+	}
+}
+
+// UnsupportedProductError is returned when the product check finds the server is not a supported
+// Elasticsearch
+type UnsupportedProductError struct {
+	Product string // the X-Elastic-Product header, or the distribution for OpenSearch
+	Version string
+	Reason  string
+}
+
+func (e *UnsupportedProductError) Error() string {
+	return "the server is not a supported Elasticsearch: " + e.Reason
+}
+
+// checkProduct runs the product check once. A failure to reach the server is retried on the next
+// request, while an unsupported product fails every request.
+func (c *client) checkProduct() error {
+	if !c.productCheck {
+		return nil
+	}
+
+	c.productMutex.Lock()
+	defer c.productMutex.Unlock()
+	if c.productChecked {
+		return c.productErr
+	}
+
+	ctx, cancel := c.requestContext(nil)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", c.Host.String()+"/", nil)
+	if err != nil {
+		return err
+	}
+	response, body, err := c.respond(req, false)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// The product cannot be verified without the monitor privilege, the requests are let through
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		c.productChecked = true
+		return nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("product check: unexpected status %d", response.StatusCode)
+	}
+
+	info := &Status{}
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+	if _, err := buffer.ReadFrom(body); err != nil {
+		return err
+	}
+	if err := c.codec.Unmarshal(buffer.Bytes(), info); err != nil {
+		return err
+	}
+
+	c.productChecked = true
+	c.productErr = c.verifyProduct(response.Header.Get("X-Elastic-Product"), info)
+	if c.productErr == nil {
+		c.infoMutex.Lock()
+		if c.info == nil {
+			c.setInfo(info)
+		}
+		c.infoMutex.Unlock()
+	}
+	return c.productErr
+}
+
+// verifyProduct checks the X-Elastic-Product header from Elasticsearch 7.14,
+// and the tag line and build flavor on earlier versions
+func (c *client) verifyProduct(product string, info *Status) error {
+	unsupported := &UnsupportedProductError{Product: product, Version: info.Version.Number}
+	if info.Version.Distribution == "opensearch" {
+		if c.acceptOpenSearch || c.openSearch {
+			return nil
+		}
+		unsupported.Product = info.Version.Distribution
+		unsupported.Reason = "OpenSearch is not accepted, see WithProductCheck"
+		return unsupported
+	}
+	if product == "Elasticsearch" {
+		return nil
+	}
+
+	version, err := ParseVersion(info.Version.Number)
+	switch {
+	case err != nil:
+		unsupported.Reason = "unknown version " + info.Version.Number
+	case version.AtLeast(7, 14):
+		unsupported.Reason = "the X-Elastic-Product header is missing"
+	case !version.AtLeast(6, 0):
+		unsupported.Reason = "version " + version.String() + " is not supported"
+	case info.TagLine != elasticsearchTagLine:
+		unsupported.Reason = "unexpected tag line " + info.TagLine
+	case version.Major == 7 && info.Version.BuildFlavor != "default":
+		unsupported.Reason = "unexpected build flavor " + info.Version.BuildFlavor
+	default:
+		return nil
+	}
+	return unsupported
+}
@@ -0,0 +1,96 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func productServer(product, info string, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if product != "" {
+			w.Header().Set("X-Elastic-Product", product)
+		}
+		if r.URL.Path == "/" {
+			w.Write([]byte(info))
+			return
+		}
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+}
+
+func TestProductCheck(t *testing.T) {
+	helper := Test{}
+	requests := 0
+	server := productServer("Elasticsearch", `{"version":{"number":"8.11.0","build_flavor":"default"},"tagline":"You Know, for Search"}`, &requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithProductCheck(false))
+	_, err := client.DeleteIndex(IndexName)
+	helper.OK(t, err)
+	_, err = client.DeleteIndex(IndexName)
+	helper.OK(t, err)
+	version, err := client.ServerVersion()
+	helper.OK(t, err)
+	helper.Equals(t, 8, version.Major)
+	helper.Equals(t, 3, requests)
+}
+
+func TestProductCheckUnsupported(t *testing.T) {
+	helper := Test{}
+	for _, test := range []struct {
+		product, info string
+		accepted      bool
+	}{
+		{"", `{"version":{"number":"8.11.0","build_flavor":"default"},"tagline":"You Know, for Search"}`, false},
+		{"", `{"version":{"number":"7.10.2","build_flavor":"default"},"tagline":"You Know, for Search"}`, true},
+		{"", `{"version":{"number":"7.10.2","build_flavor":"oss"},"tagline":"You Know, for Search"}`, false},
+		{"", `{"version":{"number":"6.8.0"},"tagline":"You Know, for Search"}`, true},
+		{"", `{"version":{"number":"5.6.0"},"tagline":"You Know, for Search"}`, false},
+		{"", `{"version":{"number":"2.11.0","distribution":"opensearch"},"tagline":"The OpenSearch Project: https://opensearch.org/"}`, false},
+	} {
+		requests := 0
+		server := productServer(test.product, test.info, &requests)
+		client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithProductCheck(false))
+		_, err := client.DeleteIndex(IndexName)
+		server.Close()
+
+		if test.accepted {
+			helper.OK(t, err)
+			continue
+		}
+		_, unsupported := err.(*elasticsearch.UnsupportedProductError)
+		helper.Assert(t, unsupported, "An unsupported product error is expected for %s, got %v", test.info, err)
+		helper.Equals(t, 1, requests)
+	}
+}
+
+func TestProductCheckOpenSearch(t *testing.T) {
+	helper := Test{}
+	requests := 0
+	server := productServer("", `{"version":{"number":"2.11.0","distribution":"opensearch"}}`, &requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithProductCheck(true))
+	_, err := client.DeleteIndex(IndexName)
+	helper.OK(t, err)
+	openSearch, err := client.IsOpenSearch()
+	helper.OK(t, err)
+	helper.Assert(t, openSearch, "OpenSearch must be detected by the product check")
+}
+
+func TestProductCheckDo(t *testing.T) {
+	helper := Test{}
+	requests := 0
+	server := productServer("", `{"version":{"number":"8.11.0","build_flavor":"default"},"tagline":"You Know, for Search"}`, &requests)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithProductCheck(false))
+	_, err := client.Do("GET", "/_ilm/policy/logs", nil, nil)
+	_, unsupported := err.(*elasticsearch.UnsupportedProductError)
+	helper.Assert(t, unsupported, "An unsupported product error is expected, got %v", err)
+	helper.Equals(t, 1, requests)
+}
@@ -0,0 +1,318 @@
+package elasticsearch
+
+import "encoding/json"
+
+// MoreLikeThis represents a more_like_this query, finding the documents similar to the liked texts and
+// documents, such as the articles related to the one being read. Built with MoreLikeThisQuery.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-mlt-query.html
+type MoreLikeThis struct {
+	fields []string
+	like   []interface{}
+	unlike []interface{}
+	params map[string]interface{}
+}
+
+// MoreLikeThisQuery creates a more_like_this query on fields, all the fields of the index if none
+func MoreLikeThisQuery(fields ...string) *MoreLikeThis {
+	return &MoreLikeThis{fields: fields, params: map[string]interface{}{}}
+}
+
+// documentReference returns the reference of a document in a like or unlike clause
+func documentReference(indexName, documentType, identifier string) map[string]string {
+	reference := map[string]string{"_index": indexName, "_id": identifier}
+	if documentType != "" {
+		reference["_type"] = documentType
+	}
+	return reference
+}
+
+// LikeText adds a text the documents must be similar to
+func (q *MoreLikeThis) LikeText(text string) *MoreLikeThis {
+	q.like = append(q.like, text)
+	return q
+}
+
+// LikeDocument adds an indexed document the documents must be similar to, the document itself being excluded
+// from the results. The document type is only required by Elasticsearch 6 and older.
+func (q *MoreLikeThis) LikeDocument(indexName, documentType, identifier string) *MoreLikeThis {
+	q.like = append(q.like, documentReference(indexName, documentType, identifier))
+	return q
+}
+
+// LikeArtificialDocument adds a document which isn't indexed, such as a draft, analyzed with the mapping of the index
+func (q *MoreLikeThis) LikeArtificialDocument(indexName string, document interface{}) *MoreLikeThis {
+	q.like = append(q.like, map[string]interface{}{"_index": indexName, "doc": document})
+	return q
+}
+
+// UnlikeText adds a text whose terms are not selected to find the similar documents
+func (q *MoreLikeThis) UnlikeText(text string) *MoreLikeThis {
+	q.unlike = append(q.unlike, text)
+	return q
+}
+
+// UnlikeDocument adds an indexed document whose terms are not selected to find the similar documents
+func (q *MoreLikeThis) UnlikeDocument(indexName, documentType, identifier string) *MoreLikeThis {
+	q.unlike = append(q.unlike, documentReference(indexName, documentType, identifier))
+	return q
+}
+
+// MinTermFreq sets the minimum frequency of a term in the liked texts and documents to be selected, 2 by default
+func (q *MoreLikeThis) MinTermFreq(frequency int) *MoreLikeThis {
+	return q.Param("min_term_freq", frequency)
+}
+
+// MaxQueryTerms sets the maximum number of selected terms, 25 by default
+func (q *MoreLikeThis) MaxQueryTerms(terms int) *MoreLikeThis {
+	return q.Param("max_query_terms", terms)
+}
+
+// MinDocFreq sets the minimum number of documents a term must appear in to be selected, 5 by default
+func (q *MoreLikeThis) MinDocFreq(documents int) *MoreLikeThis {
+	return q.Param("min_doc_freq", documents)
+}
+
+// MaxDocFreq sets the maximum number of documents a term can appear in to be selected, ignoring the too frequent terms
+func (q *MoreLikeThis) MaxDocFreq(documents int) *MoreLikeThis {
+	return q.Param("max_doc_freq", documents)
+}
+
+// MinimumShouldMatch sets the number of selected terms a document must match, such as 2 or "30%"
+func (q *MoreLikeThis) MinimumShouldMatch(minimum interface{}) *MoreLikeThis {
+	return q.Param("minimum_should_match", minimum)
+}
+
+// StopWords sets the words ignored when selecting the terms
+func (q *MoreLikeThis) StopWords(words ...string) *MoreLikeThis {
+	return q.Param("stop_words", words)
+}
+
+// Param sets a parameter of the query, such as min_word_length, analyzer, boost_terms, include or boost
+func (q *MoreLikeThis) Param(key string, value interface{}) *MoreLikeThis {
+	q.params[key] = value
+	return q
+}
+
+// MarshalJSON encodes the query as expected in the query of a search request
+func (q *MoreLikeThis) MarshalJSON() ([]byte, error) {
+	body := make(map[string]interface{}, len(q.params)+3)
+	for key, value := range q.params {
+		body[key] = value
+	}
+	if len(q.fields) > 0 {
+		body["fields"] = q.fields
+	}
+	body["like"] = q.like
+	if len(q.unlike) > 0 {
+		body["unlike"] = q.unlike
+	}
+	return json.Marshal(map[string]interface{}{"more_like_this": body})
+}
+
+// Script represents an inline script, or a stored script referenced by its ID
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/modules-scripting-using.html
+type Script struct {
+	Source string                 `json:"source,omitempty"`
+	ID     string                 `json:"id,omitempty"`
+	Lang   string                 `json:"lang,omitempty"` // painless by default
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// FunctionScore represents a function_score query, modifying the score of the documents matching a query
+// with functions such as the popularity of a document or its distance to a location. Built with FunctionScoreQuery.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-function-score-query.html
+type FunctionScore struct {
+	query     interface{}
+	functions []*ScoreFunction
+	params    map[string]interface{}
+}
+
+// FunctionScoreQuery creates a function_score query scoring the documents matching query, a json.RawMessage
+// or a builder, all the documents if nil
+func FunctionScoreQuery(query interface{}, functions ...*ScoreFunction) *FunctionScore {
+	return &FunctionScore{query: query, functions: functions, params: map[string]interface{}{}}
+}
+
+// Add adds a function to the query
+func (q *FunctionScore) Add(function *ScoreFunction) *FunctionScore {
+	q.functions = append(q.functions, function)
+	return q
+}
+
+// ScoreMode sets how the scores of the functions are combined: multiply (default), sum, avg, first, max or min
+func (q *FunctionScore) ScoreMode(mode string) *FunctionScore {
+	return q.Param("score_mode", mode)
+}
+
+// BoostMode sets how the combined score of the functions is combined with the score of the query:
+// multiply (default), replace, sum, avg, max or min
+func (q *FunctionScore) BoostMode(mode string) *FunctionScore {
+	return q.Param("boost_mode", mode)
+}
+
+// MaxBoost caps the combined score of the functions
+func (q *FunctionScore) MaxBoost(boost float64) *FunctionScore {
+	return q.Param("max_boost", boost)
+}
+
+// MinScore excludes the documents scored below score
+func (q *FunctionScore) MinScore(score float64) *FunctionScore {
+	return q.Param("min_score", score)
+}
+
+// Param sets a parameter of the query, such as boost
+func (q *FunctionScore) Param(key string, value interface{}) *FunctionScore {
+	q.params[key] = value
+	return q
+}
+
+// MarshalJSON encodes the query as expected in the query of a search request
+func (q *FunctionScore) MarshalJSON() ([]byte, error) {
+	body := make(map[string]interface{}, len(q.params)+2)
+	for key, value := range q.params {
+		body[key] = value
+	}
+	if q.query != nil {
+		body["query"] = q.query
+	}
+	if len(q.functions) > 0 {
+		body["functions"] = q.functions
+	}
+	return json.Marshal(map[string]interface{}{"function_score": body})
+}
+
+// ScoreFunction represents a function of a function_score query, built with the *Function functions
+type ScoreFunction struct {
+	kind   string // empty for a weight only function
+	params map[string]interface{}
+	field  string // field of a decay function
+	filter interface{}
+	weight *float64
+}
+
+// WeightFunction creates a function multiplying the score by weight, usually applied to the documents
+// matching a filter
+func WeightFunction(weight float64) *ScoreFunction {
+	return (&ScoreFunction{}).Weight(weight)
+}
+
+// FieldValueFactorFunction creates a field_value_factor function scoring with the value of a numeric field,
+// such as the popularity of a document. Its factor, modifier (log1p, sqrt...) and missing value are set with Param.
+func FieldValueFactorFunction(field string) *ScoreFunction {
+	return &ScoreFunction{kind: "field_value_factor", params: map[string]interface{}{"field": field}}
+}
+
+// RandomScoreFunction creates a random_score function, scoring the documents in a random order reproducible
+// with the same seed. The seed is combined with the values of field, _seq_no being advised.
+func RandomScoreFunction(seed int64, field string) *ScoreFunction {
+	return &ScoreFunction{kind: "random_score", params: map[string]interface{}{"seed": seed, "field": field}}
+}
+
+// ScriptScoreFunction creates a script_score function computing the score with a script
+func ScriptScoreFunction(script Script) *ScoreFunction {
+	return &ScoreFunction{kind: "script_score", params: map[string]interface{}{"script": script}}
+}
+
+// DecayFunction creates a decay function (gauss, linear or exp) scoring the documents with the distance of the
+// value of field to origin, the score being 0.5 at scale from origin. The origin and the scale are a number,
+// a date and a duration such as "now" and "10d", or a location and a distance such as "48.85,2.35" and "2km".
+func DecayFunction(decay, field string, origin, scale interface{}) *ScoreFunction {
+	return &ScoreFunction{
+		kind:   decay,
+		field:  field,
+		params: map[string]interface{}{field: map[string]interface{}{"origin": origin, "scale": scale}},
+	}
+}
+
+// Offset sets the distance to the origin of a decay function under which the documents are not penalized
+func (f *ScoreFunction) Offset(offset interface{}) *ScoreFunction {
+	return f.decayParam("offset", offset)
+}
+
+// Decay sets the score of a decay function at scale from the origin, 0.5 by default
+func (f *ScoreFunction) Decay(decay float64) *ScoreFunction {
+	return f.decayParam("decay", decay)
+}
+
+func (f *ScoreFunction) decayParam(key string, value interface{}) *ScoreFunction {
+	if settings, ok := f.params[f.field].(map[string]interface{}); ok && f.field != "" {
+		settings[key] = value
+	}
+	return f
+}
+
+// Filter restricts the function to the documents matching query, a json.RawMessage or a builder
+func (f *ScoreFunction) Filter(query interface{}) *ScoreFunction {
+	f.filter = query
+	return f
+}
+
+// Weight multiplies the score of the function by weight
+func (f *ScoreFunction) Weight(weight float64) *ScoreFunction {
+	f.weight = &weight
+	return f
+}
+
+// Param sets a parameter of the function, such as the factor of field_value_factor or the multi_value_mode of a decay
+func (f *ScoreFunction) Param(key string, value interface{}) *ScoreFunction {
+	if f.params == nil {
+		f.params = map[string]interface{}{}
+	}
+	f.params[key] = value
+	return f
+}
+
+// MarshalJSON encodes the function as expected in the functions of a function_score query
+func (f *ScoreFunction) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{}
+	if f.kind != "" {
+		body[f.kind] = f.params
+	}
+	if f.filter != nil {
+		body["filter"] = f.filter
+	}
+	if f.weight != nil {
+		body["weight"] = *f.weight
+	}
+	return json.Marshal(body)
+}
+
+// ScriptScore represents a script_score query, computing the score of the documents matching a query with
+// a script, such as the similarity of a dense_vector field. Built with ScriptScoreQuery.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-script-score-query.html
+type ScriptScore struct {
+	query  interface{}
+	script Script
+	params map[string]interface{}
+}
+
+// ScriptScoreQuery creates a script_score query scoring the documents matching query, a json.RawMessage
+// or a builder, all the documents if nil
+func ScriptScoreQuery(query interface{}, script Script) *ScriptScore {
+	return &ScriptScore{query: query, script: script, params: map[string]interface{}{}}
+}
+
+// MinScore excludes the documents scored below score
+func (q *ScriptScore) MinScore(score float64) *ScriptScore {
+	return q.Param("min_score", score)
+}
+
+// Param sets a parameter of the query, such as boost
+func (q *ScriptScore) Param(key string, value interface{}) *ScriptScore {
+	q.params[key] = value
+	return q
+}
+
+// MarshalJSON encodes the query as expected in the query of a search request
+func (q *ScriptScore) MarshalJSON() ([]byte, error) {
+	body := make(map[string]interface{}, len(q.params)+2)
+	for key, value := range q.params {
+		body[key] = value
+	}
+	body["query"] = q.query
+	if q.query == nil {
+		body["query"] = map[string]interface{}{"match_all": struct{}{}}
+	}
+	body["script"] = q.script
+	return json.Marshal(map[string]interface{}{"script_score": body})
+}
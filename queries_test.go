@@ -0,0 +1,66 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestMoreLikeThisQuery(t *testing.T) {
+	helper := Test{}
+	query := elasticsearch.MoreLikeThisQuery("title", "body").
+		LikeDocument("articles", "", "42").
+		LikeDocument("articles", "article", "43").
+		LikeText("generics in go").
+		UnlikeText("java").
+		MinTermFreq(1).
+		MaxQueryTerms(12).
+		MinimumShouldMatch("30%")
+
+	body, err := json.Marshal(elasticsearch.SearchRequest{Query: query})
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"more_like_this":{"fields":["title","body"],"like":[{"_id":"42","_index":"articles"},{"_id":"43","_index":"articles","_type":"article"},"generics in go"],"max_query_terms":12,"min_term_freq":1,"minimum_should_match":"30%","unlike":["java"]}}}`, string(body))
+
+	body, err = json.Marshal(elasticsearch.MoreLikeThisQuery().LikeArtificialDocument("articles", map[string]string{"title": "draft"}))
+	helper.OK(t, err)
+	helper.Equals(t, `{"more_like_this":{"like":[{"_index":"articles","doc":{"title":"draft"}}]}}`, string(body))
+
+	// Raw queries are still accepted
+	body, err = json.Marshal(elasticsearch.SearchRequest{Query: json.RawMessage(`{"match_all":{}}`)})
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"match_all":{}}}`, string(body))
+}
+
+func TestFunctionScoreQuery(t *testing.T) {
+	helper := Test{}
+	query := elasticsearch.FunctionScoreQuery(json.RawMessage(`{"match":{"name":"shirt"}}`),
+		elasticsearch.FieldValueFactorFunction("popularity").Param("modifier", "log1p").Param("factor", 1.2),
+		elasticsearch.DecayFunction("gauss", "created", "now", "10d").Offset("1d").Decay(0.3),
+		elasticsearch.WeightFunction(2).Filter(json.RawMessage(`{"term":{"featured":true}}`)),
+	).Add(elasticsearch.RandomScoreFunction(42, "_seq_no")).ScoreMode("sum").BoostMode("multiply").MaxBoost(10)
+
+	body, err := json.Marshal(query)
+	helper.OK(t, err)
+	helper.Equals(t, `{"function_score":{"boost_mode":"multiply","functions":[`+
+		`{"field_value_factor":{"factor":1.2,"field":"popularity","modifier":"log1p"}},`+
+		`{"gauss":{"created":{"decay":0.3,"offset":"1d","origin":"now","scale":"10d"}}},`+
+		`{"filter":{"term":{"featured":true}},"weight":2},`+
+		`{"random_score":{"field":"_seq_no","seed":42}}],`+
+		`"max_boost":10,"query":{"match":{"name":"shirt"}},"score_mode":"sum"}}`, string(body))
+
+	body, err = json.Marshal(elasticsearch.FunctionScoreQuery(nil, elasticsearch.ScriptScoreFunction(elasticsearch.Script{Source: "Math.log(2 + doc['likes'].value)"})))
+	helper.OK(t, err)
+	helper.Equals(t, `{"function_score":{"functions":[{"script_score":{"script":{"source":"Math.log(2 + doc['likes'].value)"}}}]}}`, string(body))
+}
+
+func TestScriptScoreQuery(t *testing.T) {
+	helper := Test{}
+	script := elasticsearch.Script{
+		Source: "cosineSimilarity(params.vector, 'embedding') + 1.0",
+		Params: map[string]interface{}{"vector": []float64{0.5, 1}},
+	}
+	body, err := json.Marshal(elasticsearch.SearchRequest{Query: elasticsearch.ScriptScoreQuery(nil, script).MinScore(1.5)})
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"script_score":{"min_score":1.5,"query":{"match_all":{}},"script":{"source":"cosineSimilarity(params.vector, 'embedding') + 1.0","params":{"vector":[0.5,1]}}}}}`, string(body))
+}
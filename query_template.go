@@ -0,0 +1,141 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// QueryTemplate is a query JSON containing named placeholders such as {{color}}.
+// A placeholder inside a JSON string is replaced by the escaped text of the value,
+// anywhere else it's replaced by the JSON encoding of the value.
+//
+//	{"query": {"match": {"Colors": "{{color}}"}}, "size": {{size}}}
+type QueryTemplate struct {
+	parts  []templatePart
+	params []string
+}
+
+type templatePart struct {
+	text     string
+	param    string
+	inString bool
+}
+
+// NewQueryTemplate parses a query template
+func NewQueryTemplate(source string) (*QueryTemplate, error) {
+	t := &QueryTemplate{}
+	seen := map[string]bool{}
+	inString := false
+	start := 0
+
+	for i := 0; i < len(source); i++ {
+		switch {
+		case source[i] == '\\' && inString:
+			i++
+		case source[i] == '"':
+			inString = !inString
+		case strings.HasPrefix(source[i:], "{{"):
+			end := strings.Index(source[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed placeholder at offset %d", i)
+			}
+			name := strings.TrimSpace(source[i+2 : i+end])
+			if name == "" {
+				return nil, fmt.Errorf("empty placeholder at offset %d", i)
+			}
+
+			t.parts = append(t.parts, templatePart{text: source[start:i]})
+			t.parts = append(t.parts, templatePart{param: name, inString: inString})
+			if !seen[name] {
+				seen[name] = true
+				t.params = append(t.params, name)
+			}
+			i += end + 1
+			start = i + 1
+		}
+	}
+	t.parts = append(t.parts, templatePart{text: source[start:]})
+
+	return t, nil
+}
+
+// MustQueryTemplate is like NewQueryTemplate but panics if the template cannot be parsed.
+// It simplifies the initialization of global variables holding templates.
+func MustQueryTemplate(source string) *QueryTemplate {
+	t, err := NewQueryTemplate(source)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadQueryTemplate reads and parses a query template from a file
+func LoadQueryTemplate(path string) (*QueryTemplate, error) {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewQueryTemplate(string(source))
+}
+
+// Params returns the names of the placeholders, in order of appearance
+func (t *QueryTemplate) Params() []string {
+	return append([]string{}, t.params...)
+}
+
+// Render substitutes the placeholders with params. Every placeholder is required,
+// and the rendered query must be valid JSON.
+func (t *QueryTemplate) Render(params map[string]interface{}) (string, error) {
+	missing := []string{}
+	for _, name := range t.params {
+		if _, found := params[name]; !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", errors.New("missing template parameters: " + strings.Join(missing, ", "))
+	}
+
+	var builder strings.Builder
+	for _, part := range t.parts {
+		if part.param == "" {
+			builder.WriteString(part.text)
+			continue
+		}
+
+		value, err := renderParam(params[part.param], part.inString)
+		if err != nil {
+			return "", fmt.Errorf("template parameter %s: %v", part.param, err)
+		}
+		builder.WriteString(value)
+	}
+
+	query := builder.String()
+	if !json.Valid([]byte(query)) {
+		return "", errors.New("rendered template is not valid JSON: " + query)
+	}
+	return query, nil
+}
+
+func renderParam(value interface{}, inString bool) (string, error) {
+	if !inString {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	encoded, err := json.Marshal(text)
+	if err != nil {
+		return "", err
+	}
+	// Strip the quotes, the placeholder is already inside a string
+	return string(encoded[1 : len(encoded)-1]), nil
+}
@@ -0,0 +1,69 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestQueryTemplate(t *testing.T) {
+	helper := Test{}
+	template, err := elasticsearch.NewQueryTemplate(`{"query": {"match": {"Colors": "{{color}}"}}, "size": {{ size }}, "_source": {{fields}}}`)
+	helper.OK(t, err)
+	helper.Equals(t, []string{"color", "size", "fields"}, template.Params())
+
+	query, err := template.Render(map[string]interface{}{
+		"color":  `red" OR "blue`,
+		"size":   10,
+		"fields": []string{"Name"},
+	})
+	helper.OK(t, err)
+	helper.Equals(t, `{"query": {"match": {"Colors": "red\" OR \"blue"}}, "size": 10, "_source": ["Name"]}`, query)
+
+	_, err = template.Render(map[string]interface{}{"color": "red"})
+	helper.Assert(t, err != nil, "Missing parameters must be rejected")
+	helper.Equals(t, "missing template parameters: fields, size", err.Error())
+}
+
+func TestSearchTemplateStorage(t *testing.T) {
+	helper := Test{}
+	for version, routes := range map[string][]string{
+		"5.4.3":  {`POST /_search/template/colors {"template":{"query":{"match":{"Colors":"{{color}}"}}}}`, "GET /_search/template/colors ", "DELETE /_search/template/colors "},
+		"7.17.0": {`PUT /_scripts/colors {"script":{"lang":"mustache","source":{"query":{"match":{"Colors":"{{color}}"}}}}}`, "GET /_scripts/colors ", "DELETE /_scripts/colors "},
+	} {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+			if r.URL.Path == "/" {
+				w.Write([]byte(`{"version":{"number":"` + version + `"},"tagline":"You Know, for Search"}`))
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+			switch {
+			case r.Method != "GET":
+				w.Write([]byte(`{"acknowledged":true}`))
+			case r.URL.Path == "/_search/template/colors":
+				w.Write([]byte(`{"_id":"colors","lang":"mustache","found":true,"template":"{\"query\":{\"match\":{\"Colors\":\"{{color}}\"}}}"}`))
+			default:
+				w.Write([]byte(`{"_id":"colors","found":true,"script":{"lang":"mustache","source":"{\"query\":{\"match\":{\"Colors\":\"{{color}}\"}}}"}}`))
+			}
+		}))
+
+		client := elasticsearch.NewClientFromUrl(server.URL)
+		_, err := client.CreateSearchTemplate("colors", `{"query":{"match":{"Colors":"{{color}}"}}}`)
+		helper.OK(t, err)
+		template, err := client.GetSearchTemplate("colors")
+		helper.OK(t, err)
+		helper.Assert(t, template.Found, "The template is expected to be found on "+version)
+		helper.Equals(t, "mustache", template.Script.Lang)
+		helper.Equals(t, `{"query":{"match":{"Colors":"{{color}}"}}}`, template.Script.Source)
+		_, err = client.DeleteSearchTemplate("colors")
+		helper.OK(t, err)
+		helper.Equals(t, routes, requests)
+		server.Close()
+	}
+}
@@ -0,0 +1,157 @@
+package elasticsearch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of the requests sent to Elasticsearch with token buckets, overall and
+// per endpoint, so that a batch job can't overwhelm a shared cluster. A request waits until a token of
+// every bucket it's subject to is available.
+//
+//	limiter := elasticsearch.NewRateLimiter(200, 50).Limit("POST /{index}/_bulk", 5, 1)
+//	client := elasticsearch.NewClientFromUrl(rawurl, elasticsearch.WithRateLimiter(limiter))
+type RateLimiter struct {
+	mutex     sync.Mutex
+	overall   *tokenBucket
+	endpoints map[string]*tokenBucket
+	stats     RateLimiterStats
+}
+
+// RateLimiterStats represents the waits of the requests delayed by a RateLimiter
+type RateLimiterStats struct {
+	Requests  int64 // requests sent through the limiter
+	Delayed   int64 // requests which waited for a token
+	Waiting   int64 // requests currently waiting
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// AverageWait returns the average wait of the delayed requests
+func (s RateLimiterStats) AverageWait() time.Duration {
+	if s.Delayed == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Delayed)
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second overall, with bursts of burst
+// requests. A rate of 0 or less doesn't limit the overall rate, the endpoints being limited with Limit.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	limiter := &RateLimiter{endpoints: map[string]*tokenBucket{}}
+	if rate > 0 {
+		limiter.overall = newTokenBucket(rate, burst)
+	}
+	return limiter
+}
+
+// Limit allows rate requests per second to the endpoint, with bursts of burst requests. The endpoint
+// is named as in RequestMetrics, such as "POST /_bulk" or "POST /{index}/_bulk".
+// A rate of 0 or less doesn't limit the endpoint, as in NewRateLimiter.
+func (l *RateLimiter) Limit(endpoint string, rate float64, burst int) *RateLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if rate <= 0 {
+		delete(l.endpoints, endpoint)
+		return l
+	}
+	l.endpoints[endpoint] = newTokenBucket(rate, burst)
+	return l
+}
+
+// WithRateLimiter delays the requests exceeding the rates of limiter, the retries included
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Stats returns the current wait statistics
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.stats
+}
+
+// Wait blocks until a request to the endpoint is allowed, or the context is done
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	l.mutex.Lock()
+	now := time.Now()
+	var wait time.Duration
+	buckets := []*tokenBucket{l.overall, l.endpoints[endpoint]}
+	for _, bucket := range buckets {
+		if bucket != nil {
+			if delay := bucket.reserve(now); delay > wait {
+				wait = delay
+			}
+		}
+	}
+	l.stats.Requests++
+	if wait == 0 {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.stats.Delayed++
+	l.stats.Waiting++
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.stats.Waiting--
+	if err != nil {
+		// The request isn't sent, its tokens are given back
+		for _, bucket := range buckets {
+			if bucket != nil {
+				bucket.tokens++
+			}
+		}
+		return err
+	}
+	l.stats.TotalWait += wait
+	if wait > l.stats.MaxWait {
+		l.stats.MaxWait = wait
+	}
+	return nil
+}
+
+// tokenBucket is refilled with rate tokens per second, up to burst tokens. The tokens are reserved
+// ahead, a negative count being the tokens owed to the requests waiting.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token, returning the time to wait for it to be available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,60 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestRateLimiter(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("POST", "/orders/_bulk", http.StatusOK, `{"took":1,"errors":false,"items":[]}`)
+	limiter := elasticsearch.NewRateLimiter(0, 0).Limit("POST /{index}/_bulk", 20, 1)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithRateLimiter(limiter))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Bulk("orders", []byte(`{"delete":{"_id":"1"}}`+"\n"))
+		helper.OK(t, err)
+	}
+	elapsed := time.Since(start)
+	helper.Assert(t, elapsed >= 90*time.Millisecond, "The bulk requests are expected to be spaced by 50ms, sent in "+elapsed.String())
+
+	// The other endpoints aren't limited
+	for i := 0; i < 3; i++ {
+		_, err := client.DeleteIndex("orders")
+		helper.OK(t, err)
+	}
+
+	stats := limiter.Stats()
+	helper.Equals(t, int64(6), stats.Requests)
+	helper.Equals(t, int64(2), stats.Delayed)
+	helper.Equals(t, int64(0), stats.Waiting)
+	helper.Assert(t, stats.MaxWait > 40*time.Millisecond && stats.MaxWait <= 100*time.Millisecond, "The maximum wait is expected to be about 50ms, got "+stats.MaxWait.String())
+	helper.Assert(t, stats.AverageWait() <= stats.MaxWait, "The average wait can't exceed the maximum wait")
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	helper := Test{}
+	limiter := elasticsearch.NewRateLimiter(1, 1)
+	helper.OK(t, limiter.Wait(context.Background(), "GET /_cluster/health"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	helper.Equals(t, context.DeadlineExceeded, limiter.Wait(ctx, "GET /_cluster/health"))
+	helper.Equals(t, int64(0), limiter.Stats().Waiting)
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	helper := Test{}
+	limiter := elasticsearch.NewRateLimiter(-1, 1).Limit("POST /_bulk", 0, 1).Limit("GET /_search", 1, 1).Limit("GET /_search", -5, 1)
+	for i := 0; i < 10; i++ {
+		helper.OK(t, limiter.Wait(context.Background(), "POST /_bulk"))
+		helper.OK(t, limiter.Wait(context.Background(), "GET /_search"))
+	}
+	helper.Equals(t, int64(0), limiter.Stats().Delayed)
+}
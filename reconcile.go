@@ -0,0 +1,330 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReconcileMode defines what a Reconciler does when the cluster drifts from the desired state
+type ReconcileMode int
+
+const (
+	// ReportDrift only reports the differences found on the cluster
+	ReportDrift ReconcileMode = iota
+	// ApplyDesired re-applies the desired state when a difference is found
+	ApplyDesired
+)
+
+// Drift kinds reported by a Reconciler
+const (
+	DriftTemplate = "template"
+	DriftMapping  = "mapping"
+	DriftAlias    = "alias"
+)
+
+// DesiredState declares the templates, mappings and aliases expected on the cluster
+type DesiredState struct {
+	Templates map[string]string   // template name -> template body
+	Mappings  map[string]string   // index name -> mapping body
+	Aliases   map[string][]string // alias name -> indices the alias points to
+}
+
+// Drift represents a difference between the desired state and the cluster
+type Drift struct {
+	Kind    string
+	Name    string
+	Reason  string
+	Applied bool
+	Error   error
+}
+
+// Reconciler compares a desired state against the cluster, and re-applies it depending on the mode
+type Reconciler struct {
+	Client   Client
+	Desired  DesiredState
+	Mode     ReconcileMode
+	Interval time.Duration
+
+	// OnDrift is called by Run after each reconciliation which found differences
+	OnDrift func([]Drift)
+	// OnError is called by Run when the cluster state cannot be read
+	OnError func(error)
+}
+
+// NewReconciler creates a Reconciler for the given client and desired state
+func NewReconciler(client Client, desired DesiredState, mode ReconcileMode, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		Client:   client,
+		Desired:  desired,
+		Mode:     mode,
+		Interval: interval,
+	}
+}
+
+// Run reconciles the cluster every Interval until the context is done.
+// The Interval must be positive, Reconcile runs a single reconciliation.
+func (r *Reconciler) Run(ctx context.Context) error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("invalid reconcile interval %s, it must be positive", r.Interval)
+	}
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		drifts, err := r.Reconcile()
+		if err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+		if len(drifts) > 0 && r.OnDrift != nil {
+			r.OnDrift(drifts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile compares the desired state against the cluster once.
+// In ApplyDesired mode, every drift is re-applied and the outcome is stored in the Drift.
+func (r *Reconciler) Reconcile() ([]Drift, error) {
+	drifts := []Drift{}
+
+	for _, name := range sortedKeys(r.Desired.Templates) {
+		drift, err := r.reconcileTemplate(name, r.Desired.Templates[name])
+		if err != nil {
+			return drifts, err
+		}
+		if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	for _, name := range sortedKeys(r.Desired.Mappings) {
+		drift, err := r.reconcileMapping(name, r.Desired.Mappings[name])
+		if err != nil {
+			return drifts, err
+		}
+		if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	aliases := make([]string, 0, len(r.Desired.Aliases))
+	for alias := range r.Desired.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		drift, err := r.reconcileAlias(alias, r.Desired.Aliases[alias])
+		if err != nil {
+			return drifts, err
+		}
+		if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	return drifts, nil
+}
+
+func (r *Reconciler) reconcileTemplate(name, desired string) (*Drift, error) {
+	response, err := r.Client.IndexTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates map[string]json.RawMessage
+	if err := codecOf(r.Client).Unmarshal(response, &templates); err != nil {
+		return nil, err
+	}
+
+	drift := &Drift{Kind: DriftTemplate, Name: name}
+	actual, found := templates[name]
+	if !found {
+		drift.Reason = "template is missing"
+	} else {
+		reason, err := jsonDiff(actual, []byte(desired))
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			return nil, nil
+		}
+		drift.Reason = reason
+	}
+
+	if r.Mode == ApplyDesired {
+		_, drift.Error = r.Client.PutIndexTemplate(name, desired)
+		drift.Applied = drift.Error == nil
+	}
+	return drift, nil
+}
+
+func (r *Reconciler) reconcileMapping(indexName, desired string) (*Drift, error) {
+	drift := &Drift{Kind: DriftMapping, Name: indexName}
+	// A missing index answers the mapping request with a 404 error document
+	exists, err := r.Client.IndexExists(indexName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		drift.Reason = "index is missing"
+		if r.Mode == ApplyDesired {
+			_, drift.Error = r.Client.CreateIndex(indexName, `{"mappings":`+desired+`}`)
+			drift.Applied = drift.Error == nil
+		}
+		return drift, nil
+	}
+
+	response, err := r.Client.GetMapping(indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	var mappings map[string]struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	if err := codecOf(r.Client).Unmarshal(response, &mappings); err != nil {
+		return nil, err
+	}
+
+	actual, found := mappings[namespaceOf(r.Client, indexName)]
+	if !found || actual.Mappings == nil {
+		return nil, fmt.Errorf("mapping of %s not found in the response, an alias cannot be reconciled", indexName)
+	}
+
+	reason, err := jsonDiff(actual.Mappings, []byte(desired))
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, nil
+	}
+	drift.Reason = reason
+
+	if r.Mode == ApplyDesired {
+		_, drift.Error = r.Client.PutMapping(indexName, desired)
+		drift.Applied = drift.Error == nil
+	}
+	return drift, nil
+}
+
+func (r *Reconciler) reconcileAlias(alias string, desired []string) (*Drift, error) {
+	actual, err := r.Client.GetIndicesFromAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	// The cluster answers with the namespaced names of the indices
+	namespaced := make([]string, len(desired))
+	for i, index := range desired {
+		namespaced[i] = namespaceOf(r.Client, index)
+	}
+	desired = namespaced
+
+	remove := difference(actual, desired)
+	add := difference(desired, actual)
+	if len(remove) == 0 && len(add) == 0 {
+		return nil, nil
+	}
+
+	drift := &Drift{Kind: DriftAlias, Name: alias}
+	reasons := []string{}
+	if len(add) > 0 {
+		reasons = append(reasons, "missing indices "+strings.Join(add, ","))
+	}
+	if len(remove) > 0 {
+		reasons = append(reasons, "unexpected indices "+strings.Join(remove, ","))
+	}
+	drift.Reason = strings.Join(reasons, ", ")
+
+	if r.Mode == ApplyDesired {
+		_, drift.Error = r.Client.UpdateAlias(remove, add, alias)
+		drift.Applied = drift.Error == nil
+	}
+	return drift, nil
+}
+
+// jsonDiff returns a description of the first desired value which is missing or different in actual.
+// Only the keys declared in desired are compared, so defaults added by the cluster are not a drift.
+func jsonDiff(actual, desired []byte) (string, error) {
+	var a, d interface{}
+	if err := json.Unmarshal(actual, &a); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(desired, &d); err != nil {
+		return "", err
+	}
+
+	actualValues := map[string]string{}
+	flatten("", a, actualValues)
+	desiredValues := map[string]string{}
+	flatten("", d, desiredValues)
+
+	for _, key := range sortedKeys(desiredValues) {
+		value, found := actualValues[key]
+		if !found {
+			// Settings are returned by the cluster under the "index" namespace
+			value, found = actualValues[strings.Replace(key, "settings.", "settings.index.", 1)]
+		}
+		if !found {
+			return key + " is missing", nil
+		}
+		if value != desiredValues[key] {
+			return fmt.Sprintf("%s is %s instead of %s", key, value, desiredValues[key]), nil
+		}
+	}
+	return "", nil
+}
+
+// flatten stores every leaf value of v under its dotted path. Scalars are compared as strings
+// because the cluster returns settings such as number_of_shards as strings.
+func flatten(prefix string, v interface{}, values map[string]string) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, child, values)
+		}
+	case []interface{}:
+		encoded, _ := json.Marshal(value)
+		values[prefix] = string(encoded)
+	default:
+		values[prefix] = fmt.Sprint(value)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func difference(a, b []string) []string {
+	diff := []string{}
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, x)
+		}
+	}
+	return diff
+}
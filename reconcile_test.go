@@ -0,0 +1,150 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestReconcile(t *testing.T) {
+	helper := Test{}
+	applied := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" || r.Method == "POST" {
+			body, _ := ioutil.ReadAll(r.Body)
+			applied[r.URL.Path] = string(body)
+			w.Write([]byte(`{"acknowledged":true}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/products":
+		case "/_template/logs":
+			w.Write([]byte(`{"logs":{"order":0,"index_patterns":["logs-*"],"settings":{"index":{"number_of_shards":"1"}},"mappings":{}}}`))
+		case "/products/_mapping":
+			w.Write([]byte(`{"products":{"mappings":{"properties":{"name":{"type":"text"}}}}}`))
+		case "/*/_alias/current":
+			w.Write([]byte(`{"products-v1":{"aliases":{"current":{}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	desired := elasticsearch.DesiredState{
+		Templates: map[string]string{"logs": `{"index_patterns":["logs-*"],"settings":{"number_of_shards":1}}`},
+		Mappings:  map[string]string{"products": `{"properties":{"name":{"type":"keyword"}}}`},
+		Aliases:   map[string][]string{"current": {"products-v2"}},
+	}
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	reconciler := elasticsearch.NewReconciler(client, desired, elasticsearch.ReportDrift, 0)
+	drifts, err := reconciler.Reconcile()
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(drifts))
+	helper.Equals(t, elasticsearch.DriftMapping, drifts[0].Kind)
+	helper.Equals(t, "properties.name.type is text instead of keyword", drifts[0].Reason)
+	helper.Equals(t, elasticsearch.DriftAlias, drifts[1].Kind)
+	helper.Assert(t, len(applied) == 0, "Report mode must not change the cluster")
+
+	reconciler.Mode = elasticsearch.ApplyDesired
+	drifts, err = reconciler.Reconcile()
+	helper.OK(t, err)
+	helper.Assert(t, drifts[0].Applied && drifts[1].Applied, "Drifts have not been applied")
+	helper.Equals(t, desired.Mappings["products"], applied["/products/_mapping"])
+	helper.Assert(t, applied["/_aliases"] != "", "Alias has not been updated")
+}
+
+func TestReconcileNamespaced(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/staging-products":
+		case "/staging-products/_mapping":
+			w.Write([]byte(`{"staging-products":{"mappings":{"properties":{"name":{"type":"keyword"}}}}}`))
+		case "/*/_alias/staging-current":
+			w.Write([]byte(`{"staging-products-v2":{"aliases":{"staging-current":{}}}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	desired := elasticsearch.DesiredState{
+		Mappings: map[string]string{"products": `{"properties":{"name":{"type":"keyword"}}}`},
+		Aliases:  map[string][]string{"current": {"products-v2"}},
+	}
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-"))
+	reconciler := elasticsearch.NewReconciler(client, desired, elasticsearch.ApplyDesired, 0)
+	drifts, err := reconciler.Reconcile()
+	helper.OK(t, err)
+	helper.Equals(t, 0, len(drifts))
+}
+
+func TestReconcileMissingIndex(t *testing.T) {
+	helper := Test{}
+	created := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && r.URL.Path == "/products" {
+			body, _ := ioutil.ReadAll(r.Body)
+			created = string(body)
+			w.Write([]byte(`{"acknowledged":true,"shards_acknowledged":true,"index":"products"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method != "HEAD" {
+			w.Write([]byte(`{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [products]","index":"products"}],` +
+				`"type":"index_not_found_exception","reason":"no such index [products]","index":"products"},"status":404}`))
+		}
+	}))
+	defer server.Close()
+
+	desired := elasticsearch.DesiredState{Mappings: map[string]string{"products": `{"properties":{"name":{"type":"keyword"}}}`}}
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	reconciler := elasticsearch.NewReconciler(client, desired, elasticsearch.ApplyDesired, 0)
+	drifts, err := reconciler.Reconcile()
+	helper.OK(t, err)
+	helper.Equals(t, 1, len(drifts))
+	helper.Equals(t, "index is missing", drifts[0].Reason)
+	helper.Assert(t, drifts[0].Applied, "The index is expected to be created, got %v", drifts[0].Error)
+	helper.Equals(t, `{"mappings":{"properties":{"name":{"type":"keyword"}}}}`, created)
+}
+
+func TestReconcileInvalidInterval(t *testing.T) {
+	helper := Test{}
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200")
+	reconciler := elasticsearch.NewReconciler(client, elasticsearch.DesiredState{}, elasticsearch.ReportDrift, 0)
+	err := reconciler.Run(context.Background())
+	helper.Assert(t, err != nil, "A zero interval is expected to be rejected")
+}
+
+func TestReconcileCodec(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_template/logs":
+			w.Write([]byte(`{"logs":{"order":0,"index_patterns":["logs-*"]}}`))
+		case "/products":
+		case "/products/_mapping":
+			w.Write([]byte(`{"products":{"mappings":{"properties":{"name":{"type":"keyword"}}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	desired := elasticsearch.DesiredState{
+		Templates: map[string]string{"logs": `{"index_patterns":["logs-*"]}`},
+		Mappings:  map[string]string{"products": `{"properties":{"name":{"type":"keyword"}}}`},
+	}
+	codec := &countingCodec{}
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithCodec(codec))
+	drifts, err := elasticsearch.NewReconciler(client, desired, elasticsearch.ReportDrift, 0).Reconcile()
+	helper.OK(t, err)
+	helper.Equals(t, 0, len(drifts))
+	helper.Equals(t, 2, codec.unmarshals)
+}
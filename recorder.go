@@ -0,0 +1,113 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RecordedRequest represents a request captured by a RequestRecorder
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Body   string // decompressed when the request is gzipped
+}
+
+// String returns the request as it would be written in the Kibana console, such as "DELETE /logs-2019"
+// followed by the body if any
+func (r RecordedRequest) String() string {
+	line := r.Method + " " + r.URL
+	if r.Body != "" {
+		line += "\n" + r.Body
+	}
+	return line
+}
+
+// RequestRecorder is a transport capturing the requests instead of sending them, for dry runs of
+// maintenance scripts and for assertions in tests. Every request is answered with 200 and an empty
+// JSON object, unless a canned response has been registered with Respond.
+type RequestRecorder struct {
+	mutex     sync.Mutex
+	requests  []RecordedRequest
+	responses map[string]cannedResponse
+}
+
+type cannedResponse struct {
+	status int
+	body   string
+}
+
+// NewRequestRecorder creates a recorder without canned responses
+func NewRequestRecorder() *RequestRecorder {
+	return &RequestRecorder{responses: map[string]cannedResponse{}}
+}
+
+// WithDryRun captures the requests with recorder instead of sending them to Elasticsearch
+func WithDryRun(recorder *RequestRecorder) ClientOption {
+	return WithTransport(recorder)
+}
+
+// Respond answers the requests with the method and the path, such as "GET" and "/_cluster/health",
+// with status and body. An empty method matches every method.
+func (r *RequestRecorder) Respond(method, path string, status int, body string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.responses[method+" "+path] = cannedResponse{status: status, body: body}
+}
+
+// Requests returns the captured requests, in the order they were sent
+func (r *RequestRecorder) Requests() []RecordedRequest {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// Reset forgets the captured requests, the canned responses being kept
+func (r *RequestRecorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = nil
+}
+
+// RoundTrip captures the request and returns its canned response
+func (r *RequestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := recordedBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	r.requests = append(r.requests, RecordedRequest{Method: req.Method, URL: req.URL.RequestURI(), Body: body})
+	response, found := r.responses[req.Method+" "+req.URL.Path]
+	if !found {
+		response, found = r.responses[" "+req.URL.Path]
+	}
+	r.mutex.Unlock()
+	if !found {
+		response = cannedResponse{status: http.StatusOK, body: "{}"}
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(response.status) + " " + http.StatusText(response.status),
+		StatusCode:    response.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewBufferString(response.body)),
+		ContentLength: int64(len(response.body)),
+		Request:       req,
+	}, nil
+}
+
+// recordedBody reads the body of the request, decompressing it if needed
+func recordedBody(req *http.Request) (string, error) {
+	raw, err := readRequestBody(req)
+	if err != nil {
+		return "", err
+	}
+	body, err := decodeBody(raw, req.Header.Get("Content-Encoding"))
+	return string(body), err
+}
@@ -0,0 +1,51 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestDryRun(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	recorder.Respond("GET", "/_alias/logs", http.StatusOK, `{"logs-2019":{"aliases":{"logs":{}}}}`)
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder), elasticsearch.WithRequestCompression(0))
+
+	aliases, err := client.GetAlias("logs")
+	helper.OK(t, err)
+	for index := range aliases {
+		_, err := client.DeleteIndex(index)
+		helper.OK(t, err)
+	}
+	_, err = client.UpdateAlias(nil, []string{"logs-2020"}, "logs")
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, 3, len(requests))
+	helper.Equals(t, "DELETE /logs-2019", requests[1].String())
+	helper.Equals(t, "POST /_aliases\n"+`{"actions":[{"add":{"index":"logs-2020","alias":"logs"}}]}`, requests[2].String())
+
+	recorder.Reset()
+	helper.Equals(t, 0, len(recorder.Requests()))
+}
+
+func TestBulkIndexTarget(t *testing.T) {
+	helper := Test{}
+	payload := []byte(`{"index":{"_id":"1"}}` + "\n" + `{"name":"shoe"}` + "\n")
+	for version, path := range map[string]string{"6.8.0": "/products/product/_bulk", "8.6.0": "/products/_bulk"} {
+		recorder := elasticsearch.NewRequestRecorder()
+		recorder.Respond("GET", "/", http.StatusOK, `{"version":{"number":"`+version+`"},"tagline":"You Know, for Search"}`)
+		client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+		_, err := client.BulkIndexTarget("products", "product", payload)
+		helper.OK(t, err)
+		requests := recorder.Requests()
+		helper.Equals(t, "POST "+path, requests[len(requests)-1].Method+" "+requests[len(requests)-1].URL)
+		helper.Equals(t, string(payload), requests[len(requests)-1].Body)
+	}
+
+	_, err := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(elasticsearch.NewRequestRecorder())).BulkIndexTarget("", "product", payload)
+	helper.Assert(t, err != nil, "A bulk request without target index is expected to be rejected")
+}
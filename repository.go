@@ -0,0 +1,203 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrDocumentNotFound is returned by a Repository when the requested document doesn't exist
+var ErrDocumentNotFound = errors.New("document not found")
+
+// Repository stores documents of type T in an index. The identifier of a document is the field
+// tagged with the "id" option of the es tag, a string or an integer:
+//
+//	type Product struct {
+//		ID   string `json:"id" es:"id,type:keyword"`
+//		Name string `json:"name"`
+//	}
+//
+//	products, err := elasticsearch.NewRepository[Product](client, "products")
+//	stored, err := products.Get("42")
+//	stored.Document.Name = "shirt"
+//	_, err = products.Update(stored) // fails with a ConflictError if the document has changed since
+type Repository[T any] struct {
+	client Client
+	index  string
+	codec  Codec
+	id     []int // index path of the identifier field
+}
+
+// Stored represents a document of a Repository with the metadata required by optimistic concurrency
+type Stored[T any] struct {
+	ID          string
+	Version     int
+	SeqNo       int64
+	PrimaryTerm int64
+	Document    T
+}
+
+// NewRepository creates a repository of the documents of type T stored in the index
+func NewRepository[T any](client Client, indexName string) (*Repository[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot create a repository of %v, a struct is expected", t)
+	}
+
+	id, err := idFieldPath(t)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository[T]{client: client, index: indexName, codec: codecOf(client), id: id}, nil
+}
+
+// idFieldPath finds the field tagged as identifier, in the struct or its embedded structs
+func idFieldPath(t reflect.Type) ([]int, error) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		for _, option := range strings.Split(field.Tag.Get("es"), ",") {
+			if option != idTagOption {
+				continue
+			}
+			switch field.Type.Kind() {
+			case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+				return []int{i}, nil
+			}
+			return nil, fmt.Errorf("identifier field %s must be a string or an integer", field.Name)
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if path, err := idFieldPath(field.Type); err == nil {
+				return append([]int{i}, path...), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%v has no field tagged with es:\"id\"", t)
+}
+
+// ID returns the identifier of a document
+func (r *Repository[T]) ID(document T) string {
+	value := reflect.ValueOf(document).FieldByIndex(r.id)
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	}
+	return strconv.FormatInt(value.Int(), 10)
+}
+
+// Save indexes the document, replacing the existing one with the same identifier if any
+func (r *Repository[T]) Save(document T, opts ...RequestOption) (*Stored[T], error) {
+	return r.write(r.client.InsertDocument, document, opts)
+}
+
+// Create indexes the document, failing with a ConflictError if its identifier already exists
+func (r *Repository[T]) Create(document T, opts ...RequestOption) (*Stored[T], error) {
+	return r.write(r.client.CreateDocument, document, opts)
+}
+
+// Update replaces a document read with Get, failing with a ConflictError if it has been changed since
+func (r *Repository[T]) Update(stored *Stored[T], opts ...RequestOption) (*Stored[T], error) {
+	opts = append([]RequestOption{WithIfSeqNo(stored.SeqNo, stored.PrimaryTerm)}, opts...)
+	return r.write(r.client.InsertDocument, stored.Document, opts)
+}
+
+func (r *Repository[T]) write(insert func(string, string, string, []byte, ...RequestOption) (*InsertDocument, error), document T, opts []RequestOption) (*Stored[T], error) {
+	id := r.ID(document)
+	if id == "" {
+		return nil, errors.New("the identifier of the document is empty")
+	}
+
+	data, err := r.codec.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := insert(r.index, "", id, data, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stored[T]{
+		ID:          response.ID,
+		Version:     response.Version,
+		SeqNo:       response.SeqNo,
+		PrimaryTerm: response.PrimaryTerm,
+		Document:    document,
+	}, nil
+}
+
+// Get returns the document, or ErrDocumentNotFound
+func (r *Repository[T]) Get(id string, opts ...RequestOption) (*Stored[T], error) {
+	response, err := r.client.Document(r.index, "", id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Found {
+		return nil, ErrDocumentNotFound
+	}
+
+	stored := &Stored[T]{
+		ID:          response.ID,
+		Version:     response.Version,
+		SeqNo:       response.SeqNo,
+		PrimaryTerm: response.PrimaryTerm,
+	}
+	if err := r.codec.Unmarshal(response.Source, &stored.Document); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// Delete deletes the document, or returns ErrDocumentNotFound
+func (r *Repository[T]) Delete(id string, opts ...RequestOption) error {
+	response, err := r.client.DeleteDocument(r.index, "", id, opts...)
+	if err != nil {
+		return err
+	}
+	if response.Result == "not_found" {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+// Exists reports whether the document exists
+func (r *Repository[T]) Exists(id string, opts ...RequestOption) (bool, error) {
+	return r.client.DocumentExists(r.index, "", id, opts...)
+}
+
+// SearchByQuery executes a search query and decodes the hits into T
+func (r *Repository[T]) SearchByQuery(query string, opts ...RequestOption) (*TypedSearchResult[T], error) {
+	return SearchTyped[T](r.client, r.index, query, opts...)
+}
+
+// BulkSave indexes the documents in a single bulk request. The failures of the documents are
+// reported by the items of the result, see Bulk.
+func (r *Repository[T]) BulkSave(documents []T, opts ...RequestOption) (*Bulk, error) {
+	var payload bytes.Buffer
+	for _, document := range documents {
+		id := r.ID(document)
+		if id == "" {
+			return nil, errors.New("the identifier of a document is empty")
+		}
+
+		action, err := r.codec.Marshal(BulkAction{Type: BulkIndex, ID: id})
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.codec.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		payload.Write(action)
+		payload.WriteByte('\n')
+		payload.Write(data)
+		payload.WriteByte('\n')
+	}
+
+	return r.client.Bulk(r.index, payload.Bytes(), opts...)
+}
@@ -0,0 +1,125 @@
+package elasticsearch_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+type repositoryProduct struct {
+	ID    string `json:"id" es:"id,type:keyword"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+// documentStore is a minimal document API with sequence numbers
+func documentStore() *httptest.Server {
+	documents := map[string]string{}
+	seqNos := map[string]int{}
+	seqNo := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		parts := strings.Split(r.URL.Path, "/")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_bulk"):
+			lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+			w.Write([]byte(fmt.Sprintf(`{"errors":false,"items":[%s]}`, strings.Repeat(`{"index":{"status":201}},`, len(lines)/2-1)+`{"index":{"status":201}}`)))
+		case r.Method == "PUT" || r.Method == "POST":
+			id := parts[3]
+			if r.URL.Query().Get("if_seq_no") != "" && r.URL.Query().Get("if_seq_no") != fmt.Sprint(seqNos[id]) {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":{"type":"version_conflict_engine_exception"},"status":409}`))
+				return
+			}
+			documents[id] = string(body)
+			seqNos[id] = seqNo
+			fmt.Fprintf(w, `{"_id":"%s","_version":1,"result":"created","_seq_no":%d,"_primary_term":1}`, id, seqNo)
+			seqNo++
+		case r.Method == "GET":
+			id := parts[3]
+			if source, found := documents[id]; found {
+				fmt.Fprintf(w, `{"_id":"%s","_version":1,"_seq_no":%d,"_primary_term":1,"found":true,"_source":%s}`, id, seqNos[id], source)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"_id":"%s","found":false}`, id)
+		case r.Method == "DELETE":
+			id := parts[3]
+			if _, found := documents[id]; found {
+				delete(documents, id)
+				fmt.Fprintf(w, `{"_id":"%s","result":"deleted"}`, id)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"_id":"%s","result":"not_found"}`, id)
+		}
+	}))
+}
+
+func TestRepository(t *testing.T) {
+	helper := Test{}
+	server := documentStore()
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	products, err := elasticsearch.NewRepository[repositoryProduct](client, "products")
+	helper.OK(t, err)
+
+	_, err = products.Save(repositoryProduct{ID: "1", Name: "shirt", Price: 10})
+	helper.OK(t, err)
+
+	stored, err := products.Get("1")
+	helper.OK(t, err)
+	helper.Equals(t, "shirt", stored.Document.Name)
+
+	// A concurrent writer changes the document, the stale update is rejected
+	_, err = products.Save(repositoryProduct{ID: "1", Name: "shirt", Price: 12})
+	helper.OK(t, err)
+	stored.Document.Price = 8
+	_, err = products.Update(stored)
+	_, conflict := err.(*elasticsearch.ConflictError)
+	helper.Assert(t, conflict, "A conflict error is expected, got %v", err)
+
+	stored, err = products.Get("1")
+	helper.OK(t, err)
+	stored.Document.Price = 8
+	_, err = products.Update(stored)
+	helper.OK(t, err)
+
+	helper.OK(t, products.Delete("1"))
+	helper.Equals(t, elasticsearch.ErrDocumentNotFound, products.Delete("1"))
+	_, err = products.Get("1")
+	helper.Equals(t, elasticsearch.ErrDocumentNotFound, err)
+
+	bulk, err := products.BulkSave([]repositoryProduct{{ID: "2"}, {ID: "3"}})
+	helper.OK(t, err)
+	helper.Equals(t, 2, len(bulk.Items))
+
+	_, err = products.Save(repositoryProduct{Name: "no identifier"})
+	helper.Assert(t, err != nil, "A document without identifier must be rejected")
+}
+
+func TestRepositoryIdentifier(t *testing.T) {
+	helper := Test{}
+	type untagged struct {
+		ID string `json:"id"`
+	}
+	_, err := elasticsearch.NewRepository[untagged](nil, "products")
+	helper.Assert(t, err != nil, "The identifier field is required")
+
+	type base struct {
+		Number int64 `json:"number" es:"id"`
+	}
+	type order struct {
+		base
+		Total float64 `json:"total"`
+	}
+	orders, err := elasticsearch.NewRepository[order](nil, "orders")
+	helper.OK(t, err)
+	helper.Equals(t, "42", orders.ID(order{base: base{Number: 42}}))
+}
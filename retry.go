@@ -0,0 +1,64 @@
+package elasticsearch
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The first wait before retrying a request rejected with 429 and no Retry-After header, doubled on each retry
+var tooManyRequestsBackoff = time.Second
+
+// WithRetryOnTooManyRequests retries the requests rejected with 429 Too Many Requests, such as the
+// bulk requests rejected by a full write thread pool, up to maxRetries times. The client sleeps for the
+// delay of the Retry-After header, or backs off exponentially from a second without it.
+// A delay longer than maxWait isn't waited for, the request failing with a *TooManyRequestsError
+// as when the retries are exhausted. The request bodies are buffered to be sent again.
+func WithRetryOnTooManyRequests(maxRetries int, maxWait time.Duration) ClientOption {
+	return func(c *client) {
+		c.maxRetries = maxRetries
+		c.maxRetryWait = maxWait
+	}
+}
+
+// TooManyRequestsError is returned when a request is still rejected with 429 Too Many Requests
+// once the retries of WithRetryOnTooManyRequests are exhausted
+type TooManyRequestsError struct {
+	Attempts int           // number of requests sent, the first one included
+	Wait     time.Duration // total time slept between the attempts
+	Response string
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return "too many requests after " + strconv.Itoa(e.Attempts) + " attempts and " + e.Wait.String() + " of wait: " + e.Response
+}
+
+// retryWait returns the delay before retrying a request rejected with 429, false when it mustn't be retried
+func (c *client) retryWait(response *http.Response, retries int) (time.Duration, bool) {
+	if retries >= c.maxRetries {
+		return 0, false
+	}
+	wait, found := retryAfter(response.Header.Get("Retry-After"), time.Now())
+	if !found {
+		wait = tooManyRequestsBackoff << uint(retries)
+	}
+	return wait, wait <= c.maxRetryWait
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
@@ -0,0 +1,55 @@
+package elasticsearch_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	helper := Test{}
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch {
+		case r.URL.Path == "/throttled":
+			w.Header().Set("Retry-After", "120")
+		case len(bodies) < 3 || r.URL.Path == "/rejected":
+			w.Header().Set("Retry-After", "0")
+		default:
+			w.Write([]byte(`{"acknowledged":true}`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"type":"es_rejected_execution_exception"},"status":429}`))
+	}))
+	defer server.Close()
+
+	var retries []int
+	client := elasticsearch.NewClientFromUrl(server.URL,
+		elasticsearch.WithRetryOnTooManyRequests(2, time.Minute),
+		elasticsearch.WithMetrics(func(m elasticsearch.RequestMetrics) { retries = append(retries, m.Retries) }))
+
+	response, err := client.CreateIndex("orders", `{"settings":{"number_of_shards":1}}`)
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The index creation is expected to be acknowledged once retried")
+	helper.Equals(t, []int{0, 1, 2}, retries)
+	helper.Equals(t, bodies[0], bodies[2])
+
+	_, err = client.DeleteIndex("rejected")
+	var tooManyRequests *elasticsearch.TooManyRequestsError
+	helper.Assert(t, errors.As(err, &tooManyRequests), "A *TooManyRequestsError is expected once the retries are exhausted")
+	helper.Equals(t, 3, tooManyRequests.Attempts)
+	helper.Equals(t, time.Duration(0), tooManyRequests.Wait)
+	helper.Equals(t, `{"error":{"type":"es_rejected_execution_exception"},"status":429}`, tooManyRequests.Response)
+
+	_, err = client.DeleteIndex("throttled")
+	helper.Assert(t, errors.As(err, &tooManyRequests), "A *TooManyRequestsError is expected when Retry-After exceeds the maximum wait")
+	helper.Equals(t, 1, tooManyRequests.Attempts)
+}
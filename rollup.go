@@ -0,0 +1,148 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Rollup exposes the rollup APIs, summarizing historical data into rollup indices searched along
+// with the live indices by Search
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-apis.html
+type Rollup interface {
+	PutJob(id, body string) (*Response, error)
+	GetJobs(id string) (*RollupJobs, error)
+	DeleteJob(id string) (*Response, error)
+	StartJob(id string) (*RollupJobState, error)
+	StopJob(id string, waitForCompletion bool) (*RollupJobState, error)
+	Caps(indexPattern string) (map[string]RollupCaps, error)
+	Search(indices, data string) (*SearchResult, error)
+}
+
+// RollupJobs represents the configuration, the status and the statistics of rollup jobs
+type RollupJobs struct {
+	RawResponse
+
+	Jobs []struct {
+		Config struct {
+			ID           string          `json:"id"`
+			IndexPattern string          `json:"index_pattern"`
+			RollupIndex  string          `json:"rollup_index"`
+			Cron         string          `json:"cron"`
+			PageSize     int             `json:"page_size"`
+			Timeout      string          `json:"timeout"`
+			Groups       json.RawMessage `json:"groups"`
+			Metrics      json.RawMessage `json:"metrics"`
+		} `json:"config"`
+		Status struct {
+			JobState string `json:"job_state"` // started, indexing, stopping, stopped or aborting
+		} `json:"status"`
+		Stats struct {
+			PagesProcessed     int64 `json:"pages_processed"`
+			DocumentsProcessed int64 `json:"documents_processed"`
+			RollupsIndexed     int64 `json:"rollups_indexed"`
+			TriggerCount       int64 `json:"trigger_count"`
+			IndexFailures      int64 `json:"index_failures"`
+			SearchFailures     int64 `json:"search_failures"`
+			IndexTimeInMillis  int64 `json:"index_time_in_ms"`
+			SearchTimeInMillis int64 `json:"search_time_in_ms"`
+		} `json:"stats"`
+	} `json:"jobs"`
+}
+
+// RollupJobState represents the response of the start or the stop of a rollup job
+type RollupJobState struct {
+	RawResponse
+
+	Started bool `json:"started"`
+	Stopped bool `json:"stopped"`
+}
+
+// RollupCaps represents the rollup jobs of an index pattern, and the aggregations they support by field
+type RollupCaps struct {
+	RollupJobs []struct {
+		JobID        string                              `json:"job_id"`
+		RollupIndex  string                              `json:"rollup_index"`
+		IndexPattern string                              `json:"index_pattern"`
+		Fields       map[string][]map[string]interface{} `json:"fields"` // such as {"agg": "date_histogram", "fixed_interval": "1h"}
+	} `json:"rollup_jobs"`
+}
+
+type rollup struct {
+	client *client
+}
+
+// Rollup returns the client of the rollup APIs
+func (c *client) Rollup() Rollup {
+	return &rollup{client: c}
+}
+
+// PutJob creates a rollup job, started with StartJob
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-put-job.html
+func (r *rollup) PutJob(id, body string) (*Response, error) {
+	return r.client.acknowledge("PUT", "/_rollup/job/"+escapePath(id), body)
+}
+
+// GetJobs returns the configuration, the status and the statistics of a rollup job, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-get-job.html
+func (r *rollup) GetJobs(id string) (*RollupJobs, error) {
+	if id == "" {
+		id = "_all"
+	}
+	result := &RollupJobs{}
+	if err := r.client.sendAPIRequest("GET", "/_rollup/job/"+escapePath(id), "", result); err != nil {
+		return &RollupJobs{}, err
+	}
+	return result, nil
+}
+
+// DeleteJob deletes a stopped rollup job, the rolled up data being kept
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-delete-job.html
+func (r *rollup) DeleteJob(id string) (*Response, error) {
+	return r.client.acknowledge("DELETE", "/_rollup/job/"+escapePath(id), "")
+}
+
+// StartJob starts a rollup job
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-start-job.html
+func (r *rollup) StartJob(id string) (*RollupJobState, error) {
+	result := &RollupJobState{}
+	if err := r.client.sendAPIRequest("POST", "/_rollup/job/"+escapePath(id)+"/_start", "", result); err != nil {
+		return &RollupJobState{}, err
+	}
+	return result, nil
+}
+
+// StopJob stops a rollup job, waiting for it to be stopped when waitForCompletion is true
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-stop-job.html
+func (r *rollup) StopJob(id string, waitForCompletion bool) (*RollupJobState, error) {
+	path := "/_rollup/job/" + escapePath(id) + "/_stop?wait_for_completion=" + strconv.FormatBool(waitForCompletion)
+	result := &RollupJobState{}
+	if err := r.client.sendAPIRequest("POST", path, "", result); err != nil {
+		return &RollupJobState{}, err
+	}
+	return result, nil
+}
+
+// Caps returns the rollup capabilities of the index patterns matching indexPattern, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-get-rollup-caps.html
+func (r *rollup) Caps(indexPattern string) (map[string]RollupCaps, error) {
+	path := "/_rollup/data"
+	if indexPattern != "" {
+		path += "/" + r.client.indexPath(indexPattern)
+	}
+	caps := make(map[string]RollupCaps)
+	if err := r.client.sendAPIRequest("GET", path, "", &caps); err != nil {
+		return map[string]RollupCaps{}, err
+	}
+	return caps, nil
+}
+
+// Search searches rollup indices, and live indices if any, the rolled up data being merged into the
+// aggregations. Only the aggregations and the queries supported by the rollup jobs are accepted.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-search.html
+func (r *rollup) Search(indices, data string) (*SearchResult, error) {
+	result := &SearchResult{}
+	if err := r.client.sendAPIRequest("POST", "/"+r.client.indexPath(indices)+"/_rollup_search", data, result); err != nil {
+		return &SearchResult{}, err
+	}
+	return result, nil
+}
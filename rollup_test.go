@@ -0,0 +1,57 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestRollup(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch r.URL.Path {
+		case "/_rollup/job/sensors":
+			w.Write([]byte(`{"acknowledged":true}`))
+		case "/_rollup/job/sensors/_start":
+			w.Write([]byte(`{"started":true}`))
+		case "/_rollup/job/_all":
+			w.Write([]byte(`{"jobs":[{"config":{"id":"sensors","index_pattern":"sensor-*","rollup_index":"sensor_rollup","cron":"*/30 * * * * ?","page_size":1000},"status":{"job_state":"indexing"},"stats":{"documents_processed":5000,"rollups_indexed":120}}]}`))
+		case "/_rollup/data/sensor-*":
+			w.Write([]byte(`{"sensor-*":{"rollup_jobs":[{"job_id":"sensors","rollup_index":"sensor_rollup","index_pattern":"sensor-*","fields":{"temperature":[{"agg":"avg"},{"agg":"max"}]}}]}}`))
+		case "/sensor-1,sensor_rollup/_rollup_search":
+			w.Write([]byte(`{"took":3,"hits":{"total":{"value":0,"relation":"eq"},"hits":[]},"aggregations":{"max_temperature":{"value":202}}}`))
+		}
+	}))
+	defer server.Close()
+
+	rollup := elasticsearch.NewClientFromUrl(server.URL).Rollup()
+	_, err := rollup.PutJob("sensors", `{"index_pattern":"sensor-*","rollup_index":"sensor_rollup","cron":"*/30 * * * * ?","page_size":1000}`)
+	helper.OK(t, err)
+
+	state, err := rollup.StartJob("sensors")
+	helper.OK(t, err)
+	helper.Assert(t, state.Started, "The job is expected to be started")
+
+	jobs, err := rollup.GetJobs("")
+	helper.OK(t, err)
+	helper.Equals(t, "indexing", jobs.Jobs[0].Status.JobState)
+	helper.Equals(t, int64(120), jobs.Jobs[0].Stats.RollupsIndexed)
+
+	caps, err := rollup.Caps("sensor-*")
+	helper.OK(t, err)
+	helper.Equals(t, "max", caps["sensor-*"].RollupJobs[0].Fields["temperature"][1]["agg"])
+
+	result, err := rollup.Search("sensor-1,sensor_rollup", `{"size":0,"aggregations":{"max_temperature":{"max":{"field":"temperature"}}}}`)
+	helper.OK(t, err)
+	max, err := elasticsearch.ParseMetricAgg(result, "max_temperature")
+	helper.OK(t, err)
+	helper.Equals(t, float64(202), *max.Value)
+
+	helper.Equals(t, "GET /_rollup/data/sensor-* ", requests[3])
+}
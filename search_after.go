@@ -0,0 +1,85 @@
+package elasticsearch
+
+// The size of the pages of a search request without size
+const defaultSearchSize = 10
+
+// SearchAfter paginates the hits of a search request with search_after, which unlike from and size
+// isn't limited to the first 10,000 hits. A tie-breaker sort is added so that the hits having the
+// same sort values are neither skipped nor repeated across the pages.
+//
+//	pages := elasticsearch.NewSearchAfter(&elasticsearch.SearchRequest{Sort: []interface{}{"created"}}, "")
+//	for {
+//		result, err := client.SearchWithRequest("orders", pages.Request())
+//		// ...
+//		if !pages.Next(result) {
+//			break
+//		}
+//	}
+//
+// The sort values of long fields exceeding 2^53 lose their precision unless the client decodes the
+// numbers with WithUseNumber.
+type SearchAfter struct {
+	request *SearchRequest
+}
+
+// NewSearchAfter paginates the request, whose sort is completed with tieBreaker in ascending order.
+// An empty tieBreaker is _shard_doc when searching a point in time, and _id otherwise; a unique field
+// with doc values is preferred without point in time, sorting on _id requiring fielddata.
+// The request is modified by the pagination.
+func NewSearchAfter(request *SearchRequest, tieBreaker string) *SearchAfter {
+	if tieBreaker == "" {
+		tieBreaker = "_id"
+		if request.PIT != nil {
+			tieBreaker = "_shard_doc"
+		}
+	}
+	if !sortsOn(request.Sort, tieBreaker) {
+		request.Sort = append(request.Sort, map[string]string{tieBreaker: "asc"})
+	}
+	return &SearchAfter{request: request}
+}
+
+// Request returns the request of the next page
+func (s *SearchAfter) Request() *SearchRequest {
+	return s.request
+}
+
+// Next moves the request after the last hit of the page, reporting whether there may be a next page
+func (s *SearchAfter) Next(result *SearchResult) bool {
+	hits := result.Hits.Hits
+	if len(hits) == 0 || len(hits[len(hits)-1].Sort) == 0 {
+		return false
+	}
+	s.request.SearchAfter = hits[len(hits)-1].Sort
+	// The identifier of a point in time may change between the searches
+	if s.request.PIT != nil && result.PitID != "" {
+		s.request.PIT.ID = result.PitID
+	}
+
+	size := defaultSearchSize
+	if s.request.Size != nil {
+		size = *s.request.Size
+	}
+	return len(hits) >= size
+}
+
+// sortsOn reports whether the sort includes the field, given as a name or as an object keyed by name
+func sortsOn(sort []interface{}, field string) bool {
+	for _, criterion := range sort {
+		switch criterion := criterion.(type) {
+		case string:
+			if criterion == field {
+				return true
+			}
+		case map[string]string:
+			if _, found := criterion[field]; found {
+				return true
+			}
+		case map[string]interface{}:
+			if _, found := criterion[field]; found {
+				return true
+			}
+		}
+	}
+	return false
+}
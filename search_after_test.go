@@ -0,0 +1,61 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestSearchAfter(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	size := 2
+	pages := elasticsearch.NewSearchAfter(&elasticsearch.SearchRequest{Size: &size, Sort: []interface{}{map[string]string{"created": "desc"}}}, "order_id")
+	pageResponses := []string{
+		`{"hits":{"hits":[{"_id":"1","sort":[1700000000000,"o-1"]},{"_id":"2","sort":[1700000000000,"o-2"]}]}}`,
+		`{"hits":{"hits":[{"_id":"3","sort":[1690000000000,"o-3"]}]}}`,
+	}
+	var ids []string
+	for _, response := range pageResponses {
+		recorder.Respond("POST", "/orders/_search", http.StatusOK, response)
+		result, err := client.SearchWithRequest("orders", pages.Request())
+		helper.OK(t, err)
+		for _, hit := range result.Hits.Hits {
+			ids = append(ids, hit.ID)
+		}
+		if !pages.Next(result) {
+			break
+		}
+	}
+
+	helper.Equals(t, []string{"1", "2", "3"}, ids)
+	requests := recorder.Requests()
+	helper.Equals(t, 2, len(requests))
+	helper.Equals(t, `{"size":2,"sort":[{"created":"desc"},{"order_id":"asc"}]}`, requests[0].Body)
+	helper.Equals(t, `{"size":2,"sort":[{"created":"desc"},{"order_id":"asc"}],"search_after":[1700000000000,"o-2"]}`, requests[1].Body)
+}
+
+func TestSearchAfterPointInTime(t *testing.T) {
+	helper := Test{}
+	request := &elasticsearch.SearchRequest{Sort: []interface{}{"created", "_shard_doc"}, PIT: &elasticsearch.PointInTime{ID: "pit-1", KeepAlive: "1m"}}
+	pages := elasticsearch.NewSearchAfter(request, "")
+	helper.Equals(t, []interface{}{"created", "_shard_doc"}, request.Sort)
+
+	result := elasticsearch.SearchResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"pit_id":"pit-2","hits":{"hits":[{"_id":"1","sort":[1,42]}]}}`), &result))
+	helper.Assert(t, !pages.Next(&result), "A page smaller than the size is expected to be the last one")
+	helper.Equals(t, "pit-2", pages.Request().PIT.ID)
+
+	body, err := json.Marshal(pages.Request())
+	helper.OK(t, err)
+	helper.Equals(t, `{"sort":["created","_shard_doc"],"search_after":[1,42],"pit":{"id":"pit-2","keep_alive":"1m"}}`, string(body))
+
+	recorder := elasticsearch.NewRequestRecorder()
+	_, err = elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder)).SearchWithRequest("", pages.Request())
+	helper.OK(t, err)
+	helper.Equals(t, "/_search", recorder.Requests()[0].URL)
+}
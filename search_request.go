@@ -0,0 +1,119 @@
+package elasticsearch
+
+import "encoding/json"
+
+// SearchRequest represents the body of a search request, complementing the raw string queries of Search
+type SearchRequest struct {
+	Query     interface{}   `json:"query,omitempty"` // a json.RawMessage, or a builder such as MoreLikeThisQuery
+	From      int           `json:"from,omitempty"`
+	Size      *int          `json:"size,omitempty"` // nil uses the default size, 0 only returns aggregations
+	Sort      []interface{} `json:"sort,omitempty"`
+	Source    interface{}   `json:"_source,omitempty"` // false, a field pattern or a list of field patterns
+	Highlight *Highlight    `json:"highlight,omitempty"`
+	Aggs      Aggregations  `json:"aggs,omitempty"`
+	Collapse  *Collapse     `json:"collapse,omitempty"`
+	Suggest   Suggesters    `json:"suggest,omitempty"`
+
+	// PostFilter filters the hits after the aggregations are computed, such as the selected facets of a
+	// product search whose aggregations must still count the other values
+	PostFilter json.RawMessage `json:"post_filter,omitempty"`
+
+	// Rescore reorders the top hits of each shard with a more expensive query, the rescorers being
+	// applied one after the other
+	Rescore []Rescore `json:"rescore,omitempty"`
+
+	// MinScore excludes the hits scoring less than the minimum
+	MinScore float64 `json:"min_score,omitempty"`
+
+	// TrackTotalHits is true to count all the hits accurately, false to skip the count,
+	// or a number of hits to count accurately, 10000 by default
+	TrackTotalHits interface{} `json:"track_total_hits,omitempty"`
+
+	// Profile returns the timing of the execution of the query on each shard in the Profile of the result
+	Profile bool `json:"profile,omitempty"`
+
+	// SearchAfter returns the hits following the sort values of a hit, set by SearchAfter to paginate
+	SearchAfter []interface{} `json:"search_after,omitempty"`
+
+	// PIT searches a point in time, the request being sent without index
+	PIT *PointInTime `json:"pit,omitempty"`
+}
+
+// PointInTime represents a point in time of a search request, opened with the point in time API
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/point-in-time-api.html
+type PointInTime struct {
+	ID        string `json:"id"`
+	KeepAlive string `json:"keep_alive,omitempty"` // such as "1m", extending the point in time
+}
+
+// Highlight represents the highlighting options of a search request.
+// The highlighted fragments are returned in the Highlight field of each Hit.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/highlighting.html
+type Highlight struct {
+	Fields            map[string]HighlightField `json:"fields"`
+	Type              string                    `json:"type,omitempty"`
+	PreTags           []string                  `json:"pre_tags,omitempty"`
+	PostTags          []string                  `json:"post_tags,omitempty"`
+	FragmentSize      int                       `json:"fragment_size,omitempty"`
+	NumberOfFragments *int                      `json:"number_of_fragments,omitempty"`
+	Order             string                    `json:"order,omitempty"`
+	Encoder           string                    `json:"encoder,omitempty"`
+	RequireFieldMatch *bool                     `json:"require_field_match,omitempty"`
+}
+
+// HighlightField represents the highlighting options of a field, overriding the global ones
+type HighlightField struct {
+	Type              string          `json:"type,omitempty"`
+	PreTags           []string        `json:"pre_tags,omitempty"`
+	PostTags          []string        `json:"post_tags,omitempty"`
+	FragmentSize      int             `json:"fragment_size,omitempty"`
+	NumberOfFragments *int            `json:"number_of_fragments,omitempty"`
+	MatchedFields     []string        `json:"matched_fields,omitempty"`
+	HighlightQuery    json.RawMessage `json:"highlight_query,omitempty"`
+}
+
+// Rescore represents a query rescorer, rescoring the WindowSize top hits of each shard.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/filter-search-results.html#rescore
+type Rescore struct {
+	WindowSize int          `json:"window_size,omitempty"` // 10 by default
+	Query      RescoreQuery `json:"query"`
+}
+
+// RescoreQuery represents the query of a rescorer and how its score is combined with the original one
+type RescoreQuery struct {
+	RescoreQuery       interface{} `json:"rescore_query"`                  // a json.RawMessage, or a builder such as FunctionScoreQuery
+	QueryWeight        *float64    `json:"query_weight,omitempty"`         // 1 by default
+	RescoreQueryWeight *float64    `json:"rescore_query_weight,omitempty"` // 1 by default
+	ScoreMode          string      `json:"score_mode,omitempty"`           // total, multiply, avg, max or min
+}
+
+// Collapse represents the collapsing of the hits on a keyword or numeric field, only the top hit of each
+// value being returned, such as the best product of each family. The collapsed value of a hit is in its Fields,
+// and the other hits of its group in its InnerHits.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/collapse-search-results.html
+type Collapse struct {
+	Field                      string             `json:"field"`
+	InnerHits                  []InnerHitsRequest `json:"inner_hits,omitempty"`
+	MaxConcurrentGroupSearches int                `json:"max_concurrent_group_searches,omitempty"`
+}
+
+// InnerHitsRequest represents the inner hits returned with each hit, of a collapsed group or of a nested query
+type InnerHitsRequest struct {
+	Name      string        `json:"name,omitempty"` // key of the InnerHits of the hits
+	From      int           `json:"from,omitempty"`
+	Size      *int          `json:"size,omitempty"` // 3 by default
+	Sort      []interface{} `json:"sort,omitempty"`
+	Source    interface{}   `json:"_source,omitempty"`
+	Highlight *Highlight    `json:"highlight,omitempty"`
+	Collapse  *Collapse     `json:"collapse,omitempty"` // second level of collapsing
+}
+
+// SearchWithRequest executes a search described by a SearchRequest
+func (c *client) SearchWithRequest(indexName string, request *SearchRequest, opts ...RequestOption) (*SearchResult, error) {
+	body, err := c.codec.Marshal(request)
+	if err != nil {
+		return &SearchResult{}, err
+	}
+
+	return c.Search(indexName, "", string(body), false, opts...)
+}
@@ -0,0 +1,112 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+)
+
+// defaultScrollKeepAlive is the time a scroll is kept between two pages of SearchStream
+const defaultScrollKeepAlive = "1m"
+
+// scrollResult represents a page of a scrolled search
+type scrollResult struct {
+	ScrollID string `json:"_scroll_id"`
+	SearchResult
+}
+
+// SearchStream executes the query and delivers every matching hit on the returned channel, paging through
+// the results with a scroll in a goroutine. The size of the pages is set by WithSize, and the time the
+// scroll is kept between two pages by WithParam("scroll", "5m"). The next page is requested once the hits
+// of the current one have been received.
+//
+// The hits channel is closed at the end of the results or on failure, the error channel then receives the
+// error if any and is closed. A consumer stopping before the end must cancel the context given with WithContext,
+// to release the goroutine and the scroll:
+//
+//	hits, errs := client.SearchStream("logs", query, elasticsearch.WithContext(ctx), elasticsearch.WithSize(1000))
+//	for hit := range hits {
+//		process(hit)
+//	}
+//	if err := <-errs; err != nil {
+//		return err
+//	}
+//
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/paginate-search-results.html#scroll-search-results
+func (c *client) SearchStream(indexName, query string, opts ...RequestOption) (<-chan Hit, <-chan error) {
+	hits := make(chan Hit)
+	errs := make(chan error, 1)
+	options := newRequestOptions(opts)
+
+	go func() {
+		defer close(errs)
+		defer close(hits)
+		if err := c.streamHits(indexName, query, options, hits); err != nil {
+			errs <- err
+		}
+	}()
+	return hits, errs
+}
+
+func (c *client) streamHits(indexName, query string, options *requestOptions, hits chan<- Hit) error {
+	ctx := options.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if options.params.Get("scroll") == "" {
+		options.params.Set("scroll", defaultScrollKeepAlive)
+	}
+	// The next pages are requested with the context and the timeout of the search only
+	scrollOptions := &requestOptions{params: url.Values{}, ctx: options.ctx, timeout: options.timeout, hasTimeout: options.hasTimeout}
+	keepAlive := options.params.Get("scroll")
+
+	page := &scrollResult{}
+	url := options.url(c.Host.String() + "/" + c.indexPath(indexName) + "/_search")
+	if err := c.sendJSONRequest(options, "POST", url, strings.NewReader(query), page); err != nil {
+		return err
+	}
+	scrollID := page.ScrollID
+	defer func() {
+		if scrollID != "" {
+			c.clearScroll(scrollID)
+		}
+	}()
+
+	for {
+		if page.Error != nil {
+			return page.Error
+		}
+		if len(page.Hits.Hits) == 0 {
+			return nil
+		}
+		for _, hit := range page.Hits.Hits {
+			select {
+			case hits <- hit:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		body, err := c.codec.Marshal(map[string]string{"scroll": keepAlive, "scroll_id": scrollID})
+		if err != nil {
+			return err
+		}
+		page = &scrollResult{}
+		if err := c.sendJSONRequest(scrollOptions, "POST", c.Host.String()+"/_search/scroll", bytes.NewReader(body), page); err != nil {
+			return err
+		}
+		if page.ScrollID != "" {
+			scrollID = page.ScrollID
+		}
+	}
+}
+
+// clearScroll releases a scroll before it expires, the failures being ignored as it expires anyway
+func (c *client) clearScroll(scrollID string) {
+	body, err := c.codec.Marshal(map[string][]string{"scroll_id": {scrollID}})
+	if err != nil {
+		return
+	}
+	c.sendRequest(nil, "DELETE", c.Host.String()+"/_search/scroll", bytes.NewReader(body))
+}
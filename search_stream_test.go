@@ -0,0 +1,75 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+// scrollServer serves pages of two hits among total, and reports the cleared scrolls
+func scrollServer(total int, cleared chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		page := 0
+		switch {
+		case r.Method == "DELETE":
+			cleared <- string(body)
+			w.Write([]byte(`{"succeeded":true}`))
+			return
+		case r.URL.Path == "/_search/scroll":
+			fmt.Sscanf(string(body), `{"scroll":"1m","scroll_id":"page-%d"}`, &page)
+		case r.URL.Query().Get("scroll") != "1m":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		hits := []string{}
+		for i := page * 2; i < page*2+2 && i < total; i++ {
+			hits = append(hits, fmt.Sprintf(`{"_id":"%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"_scroll_id":"page-%d","hits":{"hits":[%s]}}`, page+1, strings.Join(hits, ","))
+	}))
+}
+
+func TestSearchStream(t *testing.T) {
+	helper := Test{}
+	cleared := make(chan string, 1)
+	server := scrollServer(5, cleared)
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	hits, errs := client.SearchStream("logs", `{"query":{"match_all":{}}}`, elasticsearch.WithSize(2))
+	ids := []string{}
+	for hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	helper.OK(t, <-errs)
+	helper.Equals(t, []string{"0", "1", "2", "3", "4"}, ids)
+	helper.Equals(t, `{"scroll_id":["page-4"]}`, <-cleared)
+}
+
+func TestSearchStreamCancel(t *testing.T) {
+	helper := Test{}
+	cleared := make(chan string, 1)
+	server := scrollServer(100, cleared)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	hits, errs := client.SearchStream("logs", `{}`, elasticsearch.WithContext(ctx))
+	<-hits
+	cancel()
+	for range hits {
+	}
+	helper.Equals(t, context.Canceled, <-errs)
+	helper.Assert(t, strings.HasPrefix(<-cleared, `{"scroll_id":["page-`), "The scroll must be cleared")
+
+	_, errs = client.SearchStream("logs", `{}`, elasticsearch.WithParam("scroll", "10s"))
+	helper.Assert(t, <-errs != nil, "The failure of the search must be returned")
+}
@@ -0,0 +1,430 @@
+package elasticsearch
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Security exposes the security APIs managing the native users and roles of the cluster, and the API keys
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api.html
+type Security interface {
+	PutUser(username string, user User) (*SecurityResult, error)
+	GetUsers(usernames ...string) (map[string]User, error)
+	DeleteUser(username string) (*SecurityResult, error)
+	PutRole(name string, role Role) (*SecurityResult, error)
+	GetRoles(names ...string) (map[string]Role, error)
+	DeleteRole(name string) (*SecurityResult, error)
+	CreateAPIKey(request APIKeyRequest) (*APIKey, error)
+	GrantAPIKey(grant APIKeyGrant) (*APIKey, error)
+	InvalidateAPIKey(filter APIKeyFilter) (*InvalidatedAPIKeys, error)
+	GetAPIKey(filter APIKeyFilter) ([]APIKeyInfo, error)
+	Authenticate() (*AuthenticatedUser, error)
+	HasPrivileges(body string) (*PrivilegesResult, error)
+}
+
+// User represents a native user. The password is only sent on creation or to change it,
+// and never returned.
+type User struct {
+	Username string                 `json:"username,omitempty"`
+	Password string                 `json:"password,omitempty"`
+	Roles    []string               `json:"roles"`
+	FullName string                 `json:"full_name,omitempty"`
+	Email    string                 `json:"email,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Enabled  *bool                  `json:"enabled,omitempty"` // true by default
+}
+
+// Role represents the cluster, index and application privileges granted to the users having the role
+type Role struct {
+	Cluster      []string                `json:"cluster,omitempty"` // such as monitor or manage_index_templates
+	Indices      []IndexPrivileges       `json:"indices,omitempty"`
+	Applications []ApplicationPrivileges `json:"applications,omitempty"`
+	RunAs        []string                `json:"run_as,omitempty"`
+	Metadata     map[string]interface{}  `json:"metadata,omitempty"`
+}
+
+// IndexPrivileges represents the privileges granted on the indices matching the names,
+// optionally restricted to some fields and to the documents matching a query
+type IndexPrivileges struct {
+	Names                  []string       `json:"names"`
+	Privileges             []string       `json:"privileges"` // such as read, write or create_index
+	FieldSecurity          *FieldSecurity `json:"field_security,omitempty"`
+	Query                  interface{}    `json:"query,omitempty"` // a json.RawMessage or a string
+	AllowRestrictedIndices bool           `json:"allow_restricted_indices,omitempty"`
+}
+
+// FieldSecurity represents the fields of the documents a role can read
+type FieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+// ApplicationPrivileges represents the privileges granted on the resources of an application, such as Kibana
+type ApplicationPrivileges struct {
+	Application string   `json:"application"`
+	Privileges  []string `json:"privileges"`
+	Resources   []string `json:"resources"`
+}
+
+// SecurityResult represents the response of the creation or the deletion of a user or a role.
+// Created is false when an existing user or role is updated, Found is false when a missing one is deleted.
+type SecurityResult struct {
+	RawResponse
+
+	Created bool `json:"created"`
+	Found   bool `json:"found"`
+}
+
+// APIKeyRequest represents an API key to create, with the privileges of its owner
+// restricted to the RoleDescriptors if any
+type APIKeyRequest struct {
+	Name            string                 `json:"name"`
+	Expiration      string                 `json:"expiration,omitempty"` // such as "1h" or "7d", never by default
+	RoleDescriptors map[string]Role        `json:"role_descriptors,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// APIKeyGrant represents an API key created on behalf of a user, authenticated by a password or an access token
+type APIKeyGrant struct {
+	GrantType   string        `json:"grant_type"` // password or access_token
+	Username    string        `json:"username,omitempty"`
+	Password    string        `json:"password,omitempty"`
+	AccessToken string        `json:"access_token,omitempty"`
+	APIKey      APIKeyRequest `json:"api_key"`
+	RunAs       string        `json:"run_as,omitempty"`
+}
+
+// APIKey represents a created API key. The key itself is only returned on creation.
+type APIKey struct {
+	RawResponse
+
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Expiration int64  `json:"expiration,omitempty"` // in milliseconds since the epoch
+	Key        string `json:"api_key"`
+	Encoded    string `json:"encoded"` // from Elasticsearch 7.16, see EncodedKey
+}
+
+// EncodedKey returns the credentials sent in the Authorization header, base64(id:api_key)
+func (k *APIKey) EncodedKey() string {
+	if k.Encoded != "" {
+		return k.Encoded
+	}
+	return base64.StdEncoding.EncodeToString([]byte(k.ID + ":" + k.Key))
+}
+
+// APIKeyFilter selects API keys by identifiers, name, realm or username, or the ones owned by
+// the authenticated user when Owner is true. The name accepts wildcards.
+type APIKeyFilter struct {
+	IDs       []string `json:"ids,omitempty"` // a single identifier can be retrieved at once
+	Name      string   `json:"name,omitempty"`
+	RealmName string   `json:"realm_name,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	Owner     bool     `json:"owner,omitempty"`
+}
+
+// APIKeyInfo represents the information of an API key, without the key itself
+type APIKeyInfo struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Creation    int64                  `json:"creation"`             // in milliseconds since the epoch
+	Expiration  int64                  `json:"expiration,omitempty"` // in milliseconds since the epoch
+	Invalidated bool                   `json:"invalidated"`
+	Username    string                 `json:"username"`
+	Realm       string                 `json:"realm"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// InvalidatedAPIKeys represents the response of the invalidation of API keys
+type InvalidatedAPIKeys struct {
+	RawResponse
+
+	InvalidatedAPIKeys           []string     `json:"invalidated_api_keys"`
+	PreviouslyInvalidatedAPIKeys []string     `json:"previously_invalidated_api_keys"`
+	ErrorCount                   int          `json:"error_count"`
+	ErrorDetails                 []ErrorCause `json:"error_details,omitempty"`
+}
+
+// AuthenticatedUser represents the user of the credentials of the client, and how they have been authenticated
+type AuthenticatedUser struct {
+	RawResponse
+
+	Username            string                 `json:"username"`
+	Roles               []string               `json:"roles"`
+	FullName            string                 `json:"full_name"`
+	Email               string                 `json:"email"`
+	Metadata            map[string]interface{} `json:"metadata"`
+	Enabled             bool                   `json:"enabled"`
+	AuthenticationRealm Realm                  `json:"authentication_realm"`
+	LookupRealm         Realm                  `json:"lookup_realm"`
+	AuthenticationType  string                 `json:"authentication_type"` // realm, api_key, token...
+}
+
+// Realm represents a realm authenticating users, such as the native or the file realm
+type Realm struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// PrivilegesResult represents whether the authenticated user has the requested privileges, by privilege
+type PrivilegesResult struct {
+	RawResponse
+
+	Username        string                                `json:"username"`
+	HasAllRequested bool                                  `json:"has_all_requested"`
+	Cluster         map[string]bool                       `json:"cluster"`
+	Index           map[string]map[string]bool            `json:"index"`       // by index then privilege
+	Application     map[string]map[string]map[string]bool `json:"application"` // by application, resource then privilege
+}
+
+// Missing returns the requested privileges the user doesn't have, sorted, such as "cluster:monitor"
+// or "index:orders-*:write"
+func (p *PrivilegesResult) Missing() []string {
+	missing := []string{}
+	for privilege, granted := range p.Cluster {
+		if !granted {
+			missing = append(missing, "cluster:"+privilege)
+		}
+	}
+	for index, privileges := range p.Index {
+		for privilege, granted := range privileges {
+			if !granted {
+				missing = append(missing, "index:"+index+":"+privilege)
+			}
+		}
+	}
+	for application, resources := range p.Application {
+		for resource, privileges := range resources {
+			for privilege, granted := range privileges {
+				if !granted {
+					missing = append(missing, "application:"+application+":"+resource+":"+privilege)
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Err returns a *MissingPrivilegesError when the user doesn't have all the requested privileges, nil otherwise.
+// It's meant to fail fast at startup:
+//
+//	privileges, err := client.Security().HasPrivileges(`{"index":[{"names":["orders-*"],"privileges":["read","write"]}]}`)
+//	if err == nil {
+//		err = privileges.Err()
+//	}
+func (p *PrivilegesResult) Err() error {
+	if p.HasAllRequested {
+		return nil
+	}
+	return &MissingPrivilegesError{Username: p.Username, Missing: p.Missing()}
+}
+
+// APIKeySigner authenticates the requests with an API key, such as the EncodedKey of a created key:
+//
+//	client := elasticsearch.NewClientFromUrl(rawurl, elasticsearch.WithSigner(elasticsearch.APIKeySigner(key.EncodedKey())))
+type APIKeySigner string
+
+// Sign sets the Authorization header of the request
+func (s APIKeySigner) Sign(req *http.Request) error {
+	req.Header.Set("Authorization", "ApiKey "+string(s))
+	return nil
+}
+
+type security struct {
+	client *client
+}
+
+// Security returns the client of the security APIs
+func (c *client) Security() Security {
+	return &security{client: c}
+}
+
+// PutUser creates or updates a native user
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-put-user.html
+func (s *security) PutUser(username string, user User) (*SecurityResult, error) {
+	// The username is the one of the path, it's rejected in the body
+	user.Username = ""
+	body, err := s.client.codec.Marshal(user)
+	if err != nil {
+		return &SecurityResult{}, err
+	}
+
+	result := &SecurityResult{}
+	if err := s.client.sendAPIRequest("PUT", "/_security/user/"+escapePath(username), string(body), result); err != nil {
+		return &SecurityResult{}, err
+	}
+	return result, nil
+}
+
+// GetUsers returns the native users by username, all of them if none is given
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-get-user.html
+func (s *security) GetUsers(usernames ...string) (map[string]User, error) {
+	users := make(map[string]User)
+	if err := s.client.sendAPIRequest("GET", "/_security/user"+securityNames(usernames), "", &users); err != nil {
+		return map[string]User{}, err
+	}
+	return users, nil
+}
+
+// DeleteUser deletes a native user
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-delete-user.html
+func (s *security) DeleteUser(username string) (*SecurityResult, error) {
+	result := &SecurityResult{}
+	if err := s.client.sendAPIRequest("DELETE", "/_security/user/"+escapePath(username), "", result); err != nil {
+		return &SecurityResult{}, err
+	}
+	return result, nil
+}
+
+// PutRole creates or updates a native role
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-put-role.html
+func (s *security) PutRole(name string, role Role) (*SecurityResult, error) {
+	body, err := s.client.codec.Marshal(role)
+	if err != nil {
+		return &SecurityResult{}, err
+	}
+
+	var result struct {
+		Role SecurityResult `json:"role"`
+	}
+	if err := s.client.sendAPIRequest("PUT", "/_security/role/"+escapePath(name), string(body), &result); err != nil {
+		return &SecurityResult{}, err
+	}
+	return &result.Role, nil
+}
+
+// GetRoles returns the native roles by name, all of them if none is given
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-get-role.html
+func (s *security) GetRoles(names ...string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	if err := s.client.sendAPIRequest("GET", "/_security/role"+securityNames(names), "", &roles); err != nil {
+		return map[string]Role{}, err
+	}
+	return roles, nil
+}
+
+// DeleteRole deletes a native role
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-delete-role.html
+func (s *security) DeleteRole(name string) (*SecurityResult, error) {
+	result := &SecurityResult{}
+	if err := s.client.sendAPIRequest("DELETE", "/_security/role/"+escapePath(name), "", result); err != nil {
+		return &SecurityResult{}, err
+	}
+	return result, nil
+}
+
+// CreateAPIKey creates an API key with the privileges of the authenticated user, optionally restricted
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-create-api-key.html
+func (s *security) CreateAPIKey(request APIKeyRequest) (*APIKey, error) {
+	return s.createAPIKey("/_security/api_key", request)
+}
+
+// GrantAPIKey creates an API key on behalf of another user, such as the user of a service
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-grant-api-key.html
+func (s *security) GrantAPIKey(grant APIKeyGrant) (*APIKey, error) {
+	return s.createAPIKey("/_security/api_key/grant", grant)
+}
+
+func (s *security) createAPIKey(path string, request interface{}) (*APIKey, error) {
+	body, err := s.client.codec.Marshal(request)
+	if err != nil {
+		return &APIKey{}, err
+	}
+
+	result := &APIKey{}
+	if err := s.client.sendAPIRequest("POST", path, string(body), result); err != nil {
+		return &APIKey{}, err
+	}
+	return result, nil
+}
+
+// InvalidateAPIKey invalidates the API keys matching the filter
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-invalidate-api-key.html
+func (s *security) InvalidateAPIKey(filter APIKeyFilter) (*InvalidatedAPIKeys, error) {
+	body, err := s.client.codec.Marshal(filter)
+	if err != nil {
+		return &InvalidatedAPIKeys{}, err
+	}
+
+	result := &InvalidatedAPIKeys{}
+	if err := s.client.sendAPIRequest("DELETE", "/_security/api_key", string(body), result); err != nil {
+		return &InvalidatedAPIKeys{}, err
+	}
+	return result, nil
+}
+
+// GetAPIKey returns the API keys matching the filter, including the invalidated and expired ones
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-get-api-key.html
+func (s *security) GetAPIKey(filter APIKeyFilter) ([]APIKeyInfo, error) {
+	if len(filter.IDs) > 1 {
+		return []APIKeyInfo{}, fmt.Errorf("a single API key can be retrieved by identifier, %d given", len(filter.IDs))
+	}
+	params := url.Values{}
+	if len(filter.IDs) == 1 {
+		params.Set("id", filter.IDs[0])
+	}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.RealmName != "" {
+		params.Set("realm_name", filter.RealmName)
+	}
+	if filter.Username != "" {
+		params.Set("username", filter.Username)
+	}
+	if filter.Owner {
+		params.Set("owner", strconv.FormatBool(filter.Owner))
+	}
+	path := "/_security/api_key"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var result struct {
+		APIKeys []APIKeyInfo `json:"api_keys"`
+	}
+	if err := s.client.sendAPIRequest("GET", path, "", &result); err != nil {
+		return []APIKeyInfo{}, err
+	}
+	return result.APIKeys, nil
+}
+
+// Authenticate returns the user of the credentials of the client, an error being returned
+// when they are invalid
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-authenticate.html
+func (s *security) Authenticate() (*AuthenticatedUser, error) {
+	result := &AuthenticatedUser{}
+	if err := s.client.sendAPIRequest("GET", "/_security/_authenticate", "", result); err != nil {
+		return &AuthenticatedUser{}, err
+	}
+	return result, nil
+}
+
+// HasPrivileges checks whether the authenticated user has the cluster, index and application
+// privileges listed in body, see PrivilegesResult.Err
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-has-privileges.html
+func (s *security) HasPrivileges(body string) (*PrivilegesResult, error) {
+	result := &PrivilegesResult{}
+	if err := s.client.sendAPIRequest("POST", "/_security/user/_has_privileges", body, result); err != nil {
+		return &PrivilegesResult{}, err
+	}
+	return result, nil
+}
+
+// securityNames returns the path segment listing the names of users or roles, empty for all of them
+func securityNames(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	escaped := make([]string, len(names))
+	for i, name := range names {
+		escaped[i] = escapePath(name)
+	}
+	return "/" + strings.Join(escaped, ",")
+}
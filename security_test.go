@@ -0,0 +1,140 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestSecurityUsersAndRoles(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /_security/role/orders_writer":
+			w.Write([]byte(`{"role":{"created":true}}`))
+		case "PUT /_security/user/orders-service":
+			w.Write([]byte(`{"created":true}`))
+		case "GET /_security/user/orders-service,billing":
+			w.Write([]byte(`{"orders-service":{"username":"orders-service","roles":["orders_writer"],"full_name":"Orders","enabled":true,"metadata":{}}}`))
+		case "DELETE /_security/role/orders_writer":
+			w.Write([]byte(`{"found":true}`))
+		}
+	}))
+	defer server.Close()
+
+	security := elasticsearch.NewClientFromUrl(server.URL).Security()
+	created, err := security.PutRole("orders_writer", elasticsearch.Role{
+		Cluster: []string{"monitor"},
+		Indices: []elasticsearch.IndexPrivileges{{
+			Names:         []string{"orders-*"},
+			Privileges:    []string{"read", "write"},
+			FieldSecurity: &elasticsearch.FieldSecurity{Except: []string{"card"}},
+			Query:         json.RawMessage(`{"term":{"tenant":"acme"}}`),
+		}},
+	})
+	helper.OK(t, err)
+	helper.Assert(t, created.Created, "The role is expected to be created")
+
+	created, err = security.PutUser("orders-service", elasticsearch.User{Username: "ignored", Password: "s3cr3t!", Roles: []string{"orders_writer"}, FullName: "Orders"})
+	helper.OK(t, err)
+	helper.Assert(t, created.Created, "The user is expected to be created")
+
+	users, err := security.GetUsers("orders-service", "billing")
+	helper.OK(t, err)
+	helper.Equals(t, []string{"orders_writer"}, users["orders-service"].Roles)
+	helper.Assert(t, *users["orders-service"].Enabled, "The user is expected to be enabled")
+
+	deleted, err := security.DeleteRole("orders_writer")
+	helper.OK(t, err)
+	helper.Assert(t, deleted.Found, "The role is expected to be found")
+
+	helper.Equals(t, `PUT /_security/role/orders_writer {"cluster":["monitor"],"indices":[{"names":["orders-*"],"privileges":["read","write"],"field_security":{"except":["card"]},"query":{"term":{"tenant":"acme"}}}]}`, requests[0])
+	helper.Equals(t, `PUT /_security/user/orders-service {"password":"s3cr3t!","roles":["orders_writer"],"full_name":"Orders"}`, requests[1])
+}
+
+func TestSecurityAPIKeys(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		authorization = r.Header.Get("Authorization")
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"id":"VuaCfGcBCdbkQm-e5aOx","name":"ingest","expiration":1544068612110,"api_key":"ui2lp2axTNmsyakw9tvNnw"}`))
+		case "GET":
+			w.Write([]byte(`{"api_keys":[{"id":"VuaCfGcBCdbkQm-e5aOx","name":"ingest","creation":1544068612000,"invalidated":false,"username":"orders-service","realm":"native1"}]}`))
+		case "DELETE":
+			w.Write([]byte(`{"invalidated_api_keys":["VuaCfGcBCdbkQm-e5aOx"],"previously_invalidated_api_keys":[],"error_count":0}`))
+		}
+	}))
+	defer server.Close()
+
+	security := elasticsearch.NewClientFromUrl(server.URL).Security()
+	key, err := security.CreateAPIKey(elasticsearch.APIKeyRequest{Name: "ingest", Expiration: "1h", RoleDescriptors: map[string]elasticsearch.Role{
+		"ingest": {Indices: []elasticsearch.IndexPrivileges{{Names: []string{"logs-*"}, Privileges: []string{"create_doc"}}}},
+	}})
+	helper.OK(t, err)
+	// Computed when Elasticsearch doesn't return it, before 7.16
+	helper.Equals(t, "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw==", key.EncodedKey())
+
+	_, err = security.GrantAPIKey(elasticsearch.APIKeyGrant{GrantType: "password", Username: "orders-service", Password: "s3cr3t!", APIKey: elasticsearch.APIKeyRequest{Name: "ingest"}})
+	helper.OK(t, err)
+
+	keyClient := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithSigner(elasticsearch.APIKeySigner(key.EncodedKey())))
+	keys, err := keyClient.Security().GetAPIKey(elasticsearch.APIKeyFilter{Name: "ingest", Owner: true})
+	helper.OK(t, err)
+	helper.Equals(t, "orders-service", keys[0].Username)
+	helper.Equals(t, "ApiKey "+key.EncodedKey(), authorization)
+
+	_, err = security.GetAPIKey(elasticsearch.APIKeyFilter{IDs: []string{"1", "2"}})
+	helper.Assert(t, err != nil, "A single API key is expected to be retrieved by identifier")
+
+	invalidated, err := security.InvalidateAPIKey(elasticsearch.APIKeyFilter{IDs: []string{key.ID}})
+	helper.OK(t, err)
+	helper.Equals(t, []string{key.ID}, invalidated.InvalidatedAPIKeys)
+
+	helper.Equals(t, []string{
+		`POST /_security/api_key {"name":"ingest","expiration":"1h","role_descriptors":{"ingest":{"indices":[{"names":["logs-*"],"privileges":["create_doc"]}]}}}`,
+		`POST /_security/api_key/grant {"grant_type":"password","username":"orders-service","password":"s3cr3t!","api_key":{"name":"ingest"}}`,
+		`GET /_security/api_key?name=ingest&owner=true `,
+		`DELETE /_security/api_key {"ids":["VuaCfGcBCdbkQm-e5aOx"]}`,
+	}, requests)
+}
+
+func TestSecurityHasPrivileges(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/_security/_authenticate":
+			w.Write([]byte(`{"username":"orders-service","roles":["orders_writer"],"enabled":true,"authentication_realm":{"name":"native1","type":"native"},"lookup_realm":{"name":"native1","type":"native"},"authentication_type":"realm"}`))
+		case "/_security/user/_has_privileges":
+			w.Write([]byte(`{"username":"orders-service","has_all_requested":false,"cluster":{"monitor":true},"index":{"orders-*":{"read":true,"write":false},"audit":{"read":false}},"application":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	security := elasticsearch.NewClientFromUrl(server.URL).Security()
+	user, err := security.Authenticate()
+	helper.OK(t, err)
+	helper.Equals(t, "native", user.AuthenticationRealm.Type)
+
+	privileges, err := security.HasPrivileges(`{"cluster":["monitor"],"index":[{"names":["orders-*"],"privileges":["read","write"]},{"names":["audit"],"privileges":["read"]}]}`)
+	helper.OK(t, err)
+	err = privileges.Err()
+	missing, ok := err.(*elasticsearch.MissingPrivilegesError)
+	helper.Assert(t, ok, "A missing privileges error is expected, got %v", err)
+	helper.Equals(t, []string{"index:audit:read", "index:orders-*:write"}, missing.Missing)
+	helper.Equals(t, "user orders-service is missing privileges: index:audit:read, index:orders-*:write", err.Error())
+	helper.Equals(t, "POST /_security/user/_has_privileges", requests[1])
+}
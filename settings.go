@@ -0,0 +1,172 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The refresh interval of an index whose refresh_interval isn't set
+const defaultRefreshInterval = time.Second
+
+// Analysis represents the analysis settings of an index, its components being keyed by name
+type Analysis struct {
+	Analyzer   map[string]AnalysisComponent `json:"analyzer,omitempty"`
+	Tokenizer  map[string]AnalysisComponent `json:"tokenizer,omitempty"`
+	Filter     map[string]AnalysisComponent `json:"filter,omitempty"`
+	CharFilter map[string]AnalysisComponent `json:"char_filter,omitempty"`
+	Normalizer map[string]AnalysisComponent `json:"normalizer,omitempty"`
+}
+
+// AnalysisComponent represents the parameters of a custom analyzer, tokenizer or filter,
+// such as {"type": "custom", "tokenizer": "standard", "filter": ["lowercase"]}
+type AnalysisComponent map[string]interface{}
+
+// Type returns the type of the component
+func (a AnalysisComponent) Type() string {
+	componentType, _ := a["type"].(string)
+	return componentType
+}
+
+// Setting returns an index setting by its path, such as "index.number_of_shards", whether the
+// settings have been retrieved flat or not
+func (s Settings) Setting(path string) (interface{}, bool) {
+	return lookupSetting(s.Settings, path)
+}
+
+// NumberOfShards returns the number of primary shards of the index, 0 when it isn't set
+func (s Settings) NumberOfShards() int {
+	return s.intSetting("index.number_of_shards")
+}
+
+// NumberOfReplicas returns the number of replicas of each primary shard, 0 when it isn't set
+func (s Settings) NumberOfReplicas() int {
+	return s.intSetting("index.number_of_replicas")
+}
+
+// RefreshInterval returns the refresh interval of the index, 1s when it isn't set and a negative
+// duration when the periodic refreshes are disabled with -1
+func (s Settings) RefreshInterval() (time.Duration, error) {
+	value, found := s.Setting("index.refresh_interval")
+	if !found {
+		return defaultRefreshInterval, nil
+	}
+	return parseTimeValue(fmt.Sprint(value))
+}
+
+// Analysis returns the analysis settings of the index, empty when it has no custom analysis
+func (s Settings) Analysis() (Analysis, error) {
+	analysis := Analysis{}
+	value, found := s.Setting("index.analysis")
+	if !found {
+		return analysis, nil
+	}
+	data, err := json.Marshal(expandSettings(value))
+	if err != nil {
+		return Analysis{}, err
+	}
+	if err := json.Unmarshal(data, &analysis); err != nil {
+		return Analysis{}, err
+	}
+	return analysis, nil
+}
+
+// intSetting returns a numeric setting, returned as a string by Elasticsearch
+func (s Settings) intSetting(path string) int {
+	value, _ := s.Setting(path)
+	switch value := value.(type) {
+	case string:
+		n, _ := strconv.Atoi(value)
+		return n
+	case float64:
+		return int(value)
+	}
+	return 0
+}
+
+// lookupSetting walks the nested settings, the remaining path being also looked up as a flat key
+func lookupSetting(settings map[string]interface{}, path string) (interface{}, bool) {
+	if value, found := settings[path]; found {
+		return value, true
+	}
+	for i := range path {
+		if path[i] != '.' {
+			continue
+		}
+		nested, ok := settings[path[:i]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, found := lookupSetting(nested, path[i+1:]); found {
+			return value, true
+		}
+	}
+	// The nested settings of a flat key, such as index.analysis from index.analysis.analyzer.x.type
+	nested := map[string]interface{}{}
+	for key, value := range settings {
+		if strings.HasPrefix(key, path+".") {
+			nested[strings.TrimPrefix(key, path+".")] = value
+		}
+	}
+	return nested, len(nested) > 0
+}
+
+// expandSettings turns the flat keys of the settings, such as "analyzer.english.type", into nested objects
+func expandSettings(value interface{}) interface{} {
+	settings, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	expanded := map[string]interface{}{}
+	for key, value := range settings {
+		parent := expanded
+		segments := strings.Split(key, ".")
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := parent[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[segment] = child
+			}
+			parent = child
+		}
+		last := segments[len(segments)-1]
+		value = expandSettings(value)
+		existing, isObject := parent[last].(map[string]interface{})
+		children, hasChildren := value.(map[string]interface{})
+		if !isObject || !hasChildren {
+			parent[last] = value
+			continue
+		}
+		for child, value := range children {
+			existing[child] = value
+		}
+	}
+	return expanded
+}
+
+// parseTimeValue parses a time value of Elasticsearch, such as "30s", "500ms" or "-1"
+func parseTimeValue(value string) (time.Duration, error) {
+	if value == "-1" {
+		return -1, nil
+	}
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"nanos", time.Nanosecond}, {"micros", time.Microsecond}, {"ms", time.Millisecond},
+		{"s", time.Second}, {"m", time.Minute}, {"h", time.Hour}, {"d", 24 * time.Hour},
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(value, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSuffix(value, u.suffix), 64)
+		if err != nil {
+			break
+		}
+		return time.Duration(n * float64(u.unit)), nil
+	}
+	return 0, fmt.Errorf("invalid time value %q", value)
+}
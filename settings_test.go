@@ -0,0 +1,67 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestSettingsAccessors(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("flat_settings") == "true" {
+			w.Write([]byte(`{"orders":{"settings":{"index.number_of_shards":"3","index.number_of_replicas":"2","index.refresh_interval":"-1",
+				"index.analysis.analyzer.folding.type":"custom","index.analysis.analyzer.folding.tokenizer":"standard",
+				"index.analysis.analyzer.folding.filter":["lowercase","asciifolding"]}}}`))
+			return
+		}
+		w.Write([]byte(`{"orders":{"settings":{"index":{"number_of_shards":"3","number_of_replicas":"2","refresh_interval":"30s",
+			"analysis":{"analyzer":{"folding":{"type":"custom","tokenizer":"standard","filter":["lowercase","asciifolding"]}}}}}}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	settings, err := client.IndexSettings("orders")
+	helper.OK(t, err)
+	helper.Equals(t, 3, settings.NumberOfShards())
+	helper.Equals(t, 2, settings.NumberOfReplicas())
+	interval, err := settings.RefreshInterval()
+	helper.OK(t, err)
+	helper.Equals(t, 30*time.Second, interval)
+	analysis, err := settings.Analysis()
+	helper.OK(t, err)
+	helper.Equals(t, "custom", analysis.Analyzer["folding"].Type())
+	helper.Equals(t, []interface{}{"lowercase", "asciifolding"}, analysis.Analyzer["folding"]["filter"])
+
+	flat, err := client.IndexSettings("orders", elasticsearch.WithParam("flat_settings", "true"))
+	helper.OK(t, err)
+	helper.Equals(t, 3, flat.NumberOfShards())
+	interval, err = flat.RefreshInterval()
+	helper.OK(t, err)
+	helper.Assert(t, interval < 0, "The refresh interval is expected to be disabled")
+	flatAnalysis, err := flat.Analysis()
+	helper.OK(t, err)
+	helper.Equals(t, analysis, flatAnalysis)
+
+	interval, err = elasticsearch.Settings{}.RefreshInterval()
+	helper.OK(t, err)
+	helper.Equals(t, time.Second, interval)
+}
+
+func TestIndexSettingsNamespaced(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helper.Equals(t, "/staging-orders/_settings", r.URL.Path)
+		w.Write([]byte(`{"staging-orders":{"settings":{"index":{"number_of_shards":"3","number_of_replicas":"2"}}}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithIndexPrefix("staging-"))
+	settings, err := client.IndexSettings("orders")
+	helper.OK(t, err)
+	helper.Equals(t, 3, settings.NumberOfShards())
+	helper.Equals(t, 2, settings.NumberOfReplicas())
+}
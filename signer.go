@@ -0,0 +1,188 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestSigner signs a request right before it's sent, such as AWS Signature V4 or a custom scheme
+type RequestSigner interface {
+	Sign(req *http.Request) error
+}
+
+// WithSigner signs every request with signer
+func WithSigner(signer RequestSigner) ClientOption {
+	return func(c *client) {
+		c.signer = signer
+	}
+}
+
+// AWSSigner signs requests with AWS Signature Version 4, to use IAM authentication
+// with Amazon OpenSearch Service and legacy Amazon Elasticsearch Service domains.
+// https://docs.aws.amazon.com/general/latest/gr/signature-version-4.html
+type AWSSigner struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string // "es" for managed domains, "aoss" for OpenSearch Serverless
+
+	// Now returns the signing time, time.Now when nil
+	Now func() time.Time
+}
+
+// NewAWSSigner creates a signer for a managed domain of the region, using static credentials
+func NewAWSSigner(accessKeyID, secretAccessKey, sessionToken, region string) *AWSSigner {
+	return &AWSSigner{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+		Region:          region,
+		Service:         "es",
+	}
+}
+
+// NewAWSSignerFromEnv creates a signer using the credentials of the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+// The region is read from AWS_REGION when empty.
+func NewAWSSignerFromEnv(region string) *AWSSigner {
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	return NewAWSSigner(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN"), region)
+}
+
+// Sign adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers to the request.
+// The body is read to be hashed, and replaced by an in-memory copy.
+func (s *AWSSigner) Sign(req *http.Request) error {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.ContentLength = int64(len(body))
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalURI(req.URL.EscapedPath()),
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/" + s.Service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return nil
+}
+
+// awsCanonicalURI encodes every segment of the already escaped path a second time,
+// as expected by every AWS service besides S3
+func awsCanonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func awsCanonicalQuery(values map[string][]string) string {
+	pairs := []string{}
+	for key, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, awsURIEncode(key)+"="+awsURIEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte besides the unreserved characters of RFC 3986
+func awsURIEncode(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || b == '-' || b == '_' || b == '.' || b == '~' {
+			builder.WriteByte(b)
+			continue
+		}
+		builder.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+	return builder.String()
+}
+
+func hexSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
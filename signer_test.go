@@ -0,0 +1,25 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+// get-vanilla from the AWS Signature Version 4 test suite
+func TestAWSSigner(t *testing.T) {
+	helper := Test{}
+	signer := elasticsearch.NewAWSSigner("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "", "us-east-1")
+	signer.Service = "service"
+	signer.Now = func() time.Time {
+		return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	}
+
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	helper.OK(t, err)
+	helper.OK(t, signer.Sign(req))
+	helper.Equals(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	helper.Equals(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
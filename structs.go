@@ -1,114 +1,466 @@
 package elasticsearch
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Response represents a boolean response sent back by the search egine
 type Response struct {
+	RawResponse
+
 	Acknowledged bool
-	Error        string
+	Error        *ErrorCause
 	Status       int
 }
 
-// Settings represents the mapping structure of one or several indices
+// ErrorCause represents an error returned by the search engine.
+// Elasticsearch 5+ returns an object, older versions a string which is decoded into Reason.
+type ErrorCause struct {
+	Type      string       `json:"type"`
+	Reason    string       `json:"reason"`
+	Index     string       `json:"index,omitempty"`
+	IndexUUID string       `json:"index_uuid,omitempty"`
+	Shard     string       `json:"shard,omitempty"`
+	RootCause []ErrorCause `json:"root_cause,omitempty"`
+	CausedBy  *ErrorCause  `json:"caused_by,omitempty"`
+}
+
+// UnmarshalJSON decodes both the string and the object forms of an error
+func (e *ErrorCause) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &e.Reason)
+	}
+
+	type errorCause ErrorCause
+	return json.Unmarshal(data, (*errorCause)(e))
+}
+
+func (e *ErrorCause) Error() string {
+	message := e.Reason
+	if e.Type != "" {
+		message = e.Type + ": " + message
+	}
+	if e.CausedBy != nil {
+		message += " (caused by " + e.CausedBy.Error() + ")"
+	}
+	return message
+}
+
+// Settings represents the mapping structure of one or several indices.
+// The settings of an index returned by IndexSettings are read with the typed accessors, such as NumberOfShards.
 type Settings struct {
-	Shards  map[string]interface{} `json:"_shards"`
-	Indices map[string]interface{} `json:"indices"`
+	RawResponse
+
+	Shards   map[string]interface{} `json:"_shards"`
+	Indices  map[string]interface{} `json:"indices"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// Mappings represents the mappings of an index, its fields being navigable with Field
+type Mappings struct {
+	Dynamic    interface{}            `json:"dynamic,omitempty"` // true, false, "strict" or "runtime"
+	Meta       map[string]interface{} `json:"_meta,omitempty"`
+	Properties map[string]Property    `json:"properties,omitempty"`
+	Types      map[string]Mappings    `json:"-"` // the mappings by document type, before Elasticsearch 7
+}
+
+// Property represents the mapping of a field, with its sub-fields for the object and nested
+// fields and its multi-fields, such as the keyword sub-field of a text field
+type Property struct {
+	Type           string              `json:"type,omitempty"` // empty for an object field
+	Analyzer       string              `json:"analyzer,omitempty"`
+	SearchAnalyzer string              `json:"search_analyzer,omitempty"`
+	Normalizer     string              `json:"normalizer,omitempty"`
+	Format         string              `json:"format,omitempty"`
+	Index          *bool               `json:"index,omitempty"`
+	DocValues      *bool               `json:"doc_values,omitempty"`
+	IgnoreAbove    int                 `json:"ignore_above,omitempty"`
+	CopyTo         interface{}         `json:"copy_to,omitempty"` // a field name or a list of field names
+	Properties     map[string]Property `json:"properties,omitempty"`
+	Fields         map[string]Property `json:"fields,omitempty"`
+}
+
+// Field returns the mapping of a field by its path, such as "address.city" for the sub-field of an
+// object field or "title.keyword" for a multi-field
+func (m Mappings) Field(path string) (Property, bool) {
+	return Property{Properties: m.Properties}.field(path)
+}
+
+func (p Property) field(path string) (Property, bool) {
+	name, rest := path, ""
+	if i := strings.Index(path, "."); i >= 0 {
+		name, rest = path[:i], path[i+1:]
+	}
+	// The names of the fields may contain dots when the mapping isn't expanded
+	for _, candidates := range []map[string]Property{p.Properties, p.Fields} {
+		if property, found := candidates[path]; found {
+			return property, true
+		}
+		if property, found := candidates[name]; found && rest != "" {
+			if property, found := property.field(rest); found {
+				return property, true
+			}
+		}
+	}
+	return Property{}, false
 }
 
 // Status represents the status of the search engine
 type Status struct {
+	RawResponse
+
 	TagLine string
 	Version struct {
 		Number         string
+		Distribution   string `json:"distribution"` // "opensearch" for OpenSearch, empty for Elasticsearch
+		BuildFlavor    string `json:"build_flavor"`
 		BuildHash      string `json:"build_hash"`
 		BuildTimestamp string `json:"build_timestamp"`
 		BuildSnapshot  bool   `json:"build_snapshot"`
 		LuceneVersion  string `json:"lucene_version"`
 	}
-	Name   string
-	Status int
-	Ok     bool
+	Name        string
+	ClusterName string `json:"cluster_name"`
+	ClusterUUID string `json:"cluster_uuid"`
+	Status      int
+	Ok          bool
 }
 
 // InsertDocument represents the result of the insert operation of a document
 type InsertDocument struct {
-	Created bool   `json:"created"`
-	Index   string `json:"_index"`
-	Type    string `json:"_type"`
-	ID      string `json:"_id"`
-	Version int    `json:"_version"`
+	RawResponse
+
+	Created     bool        `json:"created"`
+	Result      string      `json:"result"` // created, updated or noop
+	Index       string      `json:"_index"`
+	Type        string      `json:"_type"`
+	ID          string      `json:"_id"`
+	Version     int         `json:"_version"`
+	SeqNo       int64       `json:"_seq_no"`
+	PrimaryTerm int64       `json:"_primary_term"`
+	Shards      *ShardsInfo `json:"_shards,omitempty"`
+}
+
+// UnmarshalJSON decodes the result of the insert, Created being derived from Result on ES 6+
+func (d *InsertDocument) UnmarshalJSON(data []byte) error {
+	type insertDocument InsertDocument
+	if err := json.Unmarshal(data, (*insertDocument)(d)); err != nil {
+		return err
+	}
+	d.Created = d.Created || d.Result == "created"
+	return nil
+}
+
+// Noop reports whether the write didn't change the document
+func (d *InsertDocument) Noop() bool {
+	return d.Result == "noop"
 }
 
 // Document represents a document
 type Document struct {
-	Index   string          `json:"_index"`
-	Type    string          `json:"_type"`
-	ID      string          `json:"_id"`
-	Version int             `json:"_version"`
-	Found   bool            `json:"found"`
-	Source  json.RawMessage `json:"_source"`
+	RawResponse
+
+	Index       string          `json:"_index"`
+	Type        string          `json:"_type"`
+	ID          string          `json:"_id"`
+	Version     int             `json:"_version"`
+	SeqNo       int64           `json:"_seq_no"`
+	PrimaryTerm int64           `json:"_primary_term"`
+	Found       bool            `json:"found"`
+	Source      json.RawMessage `json:"_source"`
+	Result      string          `json:"result,omitempty"`  // deleted or not_found, on delete
+	Shards      *ShardsInfo     `json:"_shards,omitempty"` // on delete
 }
 
 // Bulk represents the result of the Bulk operation
 type Bulk struct {
-	Took   uint64 `json:"took"`
-	Errors bool   `json:"errors"`
-	Items  []struct {
-		Create struct {
-			Index  string `json:"_index"`
-			Type   string `json:"_type"`
-			ID     string `json:"_id"`
-			Status int    `json:"status"`
-			Error  string `json:"error"`
-		} `json:"create"`
-		Index struct {
-			Index   string `json:"_index"`
-			Type    string `json:"_type"`
-			ID      string `json:"_id"`
-			Version int    `json:"_version"`
-			Status  int    `json:"status"`
-			Error   struct {
-				Type       string `json:"status"`
-				Reason     string `json:"reason"`
-				Index_UUID string `json:"index_uuid"`
-				Shard      string `json:"shard"`
-				Index      string `json:"index"`
-			} `json:"error"`
-		} `json:"index"`
-	} `json:"items"`
+	RawResponse
+
+	Took   int64      `json:"took"`
+	Errors bool       `json:"errors"`
+	Items  []BulkItem `json:"items"`
+}
+
+// BulkItem represents the result of an operation of a bulk, whatever its action
+type BulkItem struct {
+	Action      string      `json:"-"` // index, create, update or delete
+	Index       string      `json:"_index"`
+	Type        string      `json:"_type"`
+	ID          string      `json:"_id"`
+	Version     int         `json:"_version"`
+	Result      string      `json:"result"`
+	Status      int         `json:"status"`
+	SeqNo       int64       `json:"_seq_no"`
+	PrimaryTerm int64       `json:"_primary_term"`
+	Shards      *ShardsInfo `json:"_shards,omitempty"`
+	Error       *ErrorCause `json:"error"`
+}
+
+// ShardsInfo represents the number of shards on which a write has been performed
+type ShardsInfo struct {
+	Total      int64 `json:"total"`
+	Successful int64 `json:"successful"`
+	Failed     int64 `json:"failed"`
+}
+
+// UnmarshalJSON decodes an item keyed by its action, such as {"index": {...}}
+func (i *BulkItem) UnmarshalJSON(data []byte) error {
+	type bulkItem BulkItem
+	items := map[string]*bulkItem{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	for action, item := range items {
+		*i = BulkItem(*item)
+		i.Action = action
+	}
+	return nil
+}
+
+// BulkItemError represents a failed operation of a bulk
+type BulkItemError struct {
+	Position int    // position of the action in the bulk payload, from 0
+	Action   string // index, create, update or delete
+	Index    string
+	ID       string
+	Status   int
+	Error    ErrorCause
+}
+
+// BulkResult summarizes the operations of a bulk, whatever their action
+type BulkResult struct {
+	Succeeded    int
+	Failures     []BulkItemError
+	ErrorsByType map[string]int // number of failures by error type, such as version_conflict_engine_exception
+}
+
+// Result walks the items of the bulk, counting the succeeded operations and collecting the failed ones.
+// As for Elasticsearch, the deletion of a missing document isn't a failure.
+func (b *Bulk) Result() BulkResult {
+	result := BulkResult{ErrorsByType: map[string]int{}}
+	for position, item := range b.Items {
+		if item.Error == nil {
+			result.Succeeded++
+			continue
+		}
+		failure := BulkItemError{Position: position, Action: item.Action, Index: item.Index, ID: item.ID, Status: item.Status, Error: *item.Error}
+		result.Failures = append(result.Failures, failure)
+		result.ErrorsByType[failure.Error.Type]++
+	}
+	return result
+}
+
+// String summarizes the result in a line, such as
+// "998 succeeded, 2 failed (mapper_parsing_exception: 1, version_conflict_engine_exception: 1)"
+func (r BulkResult) String() string {
+	line := strconv.Itoa(r.Succeeded) + " succeeded, " + strconv.Itoa(len(r.Failures)) + " failed"
+	if len(r.ErrorsByType) == 0 {
+		return line
+	}
+	types := make([]string, 0, len(r.ErrorsByType))
+	for errorType := range r.ErrorsByType {
+		types = append(types, errorType)
+	}
+	sort.Strings(types)
+	for i, errorType := range types {
+		types[i] = errorType + ": " + strconv.Itoa(r.ErrorsByType[errorType])
+	}
+	return line + " (" + strings.Join(types, ", ") + ")"
+}
+
+// Bulk action types
+const (
+	BulkIndex  = "index"
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkAction represents the action and metadata line of a bulk operation
+type BulkAction struct {
+	Type    string `json:"-"`
+	Index   string `json:"_index,omitempty"`
+	ID      string `json:"_id,omitempty"`
+	Routing string `json:"routing,omitempty"`
+}
+
+// MarshalJSON encodes the metadata under the action type, as expected by the _bulk endpoint
+func (a BulkAction) MarshalJSON() ([]byte, error) {
+	type action BulkAction
+	return json.Marshal(map[string]action{a.Type: action(a)})
 }
 
 // SearchResult represents the result of the search operation
 type SearchResult struct {
-	Took     uint64 `json:"took"`
-	TimedOut bool   `json:"timed_out"`
+	RawResponse
+
+	Took     int64 `json:"took"`
+	TimedOut bool  `json:"timed_out"`
 	Shards   struct {
-		Total      int `json:"total"`
-		Successful int `json:"successful"`
-		Skipped    int `json:"skipped"`
-		Failed     int `json:"failed"`
+		Total      int64 `json:"total"`
+		Successful int64 `json:"successful"`
+		Skipped    int64 `json:"skipped"`
+		Failed     int64 `json:"failed"`
 	} `json:"_shards"`
-	Hits         ResultHits      `json:"hits"`
-	Aggregations json.RawMessage `json:"aggregations"`
+	Hits         ResultHits              `json:"hits"`
+	Aggregations json.RawMessage         `json:"aggregations"`
+	Profile      *SearchProfile          `json:"profile,omitempty"` // set when requested with profile
+	Suggest      map[string][]Suggestion `json:"suggest,omitempty"`
+	PitID        string                  `json:"pit_id,omitempty"` // set when searching a point in time
+
+	// Set in the responses of a multi search, the error being returned for a failing query
+	Status int         `json:"status,omitempty"`
+	Error  *ErrorCause `json:"error,omitempty"`
 }
 
 // ResultHits represents the result of the search hits
 type ResultHits struct {
-	Total struct {
-		Value    int    `json:"value"`
-		Relation string `json:"relation"`
-	} `json:"total"`
-	MaxScore float32 `json:"max_score"`
-	Hits     []Hit   `json:"hits"`
+	Total    TotalHits `json:"total"`
+	MaxScore float32   `json:"max_score"`
+	Hits     []Hit     `json:"hits"`
+}
+
+// TotalHits represents the number of hits matching a search
+type TotalHits struct {
+	Value    int64  `json:"value"`
+	Relation string `json:"relation"`
+}
+
+// UnmarshalJSON decodes both the ES 7+ object and the ES 6 plain integer formats of hits.total
+func (t *TotalHits) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '{' {
+		if string(data) == "null" {
+			return nil
+		}
+		t.Relation = "eq"
+		return json.Unmarshal(data, &t.Value)
+	}
+
+	type totalHits TotalHits
+	return json.Unmarshal(data, (*totalHits)(t))
+}
+
+// Accurate reports whether Value is the exact number of hits, and not a lower bound
+// reached when counting up to the limit set by track_total_hits
+func (t TotalHits) Accurate() bool {
+	return t.Relation != "gte"
 }
 
 type Hit struct {
-	Index     string              `json:"_index"`
-	Type      string              `json:"_type"`
-	ID        string              `json:"_id"`
-	Score     float32             `json:"_score"`
-	Source    json.RawMessage     `json:"_source"`
-	Highlight map[string][]string `json:"highlight,omitempty"`
+	Index          string                     `json:"_index"`
+	Type           string                     `json:"_type"`
+	ID             string                     `json:"_id"`
+	Score          float32                    `json:"_score"`
+	Source         json.RawMessage            `json:"_source"`
+	Highlight      map[string][]string        `json:"highlight,omitempty"`
+	Sort           []interface{}              `json:"sort,omitempty"`
+	SeqNo          *int64                     `json:"_seq_no,omitempty"`
+	PrimaryTerm    *int64                     `json:"_primary_term,omitempty"`
+	MatchedQueries []string                   `json:"matched_queries,omitempty"`
+	Fields         map[string]json.RawMessage `json:"fields,omitempty"`
+	InnerHits      map[string]InnerHits       `json:"inner_hits,omitempty"`
+	Nested         *NestedIdentity            `json:"_nested,omitempty"` // set on the inner hits of a nested field
+}
+
+// NestedIdentity locates a nested object inner hit in the source of its document
+type NestedIdentity struct {
+	Field  string          `json:"field"`
+	Offset int             `json:"offset"`
+	Nested *NestedIdentity `json:"_nested,omitempty"`
+}
+
+// InnerHits represents the hits of a nested, parent/child or collapse inner_hits definition
+type InnerHits struct {
+	Hits ResultHits `json:"hits"`
+}
+
+// SearchProfile represents the timing of the execution of a search on each shard
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-profile.html
+type SearchProfile struct {
+	Shards []ShardProfile `json:"shards"`
+}
+
+// ShardProfile represents the timing of the execution of a search on a shard
+type ShardProfile struct {
+	ID           string         `json:"id"` // [node][index][shard]
+	NodeID       string         `json:"node_id,omitempty"`
+	ShardID      int            `json:"shard_id,omitempty"`
+	Index        string         `json:"index,omitempty"`
+	Cluster      string         `json:"cluster,omitempty"`
+	Searches     []SearchTiming `json:"searches"`
+	Aggregations []ProfileNode  `json:"aggregations"`
+	Fetch        *ProfileNode   `json:"fetch,omitempty"` // Elasticsearch 8+
+}
+
+// SearchTiming represents the timing of the query and the collectors of a search on a shard
+type SearchTiming struct {
+	Query       []ProfileNode      `json:"query"`
+	RewriteTime int64              `json:"rewrite_time"` // in nanoseconds
+	Collector   []CollectorProfile `json:"collector"`
+}
+
+// ProfileNode represents the timing of a Lucene query, an aggregation or the fetch phase,
+// with the time spent by each of its low level methods in Breakdown
+type ProfileNode struct {
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	TimeInNanos int64                  `json:"time_in_nanos"`
+	Breakdown   map[string]int64       `json:"breakdown"`
+	Debug       map[string]interface{} `json:"debug,omitempty"`
+	Children    []ProfileNode          `json:"children,omitempty"`
+}
+
+// CollectorProfile represents the timing of a collector of the hits
+type CollectorProfile struct {
+	Name        string             `json:"name"`
+	Reason      string             `json:"reason"`
+	TimeInNanos int64              `json:"time_in_nanos"`
+	Children    []CollectorProfile `json:"children,omitempty"`
+}
+
+// Time returns the time spent in the node, its children included
+func (n ProfileNode) Time() time.Duration {
+	return time.Duration(n.TimeInNanos)
+}
+
+// QueryTime returns the time spent by the shard to rewrite and execute the queries
+func (p ShardProfile) QueryTime() time.Duration {
+	var total int64
+	for _, search := range p.Searches {
+		total += search.RewriteTime
+		for _, query := range search.Query {
+			total += query.TimeInNanos
+		}
+	}
+	return time.Duration(total)
+}
+
+// Slowest returns the query nodes taking at least threshold on any shard, the slowest first,
+// their children included to find the clauses responsible for a slow query
+func (p *SearchProfile) Slowest(threshold time.Duration) []ProfileNode {
+	slowest := []ProfileNode{}
+	var visit func(nodes []ProfileNode)
+	visit = func(nodes []ProfileNode) {
+		for _, node := range nodes {
+			if node.Time() >= threshold {
+				slowest = append(slowest, node)
+			}
+			visit(node.Children)
+		}
+	}
+	for _, shard := range p.Shards {
+		for _, search := range shard.Searches {
+			visit(search.Query)
+		}
+	}
+
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].TimeInNanos > slowest[j].TimeInNanos })
+	return slowest
 }
 
 // MSearchQuery Multi Search query
@@ -117,23 +469,673 @@ type MSearchQuery struct {
 	Body   string // query related to the declared index
 }
 
+// MSearchHeader represents the header of a multi search query, its String being the Header of MSearchQuery
+type MSearchHeader struct {
+	Index             string `json:"index,omitempty"` // comma-separated indices
+	Preference        string `json:"preference,omitempty"`
+	Routing           string `json:"routing,omitempty"`
+	SearchType        string `json:"search_type,omitempty"`
+	RequestCache      *bool  `json:"request_cache,omitempty"`
+	ExpandWildcards   string `json:"expand_wildcards,omitempty"`
+	IgnoreUnavailable bool   `json:"ignore_unavailable,omitempty"`
+	AllowNoIndices    *bool  `json:"allow_no_indices,omitempty"`
+}
+
+// String returns the header as a JSON line
+func (h MSearchHeader) String() string {
+	header, _ := json.Marshal(h)
+	return string(header)
+}
+
 // MSearchResult Multi search result
 type MSearchResult struct {
+	RawResponse
+
 	Responses []SearchResult `json:"responses"`
 }
 
+// SuggestResult represents the result of the suggest operation
+type SuggestResult struct {
+	RawResponse
+
+	Shards struct {
+		Total      int64 `json:"total"`
+		Successful int64 `json:"successful"`
+		Skipped    int64 `json:"skipped"`
+		Failed     int64 `json:"failed"`
+	} `json:"_shards"`
+	Suggest map[string][]Suggestion `json:"suggest"`
+}
+
+// Suggestion represents the suggestions for a token (term suggester) or the whole text
+type Suggestion struct {
+	Text    string          `json:"text"`
+	Offset  int             `json:"offset"`
+	Length  int             `json:"length"`
+	Options []SuggestOption `json:"options"`
+}
+
+// SuggestOption represents a suggested text.
+// Term and phrase suggesters fill Score, completion suggesters fill the document fields.
+type SuggestOption struct {
+	Text         string              `json:"text"`
+	Score        float32             `json:"score"`
+	Freq         int                 `json:"freq,omitempty"`
+	Highlighted  string              `json:"highlighted,omitempty"`
+	CollateMatch *bool               `json:"collate_match,omitempty"`
+	Index        string              `json:"_index,omitempty"`
+	Type         string              `json:"_type,omitempty"`
+	ID           string              `json:"_id,omitempty"`
+	DocScore     float32             `json:"_score,omitempty"`
+	Source       json.RawMessage     `json:"_source,omitempty"`
+	Contexts     map[string][]string `json:"contexts,omitempty"` // matched categories, geohashes of the geo contexts
+}
+
+// RankEvalResult represents the result of a ranking evaluation
+type RankEvalResult struct {
+	RawResponse
+
+	MetricScore float64                       `json:"metric_score"`
+	Details     map[string]RankEvalQueryScore `json:"details"`
+	Failures    map[string]json.RawMessage    `json:"failures"`
+}
+
+// RankEvalQueryScore represents the evaluation of one of the queries of a ranking evaluation
+type RankEvalQueryScore struct {
+	MetricScore float64 `json:"metric_score"`
+	UnratedDocs []struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	} `json:"unrated_docs"`
+	Hits []struct {
+		Hit struct {
+			Index string  `json:"_index"`
+			ID    string  `json:"_id"`
+			Score float32 `json:"_score"`
+		} `json:"hit"`
+		Rating *int `json:"rating"`
+	} `json:"hits"`
+	MetricDetails map[string]json.RawMessage `json:"metric_details"`
+}
+
+// ExplainResult represents the score explanation of a document
+type ExplainResult struct {
+	RawResponse
+
+	Index       string      `json:"_index"`
+	Type        string      `json:"_type"`
+	ID          string      `json:"_id"`
+	Matched     bool        `json:"matched"`
+	Explanation Explanation `json:"explanation"`
+}
+
+// Explanation represents a node of the scoring explanation tree
+type Explanation struct {
+	Value       float32       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details"`
+}
+
+// ValidateResult represents the result of a query validation
+type ValidateResult struct {
+	RawResponse
+
+	Valid  bool `json:"valid"`
+	Shards struct {
+		Total      int64 `json:"total"`
+		Successful int64 `json:"successful"`
+		Failed     int64 `json:"failed"`
+	} `json:"_shards"`
+	Explanations []struct {
+		Index       string `json:"index"`
+		Shard       int    `json:"shard"`
+		Valid       bool   `json:"valid"`
+		Explanation string `json:"explanation"`
+		Error       string `json:"error"`
+	} `json:"explanations"`
+}
+
+// ParseError returns the first validation error reported by an explanation
+func (v *ValidateResult) ParseError() string {
+	for _, explanation := range v.Explanations {
+		if explanation.Error != "" {
+			return explanation.Error
+		}
+	}
+	return ""
+}
+
+// Alias action types
+const (
+	AliasAdd         = "add"
+	AliasRemove      = "remove"
+	AliasRemoveIndex = "remove_index"
+)
+
+// AliasAction represents an action of an alias update
+type AliasAction struct {
+	Type          string          `json:"-"`
+	Index         string          `json:"index,omitempty"`
+	Alias         string          `json:"alias,omitempty"`
+	Filter        json.RawMessage `json:"filter,omitempty"`
+	Routing       string          `json:"routing,omitempty"`
+	IndexRouting  string          `json:"index_routing,omitempty"`
+	SearchRouting string          `json:"search_routing,omitempty"`
+	IsWriteIndex  *bool           `json:"is_write_index,omitempty"`
+}
+
+// MarshalJSON encodes the action under its type, as expected by the _aliases endpoint
+func (a AliasAction) MarshalJSON() ([]byte, error) {
+	type action AliasAction
+	return json.Marshal(map[string]action{a.Type: action(a)})
+}
+
+// AliasInfo represents the metadata of an alias on an index
+type AliasInfo struct {
+	Filter        json.RawMessage `json:"filter,omitempty"`
+	IndexRouting  string          `json:"index_routing,omitempty"`
+	SearchRouting string          `json:"search_routing,omitempty"`
+	IsWriteIndex  bool            `json:"is_write_index,omitempty"`
+	IsHidden      bool            `json:"is_hidden,omitempty"`
+}
+
+// IndexDefinition represents the complete definition of an index
+type IndexDefinition struct {
+	Aliases  map[string]AliasInfo `json:"aliases"`
+	Mappings json.RawMessage      `json:"mappings"`
+	Settings json.RawMessage      `json:"settings"`
+}
+
 type UpdateByQueryResult struct {
-	Took             int  `json:"took"`
-	TimedOut         bool `json:"timed_out"`
-	Total            int  `json:"total"`
-	Updated          int  `json:"updated"`
-	Deleted          int  `json:"deleted"`
-	Batches          int  `json:"batches"`
-	VersionConflicts int  `json:"version_conflicts"`
-	Noops            int  `json:"noops"`
+	RawResponse
+
+	Took             int64 `json:"took"`
+	TimedOut         bool  `json:"timed_out"`
+	Total            int64 `json:"total"`
+	Updated          int64 `json:"updated"`
+	Deleted          int64 `json:"deleted"`
+	Batches          int64 `json:"batches"`
+	VersionConflicts int64 `json:"version_conflicts"`
+	Noops            int64 `json:"noops"`
 	Retries          struct {
-		Bulk   int `json:"bulk"`
-		Search int `json:"search"`
+		Bulk   int64 `json:"bulk"`
+		Search int64 `json:"search"`
 	} `json:"retries"`
 	Failures []interface{} `json:"failures"`
 }
+
+// DeleteByQueryResult represents the result of the delete by query operation
+type DeleteByQueryResult struct {
+	RawResponse
+
+	Took             int64 `json:"took"`
+	TimedOut         bool  `json:"timed_out"`
+	Total            int64 `json:"total"`
+	Deleted          int64 `json:"deleted"`
+	Batches          int64 `json:"batches"`
+	VersionConflicts int64 `json:"version_conflicts"`
+	Noops            int64 `json:"noops"`
+	Retries          struct {
+		Bulk   int64 `json:"bulk"`
+		Search int64 `json:"search"`
+	} `json:"retries"`
+	Failures []interface{} `json:"failures"`
+	Error    *ErrorCause   `json:"error,omitempty"`
+	Status   int           `json:"status"` // the HTTP status of the response
+}
+
+// StoredScript represents a script stored in the cluster state
+type StoredScript struct {
+	RawResponse
+
+	ID     string `json:"_id"`
+	Found  bool   `json:"found"`
+	Script struct {
+		Lang    string            `json:"lang"`
+		Source  string            `json:"source"`
+		Options map[string]string `json:"options,omitempty"`
+	} `json:"script"`
+}
+
+// PainlessResult represents the result of a painless script, a string by default,
+// a boolean in the filter context and a number in the score context
+type PainlessResult struct {
+	RawResponse
+
+	Result json.RawMessage `json:"result"`
+}
+
+// RenderedTemplate represents a search template rendered with its parameters
+type RenderedTemplate struct {
+	RawResponse
+
+	TemplateOutput json.RawMessage `json:"template_output"`
+}
+
+// NodesHeader represents the summary of the nodes which answered a nodes request
+type NodesHeader struct {
+	Total      int64 `json:"total"`
+	Successful int64 `json:"successful"`
+	Failed     int64 `json:"failed"`
+}
+
+// NodesInfo represents the configuration of the nodes of the cluster
+type NodesInfo struct {
+	RawResponse
+
+	Nodes       NodesHeader         `json:"_nodes"`
+	ClusterName string              `json:"cluster_name"`
+	Info        map[string]NodeInfo `json:"nodes"`
+}
+
+// NodeInfo represents the configuration of a node
+type NodeInfo struct {
+	Name             string   `json:"name"`
+	TransportAddress string   `json:"transport_address"`
+	Host             string   `json:"host"`
+	IP               string   `json:"ip"`
+	Version          string   `json:"version"`
+	Roles            []string `json:"roles"`
+	JVM              struct {
+		PID     int    `json:"pid"`
+		Version string `json:"version"`
+		VMName  string `json:"vm_name"`
+		Mem     struct {
+			HeapInitInBytes int64 `json:"heap_init_in_bytes"`
+			HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+		} `json:"mem"`
+	} `json:"jvm"`
+	ThreadPool map[string]struct {
+		Type      string `json:"type"`
+		Size      int    `json:"size"`
+		QueueSize int    `json:"queue_size"`
+	} `json:"thread_pool"`
+	Transport struct {
+		BoundAddress   []string `json:"bound_address"`
+		PublishAddress string   `json:"publish_address"`
+	} `json:"transport"`
+	Settings json.RawMessage `json:"settings"`
+}
+
+// NodesStats represents the statistics of the nodes of the cluster
+type NodesStats struct {
+	RawResponse
+
+	Nodes       NodesHeader          `json:"_nodes"`
+	ClusterName string               `json:"cluster_name"`
+	Stats       map[string]NodeStats `json:"nodes"`
+}
+
+// NodeStats represents the statistics of a node
+type NodeStats struct {
+	Name      string   `json:"name"`
+	Timestamp int64    `json:"timestamp"`
+	Host      string   `json:"host"`
+	IP        string   `json:"ip"`
+	Roles     []string `json:"roles"`
+	JVM       struct {
+		UptimeInMillis int64 `json:"uptime_in_millis"`
+		Mem            struct {
+			HeapUsedInBytes      int64 `json:"heap_used_in_bytes"`
+			HeapUsedPercent      int   `json:"heap_used_percent"`
+			HeapCommittedInBytes int64 `json:"heap_committed_in_bytes"`
+			HeapMaxInBytes       int64 `json:"heap_max_in_bytes"`
+			NonHeapUsedInBytes   int64 `json:"non_heap_used_in_bytes"`
+		} `json:"mem"`
+		Threads struct {
+			Count     int `json:"count"`
+			PeakCount int `json:"peak_count"`
+		} `json:"threads"`
+		GC struct {
+			Collectors map[string]struct {
+				CollectionCount        int64 `json:"collection_count"`
+				CollectionTimeInMillis int64 `json:"collection_time_in_millis"`
+			} `json:"collectors"`
+		} `json:"gc"`
+	} `json:"jvm"`
+	ThreadPool map[string]struct {
+		Threads   int   `json:"threads"`
+		Queue     int   `json:"queue"`
+		Active    int   `json:"active"`
+		Rejected  int64 `json:"rejected"`
+		Largest   int   `json:"largest"`
+		Completed int64 `json:"completed"`
+	} `json:"thread_pool"`
+	FS struct {
+		Timestamp int64 `json:"timestamp"`
+		Total     struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			FreeInBytes      int64 `json:"free_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+		Data []struct {
+			Path             string `json:"path"`
+			Mount            string `json:"mount"`
+			Type             string `json:"type"`
+			TotalInBytes     int64  `json:"total_in_bytes"`
+			FreeInBytes      int64  `json:"free_in_bytes"`
+			AvailableInBytes int64  `json:"available_in_bytes"`
+		} `json:"data"`
+	} `json:"fs"`
+	Transport struct {
+		ServerOpen    int   `json:"server_open"`
+		RxCount       int64 `json:"rx_count"`
+		RxSizeInBytes int64 `json:"rx_size_in_bytes"`
+		TxCount       int64 `json:"tx_count"`
+		TxSizeInBytes int64 `json:"tx_size_in_bytes"`
+	} `json:"transport"`
+}
+
+// PendingTasks represents the cluster-level changes waiting to be executed by the master
+type PendingTasks struct {
+	RawResponse
+
+	Tasks []struct {
+		InsertOrder       int64  `json:"insert_order"`
+		Priority          string `json:"priority"`
+		Source            string `json:"source"`
+		Executing         bool   `json:"executing"`
+		TimeInQueueMillis int64  `json:"time_in_queue_millis"`
+		TimeInQueue       string `json:"time_in_queue"`
+	} `json:"tasks"`
+}
+
+// ClusterState represents the state of the cluster.
+// The large sections are kept raw, they depend on the requested metrics.
+type ClusterState struct {
+	RawResponse
+
+	ClusterName string `json:"cluster_name"`
+	ClusterUUID string `json:"cluster_uuid"`
+	Version     int64  `json:"version"`
+	StateUUID   string `json:"state_uuid"`
+	MasterNode  string `json:"master_node"`
+	Nodes       map[string]struct {
+		Name             string            `json:"name"`
+		EphemeralID      string            `json:"ephemeral_id"`
+		TransportAddress string            `json:"transport_address"`
+		Attributes       map[string]string `json:"attributes"`
+	} `json:"nodes"`
+	Blocks       json.RawMessage `json:"blocks"`
+	Metadata     json.RawMessage `json:"metadata"`
+	RoutingTable json.RawMessage `json:"routing_table"`
+	RoutingNodes json.RawMessage `json:"routing_nodes"`
+}
+
+// ClusterHealth represents the health of the cluster, or of the requested indices
+type ClusterHealth struct {
+	RawResponse
+
+	ClusterName                 string  `json:"cluster_name"`
+	Status                      string  `json:"status"`
+	TimedOut                    bool    `json:"timed_out"`
+	NumberOfNodes               int     `json:"number_of_nodes"`
+	NumberOfDataNodes           int     `json:"number_of_data_nodes"`
+	ActivePrimaryShards         int     `json:"active_primary_shards"`
+	ActiveShards                int     `json:"active_shards"`
+	RelocatingShards            int     `json:"relocating_shards"`
+	InitializingShards          int     `json:"initializing_shards"`
+	UnassignedShards            int     `json:"unassigned_shards"`
+	DelayedUnassignedShards     int     `json:"delayed_unassigned_shards"`
+	NumberOfPendingTasks        int     `json:"number_of_pending_tasks"`
+	ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
+}
+
+// RolloverResult represents the result of the rollover of an alias, and the conditions which were met
+type RolloverResult struct {
+	RawResponse
+
+	Acknowledged       bool            `json:"acknowledged"`
+	ShardsAcknowledged bool            `json:"shards_acknowledged"`
+	OldIndex           string          `json:"old_index"`
+	NewIndex           string          `json:"new_index"`
+	RolledOver         bool            `json:"rolled_over"`
+	DryRun             bool            `json:"dry_run"`
+	Conditions         map[string]bool `json:"conditions"` // such as "[max_age: 7d]": true
+}
+
+// IndexStats represents the statistics of indices, summed up in All
+type IndexStats struct {
+	RawResponse
+
+	Shards  ShardsInfo                 `json:"_shards"`
+	All     IndexStatsEntry            `json:"_all"`
+	Indices map[string]IndexStatsEntry `json:"indices"`
+}
+
+// IndexStatsEntry represents the statistics of the primary shards of an index, and of all its shards
+type IndexStatsEntry struct {
+	UUID      string     `json:"uuid"`
+	Primaries ShardStats `json:"primaries"`
+	Total     ShardStats `json:"total"`
+}
+
+// ShardStats represents the statistics of a set of shards
+type ShardStats struct {
+	Docs struct {
+		Count   int64 `json:"count"`
+		Deleted int64 `json:"deleted"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Indexing struct {
+		IndexTotal        int64 `json:"index_total"`
+		IndexTimeInMillis int64 `json:"index_time_in_millis"`
+		IndexFailed       int64 `json:"index_failed"`
+		DeleteTotal       int64 `json:"delete_total"`
+	} `json:"indexing"`
+	Search struct {
+		QueryTotal        int64 `json:"query_total"`
+		QueryTimeInMillis int64 `json:"query_time_in_millis"`
+		FetchTotal        int64 `json:"fetch_total"`
+		FetchTimeInMillis int64 `json:"fetch_time_in_millis"`
+		ScrollCurrent     int64 `json:"scroll_current"`
+	} `json:"search"`
+	Refresh struct {
+		Total        int64 `json:"total"`
+		TimeInMillis int64 `json:"total_time_in_millis"`
+	} `json:"refresh"`
+	Merges struct {
+		Total        int64 `json:"total"`
+		TimeInMillis int64 `json:"total_time_in_millis"`
+	} `json:"merges"`
+	Segments struct {
+		Count         int64 `json:"count"`
+		MemoryInBytes int64 `json:"memory_in_bytes"`
+	} `json:"segments"`
+}
+
+// BroadcastResponse represents the result of an operation executed on every shard of indices
+type BroadcastResponse struct {
+	RawResponse
+
+	Shards ShardsInfo `json:"_shards"`
+}
+
+// IndexBlock represents a block restricting the operations allowed on an index
+type IndexBlock string
+
+// The blocks of an index
+const (
+	BlockMetadata IndexBlock = "metadata"  // disables the metadata changes, such as closing the index
+	BlockRead     IndexBlock = "read"      // disables the read operations
+	BlockReadOnly IndexBlock = "read_only" // disables the write operations and the metadata changes
+	BlockWrite    IndexBlock = "write"     // disables the write operations
+)
+
+// IndexBlockResult represents the result of the addition of a block
+type IndexBlockResult struct {
+	RawResponse
+
+	Acknowledged       bool `json:"acknowledged"`
+	ShardsAcknowledged bool `json:"shards_acknowledged"`
+	Indices            []struct {
+		Name    string `json:"name"`
+		Blocked bool   `json:"blocked"`
+	} `json:"indices"`
+}
+
+// IndexSegments represents the Lucene segments of the shards of indices
+type IndexSegments struct {
+	RawResponse
+
+	Shards  ShardsInfo `json:"_shards"`
+	Indices map[string]struct {
+		Shards map[string][]ShardSegments `json:"shards"` // the copies of each shard, by shard number
+	} `json:"indices"`
+}
+
+// ShardSegments represents the segments of a shard copy
+type ShardSegments struct {
+	Routing struct {
+		State   string `json:"state"`
+		Primary bool   `json:"primary"`
+		Node    string `json:"node"`
+	} `json:"routing"`
+	NumCommittedSegments int                `json:"num_committed_segments"`
+	NumSearchSegments    int                `json:"num_search_segments"`
+	Segments             map[string]Segment `json:"segments"`
+}
+
+// Segment represents a Lucene segment
+type Segment struct {
+	Generation    int64  `json:"generation"`
+	NumDocs       int64  `json:"num_docs"`
+	DeletedDocs   int64  `json:"deleted_docs"`
+	SizeInBytes   int64  `json:"size_in_bytes"`
+	MemoryInBytes int64  `json:"memory_in_bytes"`
+	Committed     bool   `json:"committed"`
+	Search        bool   `json:"search"`
+	Version       string `json:"version"`
+	Compound      bool   `json:"compound"`
+}
+
+// IndexRecovery represents the recoveries of the shards of an index
+type IndexRecovery struct {
+	Shards []ShardRecovery `json:"shards"`
+}
+
+// ShardRecovery represents the recovery of a shard copy
+type ShardRecovery struct {
+	ID                int              `json:"id"`
+	Type              string           `json:"type"`  // EMPTY_STORE, EXISTING_STORE, PEER, SNAPSHOT or LOCAL_SHARDS
+	Stage             string           `json:"stage"` // INIT, INDEX, VERIFY_INDEX, TRANSLOG, FINALIZE or DONE
+	Primary           bool             `json:"primary"`
+	StartTimeInMillis int64            `json:"start_time_in_millis"`
+	StopTimeInMillis  int64            `json:"stop_time_in_millis"`
+	TotalTimeInMillis int64            `json:"total_time_in_millis"`
+	Source            RecoveryNode     `json:"source"`
+	Target            RecoveryNode     `json:"target"`
+	Index             RecoveryIndex    `json:"index"`
+	Translog          RecoveryTranslog `json:"translog"`
+}
+
+// RecoveryNode represents the source or target node of a recovery
+type RecoveryNode struct {
+	ID               string `json:"id"`
+	Host             string `json:"host"`
+	TransportAddress string `json:"transport_address"`
+	IP               string `json:"ip"`
+	Name             string `json:"name"`
+}
+
+// RecoveryIndex represents the progress of the files recovery
+type RecoveryIndex struct {
+	Size struct {
+		TotalInBytes     int64  `json:"total_in_bytes"`
+		ReusedInBytes    int64  `json:"reused_in_bytes"`
+		RecoveredInBytes int64  `json:"recovered_in_bytes"`
+		Percent          string `json:"percent"`
+	} `json:"size"`
+	Files struct {
+		Total     int    `json:"total"`
+		Reused    int    `json:"reused"`
+		Recovered int    `json:"recovered"`
+		Percent   string `json:"percent"`
+	} `json:"files"`
+	TotalTimeInMillis int64 `json:"total_time_in_millis"`
+}
+
+// RecoveryTranslog represents the progress of the translog replay
+type RecoveryTranslog struct {
+	Recovered         int64  `json:"recovered"`
+	Total             int64  `json:"total"`
+	Percent           string `json:"percent"`
+	TotalOnStart      int64  `json:"total_on_start"`
+	TotalTimeInMillis int64  `json:"total_time_in_millis"`
+}
+
+// DanglingIndices represents the dangling indices of the cluster
+type DanglingIndices struct {
+	RawResponse
+
+	Nodes           NodesHeader `json:"_nodes"`
+	ClusterName     string      `json:"cluster_name"`
+	DanglingIndices []struct {
+		IndexName          string   `json:"index_name"`
+		IndexUUID          string   `json:"index_uuid"`
+		CreationDateMillis int64    `json:"creation_date_millis"`
+		NodeIDs            []string `json:"node_ids"`
+	} `json:"dangling_indices"`
+}
+
+// DeprecationInfo represents the settings and features in use which are deprecated and will be
+// removed or changed in the next major version
+type DeprecationInfo struct {
+	RawResponse
+
+	ClusterSettings []DeprecationIssue            `json:"cluster_settings"`
+	NodeSettings    []DeprecationIssue            `json:"node_settings"`
+	IndexSettings   map[string][]DeprecationIssue `json:"index_settings"` // by index
+	MLSettings      []DeprecationIssue            `json:"ml_settings"`
+	Templates       map[string][]DeprecationIssue `json:"templates,omitempty"`    // by template, from 8.x
+	ILMPolicies     map[string][]DeprecationIssue `json:"ilm_policies,omitempty"` // by policy, from 8.x
+}
+
+// DeprecationIssue represents a deprecated setting or feature in use
+type DeprecationIssue struct {
+	Level                       string                 `json:"level"` // warning or critical, critical issues blocking the upgrade
+	Message                     string                 `json:"message"`
+	URL                         string                 `json:"url"`
+	Details                     string                 `json:"details"`
+	ResolveDuringRollingUpgrade bool                   `json:"resolve_during_rolling_upgrade"`
+	Meta                        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// Critical returns the critical issues, which must be resolved before upgrading, prefixed by
+// where they were found, such as "cluster", "node", "ml" or "index orders"
+func (d *DeprecationInfo) Critical() map[string][]DeprecationIssue {
+	critical := map[string][]DeprecationIssue{}
+	add := func(location string, issues []DeprecationIssue) {
+		for _, issue := range issues {
+			if issue.Level == "critical" {
+				critical[location] = append(critical[location], issue)
+			}
+		}
+	}
+	add("cluster", d.ClusterSettings)
+	add("node", d.NodeSettings)
+	add("ml", d.MLSettings)
+	for index, issues := range d.IndexSettings {
+		add("index "+index, issues)
+	}
+	for template, issues := range d.Templates {
+		add("template "+template, issues)
+	}
+	for policy, issues := range d.ILMPolicies {
+		add("ilm policy "+policy, issues)
+	}
+	return critical
+}
+
+// RawResponse holds the undecoded body of a response, to access the fields not modeled by the structs
+// without sending the request again. Raw is only set when the client is created with WithRawResponses.
+type RawResponse struct {
+	Raw json.RawMessage `json:"-"`
+}
+
+// setRaw keeps a copy of the body, which may be held by a pooled buffer
+func (r *RawResponse) setRaw(data []byte) {
+	r.Raw = append(json.RawMessage(nil), data...)
+}
@@ -0,0 +1,286 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestAliasActionMarshal(t *testing.T) {
+	helper := Test{}
+	isWriteIndex := true
+	actions := []elasticsearch.AliasAction{
+		{Type: elasticsearch.AliasRemove, Index: `logs-"1"`, Alias: "logs"},
+		{Type: elasticsearch.AliasAdd, Index: "logs-2", Alias: "logs", Filter: json.RawMessage(`{"term":{"user":"kimchy"}}`), SearchRouting: "1,2", IsWriteIndex: &isWriteIndex},
+	}
+
+	body, err := json.Marshal(map[string][]elasticsearch.AliasAction{"actions": actions})
+	helper.OK(t, err)
+	helper.Equals(t, `{"actions":[{"remove":{"index":"logs-\"1\"","alias":"logs"}},{"add":{"index":"logs-2","alias":"logs","filter":{"term":{"user":"kimchy"}},"search_routing":"1,2","is_write_index":true}}]}`, string(body))
+}
+
+func TestDecodeHits(t *testing.T) {
+	type Product struct {
+		Name string
+	}
+
+	helper := Test{}
+	result := &elasticsearch.SearchResult{}
+	err := json.Unmarshal([]byte(`{"hits":{"hits":[{"_id":"1","_source":{"Name":"Jeans"}},{"_id":"2","_source":{"Name":"Polo"}}]}}`), result)
+	helper.OK(t, err)
+
+	products, err := elasticsearch.DecodeHits[Product](result)
+	helper.OK(t, err)
+	helper.Equals(t, []Product{{Name: "Jeans"}, {Name: "Polo"}}, products)
+}
+
+func TestSearchRequestMarshal(t *testing.T) {
+	helper := Test{}
+	size := 0
+	request := elasticsearch.SearchRequest{
+		Query:     json.RawMessage(`{"match":{"Name":"jeans"}}`),
+		Size:      &size,
+		Source:    []string{"Name"},
+		Highlight: &elasticsearch.Highlight{Fields: map[string]elasticsearch.HighlightField{"Name": {}}},
+		Aggs:      elasticsearch.Aggregations{"colors": elasticsearch.TermsAgg("Colors", 5)},
+	}
+
+	body, err := json.Marshal(request)
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"match":{"Name":"jeans"}},"size":0,"_source":["Name"],"highlight":{"fields":{"Name":{}}},"aggs":{"colors":{"terms":{"field":"Colors","size":5}}}}`, string(body))
+}
+
+func TestSearchRequestRescore(t *testing.T) {
+	helper := Test{}
+	weight := 0.7
+	request := elasticsearch.SearchRequest{
+		Query:      json.RawMessage(`{"match":{"Name":"jeans"}}`),
+		Aggs:       elasticsearch.Aggregations{"colors": elasticsearch.TermsAgg("Colors", 5)},
+		PostFilter: json.RawMessage(`{"term":{"Colors":"red"}}`),
+		Rescore: []elasticsearch.Rescore{{
+			WindowSize: 50,
+			Query: elasticsearch.RescoreQuery{
+				RescoreQuery: json.RawMessage(`{"match_phrase":{"Name":"blue jeans"}}`),
+				QueryWeight:  &weight,
+				ScoreMode:    "total",
+			},
+		}},
+		MinScore: 0.5,
+	}
+
+	body, err := json.Marshal(request)
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"match":{"Name":"jeans"}},"aggs":{"colors":{"terms":{"field":"Colors","size":5}}},"post_filter":{"term":{"Colors":"red"}},"rescore":[{"window_size":50,"query":{"rescore_query":{"match_phrase":{"Name":"blue jeans"}},"query_weight":0.7,"score_mode":"total"}}],"min_score":0.5}`, string(body))
+}
+
+func TestHitUnmarshal(t *testing.T) {
+	helper := Test{}
+	hit := elasticsearch.Hit{}
+	err := json.Unmarshal([]byte(`{"_id":"1","_seq_no":5,"_primary_term":1,"sort":[1577836800000,"1"],"matched_queries":["red"],
+		"inner_hits":{"variants":{"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_id":"1#0","_source":{"Size":"M"}}]}}}}`), &hit)
+	helper.OK(t, err)
+	helper.Equals(t, int64(5), *hit.SeqNo)
+	helper.Equals(t, []interface{}{float64(1577836800000), "1"}, hit.Sort)
+	helper.Equals(t, []string{"red"}, hit.MatchedQueries)
+	helper.Equals(t, "1#0", hit.InnerHits["variants"].Hits.Hits[0].ID)
+}
+
+func TestTotalHitsUnmarshal(t *testing.T) {
+	helper := Test{}
+	hits := elasticsearch.ResultHits{}
+	helper.OK(t, json.Unmarshal([]byte(`{"total":{"value":10000,"relation":"gte"}}`), &hits))
+	helper.Equals(t, elasticsearch.TotalHits{Value: 10000, Relation: "gte"}, hits.Total)
+
+	hits = elasticsearch.ResultHits{}
+	helper.OK(t, json.Unmarshal([]byte(`{"total":42}`), &hits))
+	helper.Equals(t, elasticsearch.TotalHits{Value: 42, Relation: "eq"}, hits.Total)
+	helper.Assert(t, hits.Total.Accurate(), "An eq total is accurate")
+
+	// Totals beyond 2^31
+	hits = elasticsearch.ResultHits{}
+	helper.OK(t, json.Unmarshal([]byte(`{"total":{"value":5000000000,"relation":"eq"}}`), &hits))
+	helper.Equals(t, int64(5000000000), hits.Total.Value)
+	hits = elasticsearch.ResultHits{}
+	helper.OK(t, json.Unmarshal([]byte(`{"total":5000000000}`), &hits))
+	helper.Equals(t, int64(5000000000), hits.Total.Value)
+
+	result := elasticsearch.DeleteByQueryResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"took":3000000000,"total":4000000000,"deleted":4000000000}`), &result))
+	helper.Equals(t, int64(4000000000), result.Deleted)
+}
+
+func TestBulkActionMarshal(t *testing.T) {
+	helper := Test{}
+	line, err := json.Marshal(elasticsearch.BulkAction{Type: elasticsearch.BulkIndex, Index: IndexName, ID: "1", Routing: "tenant-1"})
+	helper.OK(t, err)
+	helper.Equals(t, `{"index":{"_index":"test","_id":"1","routing":"tenant-1"}}`, string(line))
+}
+
+func TestErrorCauseUnmarshal(t *testing.T) {
+	helper := Test{}
+	response := elasticsearch.Response{}
+	helper.OK(t, json.Unmarshal([]byte(`{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [test]"}],"type":"index_not_found_exception","reason":"no such index [test]","index":"test"},"status":404}`), &response))
+	helper.Equals(t, "index_not_found_exception", response.Error.Type)
+	helper.Equals(t, "test", response.Error.Index)
+	helper.Equals(t, 1, len(response.Error.RootCause))
+	helper.Equals(t, "index_not_found_exception: no such index [test]", response.Error.Error())
+
+	response = elasticsearch.Response{}
+	helper.OK(t, json.Unmarshal([]byte(`{"error":"IndexMissingException[[test] missing]","status":404}`), &response))
+	helper.Equals(t, "IndexMissingException[[test] missing]", response.Error.Reason)
+
+	response = elasticsearch.Response{}
+	helper.OK(t, json.Unmarshal([]byte(`{"acknowledged":true}`), &response))
+	helper.Assert(t, response.Error == nil, "No error expected")
+}
+
+func TestBulkUnmarshal(t *testing.T) {
+	helper := Test{}
+	bulk := elasticsearch.Bulk{}
+	helper.OK(t, json.Unmarshal([]byte(`{"took":30,"errors":true,"items":[
+		{"index":{"_index":"test","_id":"1","_version":1,"result":"created","_shards":{"total":2,"successful":1,"failed":0},"status":201,"_seq_no":0,"_primary_term":1}},
+		{"delete":{"_index":"test","_id":"2","_version":1,"result":"not_found","status":404,"_seq_no":1,"_primary_term":2}},
+		{"update":{"_index":"test","_id":"3","status":404,"error":{"type":"document_missing_exception","reason":"[3]: document missing","index":"test"}}}
+	]}`), &bulk))
+	helper.Equals(t, 3, len(bulk.Items))
+	helper.Equals(t, elasticsearch.BulkIndex, bulk.Items[0].Action)
+	helper.Equals(t, "created", bulk.Items[0].Result)
+	helper.Equals(t, int64(1), bulk.Items[0].Shards.Successful)
+	helper.Equals(t, elasticsearch.BulkDelete, bulk.Items[1].Action)
+	helper.Equals(t, int64(2), bulk.Items[1].PrimaryTerm)
+	helper.Equals(t, elasticsearch.BulkUpdate, bulk.Items[2].Action)
+	helper.Equals(t, "document_missing_exception", bulk.Items[2].Error.Type)
+}
+
+func TestBulkResult(t *testing.T) {
+	helper := Test{}
+	bulk := elasticsearch.Bulk{}
+	helper.OK(t, json.Unmarshal([]byte(`{"took":30,"errors":true,"items":[
+		{"index":{"_index":"test","_id":"1","result":"created","status":201}},
+		{"delete":{"_index":"test","_id":"2","result":"not_found","status":404}},
+		{"update":{"_index":"test","_id":"3","status":404,"error":{"type":"document_missing_exception","reason":"[3]: document missing"}}},
+		{"index":{"_index":"test","_id":"4","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [price]"}}},
+		{"create":{"_index":"test","_id":"5","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[5]: version conflict"}}},
+		{"index":{"_index":"test","_id":"6","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [date]"}}}
+	]}`), &bulk))
+
+	result := bulk.Result()
+	helper.Equals(t, 2, result.Succeeded)
+	helper.Equals(t, 4, len(result.Failures))
+	helper.Equals(t, elasticsearch.BulkItemError{Position: 2, Action: elasticsearch.BulkUpdate, Index: "test", ID: "3", Status: 404,
+		Error: elasticsearch.ErrorCause{Type: "document_missing_exception", Reason: "[3]: document missing"}}, result.Failures[0])
+	helper.Equals(t, map[string]int{"document_missing_exception": 1, "mapper_parsing_exception": 2, "version_conflict_engine_exception": 1}, result.ErrorsByType)
+	helper.Equals(t, "2 succeeded, 4 failed (document_missing_exception: 1, mapper_parsing_exception: 2, version_conflict_engine_exception: 1)", result.String())
+	helper.Equals(t, "0 succeeded, 0 failed", (&elasticsearch.Bulk{}).Result().String())
+}
+
+func TestInsertDocumentUnmarshal(t *testing.T) {
+	helper := Test{}
+	insert := elasticsearch.InsertDocument{}
+	helper.OK(t, json.Unmarshal([]byte(`{"_index":"test","_id":"1","_version":1,"result":"created","_shards":{"total":2,"successful":2,"failed":0},"_seq_no":0,"_primary_term":1}`), &insert))
+	helper.Assert(t, insert.Created, "Created must be derived from the result")
+	helper.Equals(t, int64(2), insert.Shards.Successful)
+
+	insert = elasticsearch.InsertDocument{}
+	helper.OK(t, json.Unmarshal([]byte(`{"_index":"test","_id":"1","_version":2,"result":"noop"}`), &insert))
+	helper.Assert(t, insert.Noop() && !insert.Created, "The write is a noop")
+}
+
+func TestIndexDefinitionUnmarshal(t *testing.T) {
+	helper := Test{}
+	indices := map[string]elasticsearch.IndexDefinition{}
+	helper.OK(t, json.Unmarshal([]byte(`{"logs-1":{"aliases":{"logs":{"is_write_index":true}},"mappings":{"properties":{"message":{"type":"text"}}},"settings":{"index":{"number_of_shards":"1"}}}}`), &indices))
+	helper.Assert(t, indices["logs-1"].Aliases["logs"].IsWriteIndex, "logs must be the write alias")
+	helper.Equals(t, `{"properties":{"message":{"type":"text"}}}`, string(indices["logs-1"].Mappings))
+	helper.Equals(t, `{"index":{"number_of_shards":"1"}}`, string(indices["logs-1"].Settings))
+}
+
+func TestIndexRecoveryUnmarshal(t *testing.T) {
+	helper := Test{}
+	recoveries := map[string]elasticsearch.IndexRecovery{}
+	helper.OK(t, json.Unmarshal([]byte(`{"logs-1":{"shards":[{"id":0,"type":"PEER","stage":"INDEX","primary":false,
+		"source":{"id":"a","name":"node-1"},"target":{"id":"b","name":"node-2"},
+		"index":{"size":{"total_in_bytes":1000,"recovered_in_bytes":250,"percent":"25.0%"},"files":{"total":4,"recovered":1,"percent":"25.0%"}},
+		"translog":{"recovered":0,"total":-1,"percent":"-1.0%"}}]}}`), &recoveries))
+	shard := recoveries["logs-1"].Shards[0]
+	helper.Equals(t, "INDEX", shard.Stage)
+	helper.Equals(t, "node-2", shard.Target.Name)
+	helper.Equals(t, int64(250), shard.Index.Size.RecoveredInBytes)
+	helper.Equals(t, "25.0%", shard.Index.Files.Percent)
+}
+
+func TestSearchProfileUnmarshal(t *testing.T) {
+	helper := Test{}
+	body, err := json.Marshal(elasticsearch.SearchRequest{Query: json.RawMessage(`{"match_all":{}}`), Profile: true})
+	helper.OK(t, err)
+	helper.Equals(t, `{"query":{"match_all":{}},"profile":true}`, string(body))
+
+	result := elasticsearch.SearchResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"hits":{"hits":[]},"profile":{"shards":[{"id":"[node][logs][0]","searches":[{
+		"query":[{"type":"BooleanQuery","description":"+name:shirt +color:red","time_in_nanos":5000000,"breakdown":{"score":1000,"build_scorer":2000},
+			"children":[{"type":"TermQuery","description":"name:shirt","time_in_nanos":4000000,"breakdown":{"score":500}},
+				{"type":"TermQuery","description":"color:red","time_in_nanos":1000000,"breakdown":{"score":500}}]}],
+		"rewrite_time":200000,
+		"collector":[{"name":"SimpleTopScoreDocCollector","reason":"search_top_hits","time_in_nanos":300000}]}],
+		"aggregations":[]}]}}`), &result))
+
+	shard := result.Profile.Shards[0]
+	helper.Equals(t, "[node][logs][0]", shard.ID)
+	helper.Equals(t, int64(2000), shard.Searches[0].Query[0].Breakdown["build_scorer"])
+	helper.Equals(t, "search_top_hits", shard.Searches[0].Collector[0].Reason)
+	helper.Equals(t, 5200*time.Microsecond, shard.QueryTime())
+
+	slowest := result.Profile.Slowest(2 * time.Millisecond)
+	helper.Equals(t, 2, len(slowest))
+	helper.Equals(t, "BooleanQuery", slowest[0].Type)
+	helper.Equals(t, "name:shirt", slowest[1].Description)
+}
+
+func TestCollapse(t *testing.T) {
+	type Product struct {
+		Name string
+	}
+
+	helper := Test{}
+	size := 2
+	body, err := json.Marshal(elasticsearch.SearchRequest{
+		Collapse: &elasticsearch.Collapse{
+			Field:     "family",
+			InnerHits: []elasticsearch.InnerHitsRequest{{Name: "cheapest", Size: &size, Sort: []interface{}{map[string]string{"price": "asc"}}}},
+		},
+	})
+	helper.OK(t, err)
+	helper.Equals(t, `{"collapse":{"field":"family","inner_hits":[{"name":"cheapest","size":2,"sort":[{"price":"asc"}]}]}}`, string(body))
+
+	result := elasticsearch.SearchResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"hits":{"total":{"value":3,"relation":"eq"},"hits":[{"_id":"1","_source":{"Name":"shirt"},"fields":{"family":["tops"]},
+		"inner_hits":{"cheapest":{"hits":{"total":{"value":2,"relation":"eq"},"hits":[{"_id":"2","_source":{"Name":"tee"}},{"_id":"3","_source":{"Name":"tank"}}]}},
+			"variants":{"hits":{"hits":[{"_id":"1","_nested":{"field":"variants","offset":1},"_source":{"Name":"red"}}]}}}}]}}`), &result))
+
+	hit := result.Hits.Hits[0]
+	helper.Equals(t, json.RawMessage(`["tops"]`), hit.Fields["family"])
+	family, err := elasticsearch.DecodeInnerHits[Product](hit, "cheapest")
+	helper.OK(t, err)
+	helper.Equals(t, []string{"tee", "tank"}, []string{family[0].Name, family[1].Name})
+	helper.Equals(t, int64(2), hit.InnerHits["cheapest"].Hits.Total.Value)
+	helper.Equals(t, 1, hit.InnerHits["variants"].Hits.Hits[0].Nested.Offset)
+
+	missing, err := elasticsearch.DecodeInnerHits[Product](hit, "missing")
+	helper.OK(t, err)
+	helper.Equals(t, 0, len(missing))
+}
+
+func TestDeprecationInfoCritical(t *testing.T) {
+	helper := Test{}
+	info := elasticsearch.DeprecationInfo{}
+	err := json.Unmarshal([]byte(`{"cluster_settings":[{"level":"critical","message":"Cluster name cannot contain ':'","url":"https://ela.st/x","details":"This cluster is named [mycompany:logging]"}],
+		"node_settings":[],"index_settings":{"logs":[{"level":"warning","message":"Deprecated setting"},{"level":"critical","message":"Index created before 7.0"}]},"ml_settings":[]}`), &info)
+	helper.OK(t, err)
+
+	critical := info.Critical()
+	helper.Equals(t, 2, len(critical))
+	helper.Equals(t, "Cluster name cannot contain ':'", critical["cluster"][0].Message)
+	helper.Equals(t, "Index created before 7.0", critical["index logs"][0].Message)
+}
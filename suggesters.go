@@ -0,0 +1,161 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Suggester represents a suggester of a search request, built with the *Suggester functions
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html
+type Suggester struct {
+	kind    string
+	text    string
+	textKey string // text by default, prefix for a completion suggester
+	params  map[string]interface{}
+}
+
+// Suggesters represents the named suggesters of a search request, marshaled as the "suggest" object.
+// The body of Suggest is their JSON encoding.
+type Suggesters map[string]*Suggester
+
+// NewSuggester creates a suggester of any kind for text, for the ones without a dedicated builder
+func NewSuggester(kind, text string) *Suggester {
+	return &Suggester{kind: kind, text: text, params: map[string]interface{}{}}
+}
+
+// TermSuggester creates a term suggester, suggesting a correction for each misspelled term of text
+// from the terms of field
+func TermSuggester(text, field string) *Suggester {
+	return NewSuggester("term", text).Param("field", field)
+}
+
+// PhraseSuggester creates a phrase suggester, suggesting a corrected version of the whole text ("did you mean")
+// from the terms of field, usually a shingle field
+func PhraseSuggester(text, field string) *Suggester {
+	return NewSuggester("phrase", text).Param("field", field)
+}
+
+// CompletionSuggester creates a completion suggester, suggesting as you type the documents whose completion
+// field starts with prefix. The suggestions are restricted and boosted by their contexts with Context.
+func CompletionSuggester(prefix, field string) *Suggester {
+	suggester := NewSuggester("completion", prefix).Param("field", field)
+	suggester.textKey = "prefix"
+	return suggester
+}
+
+// Fuzzy accepts the prefixes of a completion suggester with up to fuzziness typos, such as 1 or "AUTO"
+func (s *Suggester) Fuzzy(fuzziness interface{}) *Suggester {
+	return s.Param("fuzzy", map[string]interface{}{"fuzziness": fuzziness})
+}
+
+// SkipDuplicates removes the options of a completion suggester with the same text
+func (s *Suggester) SkipDuplicates() *Suggester {
+	return s.Param("skip_duplicates", true)
+}
+
+// Context restricts the options of a completion suggester to the documents matching one of the contexts
+// of the context named name in the mapping, such as the stores or the countries of a segmented autocomplete
+func (s *Suggester) Context(name string, contexts ...SuggestContext) *Suggester {
+	queries, _ := s.params["contexts"].(map[string][]SuggestContext)
+	if queries == nil {
+		queries = map[string][]SuggestContext{}
+	}
+	queries[name] = append(queries[name], contexts...)
+	return s.Param("contexts", queries)
+}
+
+// Size sets the maximum number of options returned for each term, for the phrase or for the prefix
+func (s *Suggester) Size(size int) *Suggester {
+	return s.Param("size", size)
+}
+
+// SuggestMode sets the terms suggested by a term suggester: missing (only for the terms not in the index,
+// the default), popular (only more frequent terms) or always
+func (s *Suggester) SuggestMode(mode string) *Suggester {
+	return s.Param("suggest_mode", mode)
+}
+
+// Highlight wraps the corrected terms of the options of a phrase suggester in Highlighted
+func (s *Suggester) Highlight(preTag, postTag string) *Suggester {
+	return s.Param("highlight", map[string]string{"pre_tag": preTag, "post_tag": postTag})
+}
+
+// Collate checks each option of a phrase suggester against query, a template where {{suggestion}} is replaced
+// by the option, such as {"match_phrase":{"title":"{{suggestion}}"}}. With prune, the options without match are
+// returned with a false CollateMatch, otherwise they are removed.
+func (s *Suggester) Collate(query json.RawMessage, prune bool) *Suggester {
+	collate := map[string]interface{}{"query": map[string]json.RawMessage{"source": query}}
+	if prune {
+		collate["prune"] = true
+	}
+	return s.Param("collate", collate)
+}
+
+// DirectGenerator adds a generator of the candidate terms of a phrase suggester, from the terms of field
+// with a suggest mode such as always or popular
+func (s *Suggester) DirectGenerator(field, suggestMode string) *Suggester {
+	generators, _ := s.params["direct_generator"].([]map[string]string)
+	generator := map[string]string{"field": field}
+	if suggestMode != "" {
+		generator["suggest_mode"] = suggestMode
+	}
+	return s.Param("direct_generator", append(generators, generator))
+}
+
+// Param sets a parameter of the suggester, such as max_edits, confidence, max_errors or gram_size
+func (s *Suggester) Param(key string, value interface{}) *Suggester {
+	s.params[key] = value
+	return s
+}
+
+// MarshalJSON encodes the suggester as expected in the suggest object of a search request
+func (s *Suggester) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{s.kind: s.params}
+	if s.text != "" {
+		key := s.textKey
+		if key == "" {
+			key = "text"
+		}
+		body[key] = s.text
+	}
+	return json.Marshal(body)
+}
+
+// SuggestContext represents a context query of a completion suggester, built with CategoryContext or GeoContext
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/suggester-context.html
+type SuggestContext struct {
+	Context    interface{}   `json:"context"`
+	Boost      float64       `json:"boost,omitempty"`
+	Prefix     bool          `json:"prefix,omitempty"`     // category: the value is a prefix of the categories
+	Precision  interface{}   `json:"precision,omitempty"`  // geo: geohash length such as 4, or distance such as "10km"
+	Neighbours []interface{} `json:"neighbours,omitempty"` // geo: precisions of the neighbouring cells to also match
+}
+
+// CategoryContext creates a query matching the options of the category value, such as a store
+func CategoryContext(value string) SuggestContext {
+	return SuggestContext{Context: value}
+}
+
+// GeoContext creates a query matching the options located in the geohash cell of a location,
+// the size of the cell being set by Precision
+func GeoContext(lat, lon float64) SuggestContext {
+	return SuggestContext{Context: map[string]float64{"lat": lat, "lon": lon}}
+}
+
+// CorrectedText returns text with each of its terms replaced by its best suggestion, from the result of
+// a term suggester. The terms without suggestion are kept.
+func CorrectedText(text string, suggestions []Suggestion) string {
+	var corrected strings.Builder
+	position := 0
+	for _, suggestion := range suggestions {
+		end := suggestion.Offset + suggestion.Length
+		if len(suggestion.Options) == 0 || suggestion.Offset < position || end > len(text) {
+			continue
+		}
+		corrected.WriteString(text[position:suggestion.Offset])
+		corrected.WriteString(suggestion.Options[0].Text)
+		position = end
+	}
+	corrected.WriteString(text[position:])
+	return corrected.String()
+}
@@ -0,0 +1,67 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestSuggesters(t *testing.T) {
+	helper := Test{}
+	request := elasticsearch.SearchRequest{
+		Size: new(int),
+		Suggest: elasticsearch.Suggesters{
+			"spelling": elasticsearch.TermSuggester("nobel prize", "title").SuggestMode("popular").Size(1),
+			"phrase": elasticsearch.PhraseSuggester("nobel prize", "title.trigram").
+				DirectGenerator("title.trigram", "always").
+				Highlight("[", "]").
+				Collate(json.RawMessage(`{"match_phrase":{"title":"{{suggestion}}"}}`), true),
+		},
+	}
+	body, err := json.Marshal(request)
+	helper.OK(t, err)
+	helper.Equals(t, `{"size":0,"suggest":{"phrase":{"phrase":{"collate":{"prune":true,"query":{"source":{"match_phrase":{"title":"{{suggestion}}"}}}},"direct_generator":[{"field":"title.trigram","suggest_mode":"always"}],"field":"title.trigram","highlight":{"post_tag":"]","pre_tag":"["}},"text":"nobel prize"},"spelling":{"term":{"field":"title","size":1,"suggest_mode":"popular"},"text":"nobel prize"}}}`, string(body))
+
+	result := elasticsearch.SearchResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"hits":{"hits":[]},"suggest":{
+		"spelling":[
+			{"text":"nobel","offset":0,"length":5,"options":[{"text":"noble","score":0.8,"freq":12}]},
+			{"text":"prize","offset":6,"length":5,"options":[]}],
+		"phrase":[{"text":"nobel prize","offset":0,"length":11,"options":[
+			{"text":"noble prize","highlighted":"<em>noble</em> prize","score":0.4,"collate_match":true}]}]}}`), &result))
+
+	spelling := result.Suggest["spelling"]
+	helper.Equals(t, 2, len(spelling))
+	helper.Equals(t, float32(0.8), spelling[0].Options[0].Score)
+	helper.Equals(t, 12, spelling[0].Options[0].Freq)
+	helper.Equals(t, "noble prize", elasticsearch.CorrectedText("nobel prize", spelling))
+
+	option := result.Suggest["phrase"][0].Options[0]
+	helper.Equals(t, "<em>noble</em> prize", option.Highlighted)
+	helper.Assert(t, option.CollateMatch != nil && *option.CollateMatch, "collate match expected")
+}
+
+func TestCompletionSuggesterContexts(t *testing.T) {
+	helper := Test{}
+	precise := elasticsearch.GeoContext(48.85, 2.35)
+	precise.Precision = 4
+	precise.Boost = 2
+	suggesters := elasticsearch.Suggesters{
+		"products": elasticsearch.CompletionSuggester("sho", "suggest").
+			Fuzzy("AUTO").
+			SkipDuplicates().
+			Context("store", elasticsearch.CategoryContext("paris-01"), elasticsearch.CategoryContext("lyon-02")).
+			Context("location", precise),
+	}
+	body, err := json.Marshal(suggesters)
+	helper.OK(t, err)
+	helper.Equals(t, `{"products":{"completion":{"contexts":{"location":[{"context":{"lat":48.85,"lon":2.35},"boost":2,"precision":4}],"store":[{"context":"paris-01"},{"context":"lyon-02"}]},"field":"suggest","fuzzy":{"fuzziness":"AUTO"},"skip_duplicates":true},"prefix":"sho"}}`, string(body))
+
+	result := elasticsearch.SuggestResult{}
+	helper.OK(t, json.Unmarshal([]byte(`{"suggest":{"products":[{"text":"sho","offset":0,"length":3,"options":[
+		{"text":"shoes","_index":"products","_id":"1","_score":2.0,"_source":{"name":"shoes"},"contexts":{"store":["paris-01"]}}]}]}}`), &result))
+	option := result.Suggest["products"][0].Options[0]
+	helper.Equals(t, "1", option.ID)
+	helper.Equals(t, []string{"paris-01"}, option.Contexts["store"])
+}
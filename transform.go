@@ -0,0 +1,167 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Transform exposes the transform APIs, continuously pivoting the documents of source indices into
+// entity-centric summaries, such as the sessions of the users or the aggregates of each customer
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/transform-apis.html
+type Transform interface {
+	Put(id, body string) (*Response, error)
+	Get(ids string) (*Transforms, error)
+	Stats(ids string) (*TransformStats, error)
+	Start(id string) (*Response, error)
+	Stop(id string, waitForCompletion bool) (*Response, error)
+	Preview(body string) (*TransformPreview, error)
+	Delete(id string, force bool) (*Response, error)
+}
+
+// Transforms represents the configuration of transforms
+type Transforms struct {
+	RawResponse
+
+	Count      int `json:"count"`
+	Transforms []struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+		Version     string `json:"version"`
+		CreateTime  int64  `json:"create_time"` // in milliseconds since the epoch
+		Source      struct {
+			Index []string        `json:"index"`
+			Query json.RawMessage `json:"query,omitempty"`
+		} `json:"source"`
+		Dest struct {
+			Index    string `json:"index"`
+			Pipeline string `json:"pipeline,omitempty"`
+		} `json:"dest"`
+		Frequency string          `json:"frequency,omitempty"`
+		Pivot     json.RawMessage `json:"pivot,omitempty"`
+		Latest    json.RawMessage `json:"latest,omitempty"`
+		Sync      json.RawMessage `json:"sync,omitempty"` // set for continuous transforms
+	} `json:"transforms"`
+}
+
+// TransformStats represents the state and the progress of transforms
+type TransformStats struct {
+	RawResponse
+
+	Count      int `json:"count"`
+	Transforms []struct {
+		ID     string `json:"id"`
+		State  string `json:"state"` // started, indexing, stopping, stopped, aborting or failed
+		Reason string `json:"reason,omitempty"`
+		Node   *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"node,omitempty"`
+		Stats struct {
+			PagesProcessed     int64 `json:"pages_processed"`
+			DocumentsProcessed int64 `json:"documents_processed"`
+			DocumentsIndexed   int64 `json:"documents_indexed"`
+			TriggerCount       int64 `json:"trigger_count"`
+			IndexTimeInMillis  int64 `json:"index_time_in_ms"`
+			IndexTotal         int64 `json:"index_total"`
+			IndexFailures      int64 `json:"index_failures"`
+			SearchTimeInMillis int64 `json:"search_time_in_ms"`
+			SearchTotal        int64 `json:"search_total"`
+			SearchFailures     int64 `json:"search_failures"`
+		} `json:"stats"`
+		Checkpointing struct {
+			Last struct {
+				Checkpoint      int64 `json:"checkpoint"`
+				TimestampMillis int64 `json:"timestamp_millis"`
+			} `json:"last"`
+			OperationsBehind      int64 `json:"operations_behind"`
+			ChangesLastDetectedAt int64 `json:"changes_last_detected_at"` // in milliseconds since the epoch
+		} `json:"checkpointing"`
+		Health struct {
+			Status string `json:"status"` // green, yellow or red
+		} `json:"health"`
+	} `json:"transforms"`
+}
+
+// TransformPreview represents the documents a transform would generate, and the index it would create
+type TransformPreview struct {
+	RawResponse
+
+	Preview            []json.RawMessage `json:"preview"`
+	GeneratedDestIndex struct {
+		Mappings json.RawMessage `json:"mappings"`
+		Settings json.RawMessage `json:"settings"`
+		Aliases  json.RawMessage `json:"aliases"`
+	} `json:"generated_dest_index"`
+}
+
+type transform struct {
+	client *client
+}
+
+// Transform returns the client of the transform APIs
+func (c *client) Transform() Transform {
+	return &transform{client: c}
+}
+
+// Put creates a transform, started with Start
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/put-transform.html
+func (t *transform) Put(id, body string) (*Response, error) {
+	return t.client.acknowledge("PUT", "/_transform/"+escapePath(id), body)
+}
+
+// Get returns the configuration of the comma-separated transforms, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/get-transform.html
+func (t *transform) Get(ids string) (*Transforms, error) {
+	result := &Transforms{}
+	if err := t.client.sendAPIRequest("GET", "/_transform/"+transformIDs(ids), "", result); err != nil {
+		return &Transforms{}, err
+	}
+	return result, nil
+}
+
+// Stats returns the state and the progress of the comma-separated transforms, all of them if empty
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/get-transform-stats.html
+func (t *transform) Stats(ids string) (*TransformStats, error) {
+	result := &TransformStats{}
+	if err := t.client.sendAPIRequest("GET", "/_transform/"+transformIDs(ids)+"/_stats", "", result); err != nil {
+		return &TransformStats{}, err
+	}
+	return result, nil
+}
+
+// Start starts a transform, which runs once or continuously depending on its sync configuration
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/start-transform.html
+func (t *transform) Start(id string) (*Response, error) {
+	return t.client.acknowledge("POST", "/_transform/"+escapePath(id)+"/_start", "")
+}
+
+// Stop stops a transform, waiting for it to be stopped when waitForCompletion is true
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/stop-transform.html
+func (t *transform) Stop(id string, waitForCompletion bool) (*Response, error) {
+	return t.client.acknowledge("POST", "/_transform/"+escapePath(id)+"/_stop?wait_for_completion="+strconv.FormatBool(waitForCompletion), "")
+}
+
+// Preview returns the documents the transform described by body would generate, without creating it
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/preview-transform.html
+func (t *transform) Preview(body string) (*TransformPreview, error) {
+	result := &TransformPreview{}
+	if err := t.client.sendAPIRequest("POST", "/_transform/_preview", body, result); err != nil {
+		return &TransformPreview{}, err
+	}
+	return result, nil
+}
+
+// Delete deletes a stopped transform, or a started one when force is true.
+// The destination index is kept.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/delete-transform.html
+func (t *transform) Delete(id string, force bool) (*Response, error) {
+	return t.client.acknowledge("DELETE", "/_transform/"+escapePath(id)+"?force="+strconv.FormatBool(force), "")
+}
+
+// transformIDs returns the path segment of comma-separated transforms, _all if empty
+func transformIDs(ids string) string {
+	if ids == "" {
+		return "_all"
+	}
+	return escapeIndices(ids)
+}
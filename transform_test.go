@@ -0,0 +1,57 @@
+package elasticsearch_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestTransform(t *testing.T) {
+	helper := Test{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch r.URL.Path {
+		case "/_transform/_preview":
+			w.Write([]byte(`{"preview":[{"customer":"acme","total":42.5}],"generated_dest_index":{"mappings":{"properties":{"customer":{"type":"keyword"}}},"settings":{},"aliases":{}}}`))
+		case "/_transform/customers/_stats":
+			w.Write([]byte(`{"count":1,"transforms":[{"id":"customers","state":"indexing","stats":{"documents_processed":1000,"documents_indexed":12},"checkpointing":{"last":{"checkpoint":3},"operations_behind":57},"health":{"status":"green"}}]}`))
+		default:
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer server.Close()
+
+	transform := elasticsearch.NewClientFromUrl(server.URL).Transform()
+	definition := `{"source":{"index":["orders"]},"dest":{"index":"customers"},"pivot":{"group_by":{"customer":{"terms":{"field":"customer"}}},"aggregations":{"total":{"sum":{"field":"price"}}}}}`
+	preview, err := transform.Preview(definition)
+	helper.OK(t, err)
+	helper.Equals(t, `{"customer":"acme","total":42.5}`, string(preview.Preview[0]))
+
+	created, err := transform.Put("customers", definition)
+	helper.OK(t, err)
+	helper.Assert(t, created.Acknowledged, "The transform is expected to be created")
+
+	_, err = transform.Start("customers")
+	helper.OK(t, err)
+
+	stats, err := transform.Stats("customers")
+	helper.OK(t, err)
+	helper.Equals(t, "indexing", stats.Transforms[0].State)
+	helper.Equals(t, int64(57), stats.Transforms[0].Checkpointing.OperationsBehind)
+
+	_, err = transform.Stop("customers", true)
+	helper.OK(t, err)
+
+	helper.Equals(t, []string{
+		"POST /_transform/_preview " + definition,
+		"PUT /_transform/customers " + definition,
+		"POST /_transform/customers/_start ",
+		"GET /_transform/customers/_stats ",
+		"POST /_transform/customers/_stop?wait_for_completion=true ",
+	}, requests)
+}
@@ -0,0 +1,97 @@
+package elasticsearch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The idle connections kept per node by the default transport. http.DefaultTransport keeps 2,
+// reopening connections as soon as more requests are sent concurrently, such as parallel bulk workers.
+const defaultMaxIdleConnsPerHost = 32
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept open per node, 32 by default
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.MaxIdleConnsPerHost = n
+		if t.MaxIdleConns != 0 && t.MaxIdleConns < n {
+			t.MaxIdleConns = n
+		}
+	})
+}
+
+// WithMaxConnsPerHost limits the number of connections per node, the requests waiting for a
+// connection beyond it. There is no limit by default.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.MaxConnsPerHost = n
+	})
+}
+
+// WithIdleConnTimeout closes the connections idle for longer than timeout, 90 seconds by default
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.IdleConnTimeout = timeout
+	})
+}
+
+// WithTLSHandshakeTimeout limits the time of the TLS handshakes, 10 seconds by default
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.TLSHandshakeTimeout = timeout
+	})
+}
+
+// WithProxy sends the requests through the HTTP or HTTPS proxy, instead of the proxy of the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func WithProxy(proxy *url.URL) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.Proxy = http.ProxyURL(proxy)
+	})
+}
+
+// WithDialer opens the connections to Elasticsearch with dial, such as the DialContext method of a
+// net.Dialer with its own timeouts, a SOCKS dialer, or a function dialing the unix socket of a tunnel
+// to a port-forwarded cluster whatever the address
+func WithDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.DialContext = dial
+	})
+}
+
+// withTransportOption customizes the transport of the client, a copy of http.DefaultTransport or
+// of the *http.Transport set with WithTransport. The other transports, such as a Cassette, are not
+// customized.
+func withTransportOption(option func(*http.Transport)) ClientOption {
+	return func(c *client) {
+		c.transportOptions = append(c.transportOptions, option)
+	}
+}
+
+// configureTransport applies the transport options, once every option is applied whatever their
+// order, and creates the HTTP client shared by the requests to reuse their connections
+func (c *client) configureTransport() {
+	defer func() {
+		c.httpClient = &http.Client{Transport: c.transport}
+	}()
+
+	var transport *http.Transport
+	switch base := c.transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	case *http.Transport:
+		if len(c.transportOptions) == 0 {
+			return
+		}
+		transport = base.Clone()
+	default:
+		return
+	}
+	for _, option := range c.transportOptions {
+		option(transport)
+	}
+	c.transport = transport
+}
@@ -0,0 +1,115 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestProxy(t *testing.T) {
+	helper := Test{}
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	helper.OK(t, err)
+	client := elasticsearch.NewClientFromUrl("http://elasticsearch.internal:9200", elasticsearch.WithProxy(proxyURL))
+	_, err = client.DeleteIndex("orders")
+	helper.OK(t, err)
+	helper.Equals(t, []string{"DELETE http://elasticsearch.internal:9200/orders"}, proxied)
+}
+
+func TestDialer(t *testing.T) {
+	helper := Test{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	// A tunnel to the port-forwarded cluster, whatever the address of the client
+	var dialed []string
+	dialer := &net.Dialer{}
+	client := elasticsearch.NewClientFromUrl("http://elasticsearch.internal:9200",
+		elasticsearch.WithDialer(func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+		}))
+	response, err := client.DeleteIndex("orders")
+	helper.OK(t, err)
+	helper.Assert(t, response.Acknowledged, "The request is expected to be sent through the tunnel")
+	helper.Equals(t, []string{"elasticsearch.internal:9200"}, dialed)
+}
+
+func TestConnectionPool(t *testing.T) {
+	helper := Test{}
+	var mutex sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mutex.Lock()
+			connections++
+			mutex.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithMaxConnsPerHost(2), elasticsearch.WithIdleConnTimeout(time.Minute))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.DeleteIndex("orders")
+			helper.OK(t, err)
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 4; i++ {
+		_, err := client.DeleteIndex("orders")
+		helper.OK(t, err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	helper.Assert(t, connections <= 2, "The connections are expected to be limited to 2 and reused, got "+strconv.Itoa(connections))
+}
+
+func TestUserAgent(t *testing.T) {
+	helper := Test{}
+	var userAgent, clientMeta string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		clientMeta = r.Header.Get("x-elastic-client-meta")
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	_, err := elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithApplication("orders-service/2.3.0")).DeleteIndex("orders")
+	helper.OK(t, err)
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	helper.Equals(t, "maximelamure-elasticsearch/0.0.0 ("+runtime.Version()+"; "+runtime.GOOS+"/"+runtime.GOARCH+") orders-service/2.3.0", userAgent)
+	helper.Equals(t, "es=0.0.0,go="+goVersion+",t=0.0.0,hc="+goVersion, clientMeta)
+
+	_, err = elasticsearch.NewClientFromUrl(server.URL, elasticsearch.WithOpenSearch()).DeleteIndex("orders")
+	helper.OK(t, err)
+	helper.Equals(t, "", clientMeta)
+}
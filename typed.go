@@ -0,0 +1,45 @@
+package elasticsearch
+
+// TypedSearchResult represents the result of a search operation whose hits are decoded into T
+type TypedSearchResult[T any] struct {
+	*SearchResult
+	Documents []T // Documents[i] is the decoded source of Hits.Hits[i]
+}
+
+// SearchTyped executes a search query and decodes the source of every hit into T
+func SearchTyped[T any](client Client, indexName, query string, opts ...RequestOption) (*TypedSearchResult[T], error) {
+	result, err := client.Search(indexName, "", query, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := DecodeHitsWith[T](codecOf(client), result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedSearchResult[T]{SearchResult: result, Documents: documents}, nil
+}
+
+// DecodeHits decodes the source of every hit of a search result into T
+func DecodeHits[T any](result *SearchResult) ([]T, error) {
+	return DecodeHitsWith[T](JSONCodec{}, result)
+}
+
+// DecodeHitsWith decodes the source of every hit of a search result into T with codec
+func DecodeHitsWith[T any](codec Codec, result *SearchResult) ([]T, error) {
+	documents := make([]T, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		if err := codec.Unmarshal(hit.Source, &documents[i]); err != nil {
+			return nil, err
+		}
+	}
+	return documents, nil
+}
+
+// DecodeInnerHits decodes the source of the inner hits of a hit into T, such as the other hits of a
+// collapsed group or the matching nested objects. No documents are returned if the hit has no such inner hits.
+func DecodeInnerHits[T any](hit Hit, name string) ([]T, error) {
+	innerHits := hit.InnerHits[name]
+	return DecodeHits[T](&SearchResult{Hits: innerHits.Hits})
+}
@@ -0,0 +1,55 @@
+package elasticsearch
+
+import "errors"
+
+// UpdateRequest represents the body of an update request, with a partial document or a script.
+// A missing document is created with Upsert, with Doc when DocAsUpsert is set, or by the script
+// when ScriptedUpsert is set.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-update.html
+type UpdateRequest struct {
+	Doc    interface{} `json:"doc,omitempty"` // the partial document merged into the existing one
+	Script *Script     `json:"script,omitempty"`
+
+	// Upsert is the document indexed when the document doesn't exist, the script not being run
+	// unless ScriptedUpsert is set
+	Upsert interface{} `json:"upsert,omitempty"`
+
+	// DocAsUpsert indexes Doc when the document doesn't exist, for last-write-wins updates
+	DocAsUpsert bool `json:"doc_as_upsert,omitempty"`
+
+	// ScriptedUpsert runs the script whether the document exists or not, Upsert being the initial
+	// document, such as {"count": 0} for a counter incremented by the script
+	ScriptedUpsert bool `json:"scripted_upsert,omitempty"`
+
+	// DetectNoop is false to write the document even if the partial document doesn't change it
+	DetectNoop *bool `json:"detect_noop,omitempty"`
+}
+
+// validate rejects the requests Elasticsearch would reject
+func (r *UpdateRequest) validate() error {
+	switch {
+	case r.Doc == nil && r.Script == nil:
+		return errors.New("an update requires a partial document or a script")
+	case r.Doc != nil && r.Script != nil:
+		return errors.New("an update can't have both a partial document and a script")
+	case r.DocAsUpsert && r.Doc == nil:
+		return errors.New("doc_as_upsert requires a partial document")
+	case r.ScriptedUpsert && r.Script == nil:
+		return errors.New("scripted_upsert requires a script")
+	}
+	return nil
+}
+
+// UpdateWithRequest updates a document as described by an UpdateRequest. Concurrent updates of the
+// same document are retried by Elasticsearch with WithRetryOnConflict.
+func (c *client) UpdateWithRequest(indexName, identifier string, request *UpdateRequest, opts ...RequestOption) (*InsertDocument, error) {
+	if err := request.validate(); err != nil {
+		return &InsertDocument{}, err
+	}
+	body, err := c.codec.Marshal(request)
+	if err != nil {
+		return &InsertDocument{}, err
+	}
+
+	return c.UpdateDocument(indexName, "", identifier, body, opts...)
+}
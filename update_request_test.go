@@ -0,0 +1,35 @@
+package elasticsearch_test
+
+import (
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestUpdateWithRequest(t *testing.T) {
+	helper := Test{}
+	recorder := elasticsearch.NewRequestRecorder()
+	client := elasticsearch.NewClientFromUrl("http://localhost:9200", elasticsearch.WithDryRun(recorder))
+
+	_, err := client.UpdateWithRequest("pages", "home", &elasticsearch.UpdateRequest{
+		Script:         &elasticsearch.Script{Source: "ctx._source.views += params.count", Params: map[string]interface{}{"count": 1}},
+		Upsert:         map[string]int{"views": 0},
+		ScriptedUpsert: true,
+	}, elasticsearch.WithRetryOnConflict(3))
+	helper.OK(t, err)
+
+	_, err = client.UpdateWithRequest("pages", "home", &elasticsearch.UpdateRequest{
+		Doc:         map[string]string{"title": "Home"},
+		DocAsUpsert: true,
+	})
+	helper.OK(t, err)
+
+	requests := recorder.Requests()
+	helper.Equals(t, "POST /pages/_update/home?retry_on_conflict=3\n"+
+		`{"script":{"source":"ctx._source.views += params.count","params":{"count":1}},"upsert":{"views":0},"scripted_upsert":true}`, requests[0].String())
+	helper.Equals(t, "POST /pages/_update/home\n"+`{"doc":{"title":"Home"},"doc_as_upsert":true}`, requests[1].String())
+
+	_, err = client.UpdateWithRequest("pages", "home", &elasticsearch.UpdateRequest{Upsert: map[string]int{"views": 0}, ScriptedUpsert: true})
+	helper.Assert(t, err != nil, "A scripted upsert without script is expected to be rejected")
+	helper.Equals(t, 2, len(recorder.Requests()))
+}
@@ -0,0 +1,63 @@
+package elasticsearch
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+const modulePath = "github.com/maximelamure/elasticsearch"
+
+// WithApplication appends an application identifier to the User-Agent header, such as
+// "orders-service/2.3.0", so that the cluster administrators can attribute the traffic per service
+func WithApplication(identifier string) ClientOption {
+	return func(c *client) {
+		c.application = identifier
+	}
+}
+
+// userAgent returns the User-Agent header, such as
+// "maximelamure-elasticsearch/1.4.0 (go1.18.3; linux/amd64) orders-service/2.3.0"
+func (c *client) userAgent() string {
+	userAgent := "maximelamure-elasticsearch/" + libraryVersion() + " (" + runtime.Version() + "; " + runtime.GOOS + "/" + runtime.GOARCH + ")"
+	if c.application != "" {
+		userAgent += " " + c.application
+	}
+	return userAgent
+}
+
+// clientMeta returns the x-elastic-client-meta header, describing the client with the versions of the
+// library, of its language and of its HTTP client, such as "es=1.4.0,go=1.18.3,t=1.4.0,hc=1.18.3"
+func clientMeta() string {
+	library := metaVersion(libraryVersion())
+	golang := metaVersion(strings.TrimPrefix(runtime.Version(), "go"))
+	return "es=" + library + ",go=" + golang + ",t=" + library + ",hc=" + golang
+}
+
+// libraryVersion returns the version of the module from the build information of the binary,
+// 0.0.0 when it's unknown such as in its own tests
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "0.0.0"
+	}
+	module := &info.Main
+	for _, dependency := range info.Deps {
+		if dependency.Path == modulePath {
+			module = dependency
+		}
+	}
+	if module.Path != modulePath || module.Version == "" || module.Version == "(devel)" {
+		return "0.0.0"
+	}
+	return strings.TrimPrefix(module.Version, "v")
+}
+
+// metaVersion formats a version as expected by x-elastic-client-meta, a pre-release such as
+// 1.4.0-rc1 or a pseudo-version being reported as 1.4.0p
+func metaVersion(version string) string {
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		return version[:i] + "p"
+	}
+	return version
+}
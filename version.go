@@ -0,0 +1,86 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version represents the version of the search engine
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version number such as 7.17.3 or 8.0.0-SNAPSHOT
+func ParseVersion(number string) (Version, error) {
+	number = strings.SplitN(number, "-", 2)[0]
+	parts := strings.Split(number, ".")
+	if len(parts) < 2 {
+		return Version{}, fmt.Errorf("invalid version number: %q", number)
+	}
+
+	values := [3]int{}
+	for i := 0; i < len(parts) && i < 3; i++ {
+		value, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version number: %q", number)
+		}
+		values[i] = value
+	}
+	return Version{Major: values[0], Minor: values[1], Patch: values[2]}, nil
+}
+
+// AtLeast reports whether the version is greater than or equal to major.minor
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Major > major || v.Major == major && v.Minor >= minor
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// openSearchAPIVersion is the Elasticsearch version whose API is implemented by OpenSearch
+var openSearchAPIVersion = Version{Major: 7, Minor: 10, Patch: 2}
+
+// ServerVersion returns the version of the search engine, cached after the first successful call
+func (c *client) ServerVersion() (Version, error) {
+	info, err := c.Info()
+	if err != nil {
+		return Version{}, err
+	}
+	return ParseVersion(info.Version.Number)
+}
+
+// versionRetryInterval is the time during which apiVersion doesn't get the version again after a failure
+const versionRetryInterval = 30 * time.Second
+
+// apiVersion returns the Elasticsearch version whose endpoints must be used.
+// ok is false when the version cannot be detected, the caller then keeps its default routes.
+// After a failure, the version isn't requested again before versionRetryInterval.
+func (c *client) apiVersion() (Version, bool) {
+	if c.targetsOpenSearch() {
+		return openSearchAPIVersion, true
+	}
+
+	c.infoMutex.Lock()
+	failed := !c.versionFailure.IsZero() && time.Since(c.versionFailure) < versionRetryInterval
+	c.infoMutex.Unlock()
+	if failed {
+		return Version{}, false
+	}
+
+	version, err := c.ServerVersion()
+	if err != nil {
+		c.infoMutex.Lock()
+		c.versionFailure = time.Now()
+		c.infoMutex.Unlock()
+		return Version{}, false
+	}
+	if c.targetsOpenSearch() {
+		return openSearchAPIVersion, true
+	}
+	return version, true
+}
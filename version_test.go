@@ -0,0 +1,65 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximelamure/elasticsearch"
+)
+
+func TestParseVersion(t *testing.T) {
+	helper := Test{}
+	version, err := elasticsearch.ParseVersion("8.0.0-SNAPSHOT")
+	helper.OK(t, err)
+	helper.Equals(t, elasticsearch.Version{Major: 8}, version)
+	helper.Assert(t, version.AtLeast(7, 10), "8.0.0 is after 7.10")
+	helper.Assert(t, !version.AtLeast(8, 1), "8.0.0 is before 8.1")
+
+	_, err = elasticsearch.ParseVersion("")
+	helper.Assert(t, err != nil, "An empty version must be rejected")
+}
+
+func TestVersionRouting(t *testing.T) {
+	helper := Test{}
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"version":{"number":"8.11.1"}}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	client.Document(IndexName, ProductDocumentType, "1")
+	client.Status(IndexName)
+	helper.Equals(t, []string{"/", "/test/_doc/1", "/test/_stats"}, paths)
+}
+
+func TestVersionFailure(t *testing.T) {
+	helper := Test{}
+	paths := []string{}
+	available := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/" && !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"type":"master_not_discovered_exception","reason":null},"status":503}`))
+			return
+		}
+		w.Write([]byte(`{"version":{"number":"8.11.1"},"_id":"1","found":true}`))
+	}))
+	defer server.Close()
+
+	client := elasticsearch.NewClientFromUrl(server.URL)
+	client.Document(IndexName, ProductDocumentType, "1")
+	client.Document(IndexName, ProductDocumentType, "2")
+	helper.Equals(t, []string{"/", "/test/" + ProductDocumentType + "/1", "/test/" + ProductDocumentType + "/2"}, paths)
+
+	info, err := client.Info()
+	helper.OK(t, err)
+	helper.Equals(t, 503, info.Status)
+	available = true
+	info, err = client.Info()
+	helper.OK(t, err)
+	helper.Equals(t, "8.11.1", info.Version.Number)
+}